@@ -282,6 +282,33 @@ type StepStatus struct {
 	FirstExecuteTime metav1.Time `json:"firstExecuteTime,omitempty"`
 	// LastExecuteTime is the last time this step execution.
 	LastExecuteTime metav1.Time `json:"lastExecuteTime,omitempty"`
+	// NextExecuteTime is the computed time that the step will be resumed automatically, such as the
+	// absolute deadline or the next opening of the business calendar window of a suspend step.
+	NextExecuteTime *metav1.Time `json:"nextExecuteTime,omitempty"`
+	// Approvals record who approved or rejected the approval step.
+	Approvals []WorkflowStepApproval `json:"approvals,omitempty"`
+}
+
+// WorkflowStepApprovalAction is the action taken by an approver on an approval step
+type WorkflowStepApprovalAction string
+
+const (
+	// WorkflowStepApprovalApprove means the approver approved the step
+	WorkflowStepApprovalApprove WorkflowStepApprovalAction = "approve"
+	// WorkflowStepApprovalReject means the approver rejected the step
+	WorkflowStepApprovalReject WorkflowStepApprovalAction = "reject"
+)
+
+// WorkflowStepApproval records an approval or a rejection of an approval step
+type WorkflowStepApproval struct {
+	// Approver is the user name or the service account user name of the approver.
+	Approver string `json:"approver"`
+	// Groups are the groups of the approver.
+	Groups []string                   `json:"groups,omitempty"`
+	Action WorkflowStepApprovalAction `json:"action"`
+	// Message is the comment left by the approver.
+	Message string      `json:"message,omitempty"`
+	Time    metav1.Time `json:"time"`
 }
 
 // WorkflowStepStatus record the status of a workflow step, include step status and subStep status
@@ -325,6 +352,38 @@ type AppStatus struct {
 
 	// PolicyStatus records the status of policy
 	PolicyStatus []PolicyStatus `json:"policy,omitempty"`
+
+	// ScheduledRuns record the latest scheduled runs of the workflow
+	ScheduledRuns []WorkflowScheduledRun `json:"scheduledRuns,omitempty"`
+}
+
+// WorkflowScheduledRunPhase is the outcome of a scheduled run of the workflow
+type WorkflowScheduledRunPhase string
+
+const (
+	// WorkflowScheduledRunRunning means the scheduled run is still running
+	WorkflowScheduledRunRunning WorkflowScheduledRunPhase = "running"
+	// WorkflowScheduledRunSucceeded means the workflow of the scheduled run is succeeded
+	WorkflowScheduledRunSucceeded WorkflowScheduledRunPhase = "succeeded"
+	// WorkflowScheduledRunTerminated means the workflow of the scheduled run is terminated
+	WorkflowScheduledRunTerminated WorkflowScheduledRunPhase = "terminated"
+	// WorkflowScheduledRunFailed means the workflow of the scheduled run is stopped since the failed times of
+	// the steps have reached the limit
+	WorkflowScheduledRunFailed WorkflowScheduledRunPhase = "failed"
+)
+
+// WorkflowScheduledRun records a run of the workflow triggered by the schedule
+type WorkflowScheduledRun struct {
+	// ScheduleTime is the scheduled time of the run
+	ScheduleTime metav1.Time `json:"scheduleTime"`
+	// StartTime is the time when the workflow is restarted
+	StartTime metav1.Time `json:"startTime,omitempty"`
+	// EndTime is the time when the workflow is finished
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+	// Phase is the outcome of the run
+	Phase WorkflowScheduledRunPhase `json:"phase"`
+	// Message is the message of the workflow when it's finished
+	Message string `json:"message,omitempty"`
 }
 
 // PolicyStatus records the status of policy
@@ -353,6 +412,12 @@ type WorkflowStep struct {
 
 	Timeout string `json:"timeout,omitempty"`
 
+	Retry *WorkflowStepRetry `json:"retry,omitempty"`
+
+	// Cache skips the execution of the step if its resolved properties and inputs are unchanged since the
+	// last succeeded execution, the outputs of the last execution are restored instead.
+	Cache bool `json:"cache,omitempty"`
+
 	DependsOn []string `json:"dependsOn,omitempty"`
 
 	Inputs StepInputs `json:"inputs,omitempty"`
@@ -365,6 +430,37 @@ type WorkflowStepMeta struct {
 	Alias string `json:"alias,omitempty"`
 }
 
+// WorkflowStepBackoffKind describes how the wait time between the retries of a workflow step grows
+type WorkflowStepBackoffKind string
+
+const (
+	// WorkflowStepBackoffFixed waits the same base delay before each retry
+	WorkflowStepBackoffFixed WorkflowStepBackoffKind = "fixed"
+	// WorkflowStepBackoffExponential doubles the wait time after each retry, starting from the base delay
+	WorkflowStepBackoffExponential WorkflowStepBackoffKind = "exponential"
+)
+
+// WorkflowStepRetry defines the retry policy of a workflow step, it overrides the global retry settings of the controller
+type WorkflowStepRetry struct {
+	// Attempts is the max retry times of the failed step before it is marked as FailedAfterRetries.
+	// Zero means using the global max retry times of the controller.
+	Attempts int `json:"attempts,omitempty"`
+
+	// Backoff is the kind of the backoff between retries, the default is exponential.
+	// +kubebuilder:validation:Enum=fixed;exponential
+	Backoff WorkflowStepBackoffKind `json:"backoff,omitempty"`
+
+	// BaseDelay is the wait time before the first retry, such as 1s or 1m, the default is 1s.
+	BaseDelay string `json:"baseDelay,omitempty"`
+
+	// MaxDelay is the max wait time between retries, such as 5m, the default is the global max failed backoff time of the controller.
+	MaxDelay string `json:"maxDelay,omitempty"`
+
+	// RetryableReasons are the failure reasons of the step that can be retried, such as Execute.
+	// The step fails immediately when it fails with other reasons. Empty means all reasons are retryable.
+	RetryableReasons []string `json:"retryableReasons,omitempty"`
+}
+
 // WorkflowSubStep defines how to execute a workflow subStep.
 type WorkflowSubStep struct {
 	// Name is the unique name of the workflow step.
@@ -381,6 +477,12 @@ type WorkflowSubStep struct {
 
 	Timeout string `json:"timeout,omitempty"`
 
+	Retry *WorkflowStepRetry `json:"retry,omitempty"`
+
+	// Cache skips the execution of the step if its resolved properties and inputs are unchanged since the
+	// last succeeded execution, the outputs of the last execution are restored instead.
+	Cache bool `json:"cache,omitempty"`
+
 	DependsOn []string `json:"dependsOn,omitempty"`
 
 	Inputs StepInputs `json:"inputs,omitempty"`
@@ -402,6 +504,8 @@ type WorkflowStatus struct {
 
 	ContextBackend *corev1.ObjectReference `json:"contextBackend,omitempty"`
 	Steps          []WorkflowStepStatus    `json:"steps,omitempty"`
+	// ExitHandlers record the status of the onSuccess, onFailure and finally steps
+	ExitHandlers []WorkflowStepStatus `json:"exitHandlers,omitempty"`
 
 	StartTime metav1.Time `json:"startTime,omitempty"`
 }
@@ -646,6 +750,8 @@ const (
 	RolloutCondition
 	// ReadyCondition indicates whether whole application processing is successful.
 	ReadyCondition
+	// DriftCondition indicates whether the managed resources drift from the desired state.
+	DriftCondition
 )
 
 var conditions = map[ApplicationConditionType]string{
@@ -656,6 +762,7 @@ var conditions = map[ApplicationConditionType]string{
 	WorkflowCondition: "Workflow",
 	RolloutCondition:  "Rollout",
 	ReadyCondition:    "Ready",
+	DriftCondition:    "Drift",
 }
 
 // String returns the string corresponding to the condition type.
@@ -81,6 +81,13 @@ func (in *AppStatus) DeepCopyInto(out *AppStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ScheduledRuns != nil {
+		in, out := &in.ScheduledRuns, &out.ScheduledRuns
+		*out = make([]WorkflowScheduledRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppStatus.
@@ -616,6 +623,17 @@ func (in *StepStatus) DeepCopyInto(out *StepStatus) {
 	*out = *in
 	in.FirstExecuteTime.DeepCopyInto(&out.FirstExecuteTime)
 	in.LastExecuteTime.DeepCopyInto(&out.LastExecuteTime)
+	if in.NextExecuteTime != nil {
+		in, out := &in.NextExecuteTime, &out.NextExecuteTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Approvals != nil {
+		in, out := &in.Approvals, &out.Approvals
+		*out = make([]WorkflowStepApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepStatus.
@@ -653,6 +671,27 @@ func (in *Terraform) DeepCopy() *Terraform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowScheduledRun) DeepCopyInto(out *WorkflowScheduledRun) {
+	*out = *in
+	in.ScheduleTime.DeepCopyInto(&out.ScheduleTime)
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowScheduledRun.
+func (in *WorkflowScheduledRun) DeepCopy() *WorkflowScheduledRun {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowScheduledRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkflowStatus) DeepCopyInto(out *WorkflowStatus) {
 	*out = *in
@@ -668,6 +707,13 @@ func (in *WorkflowStatus) DeepCopyInto(out *WorkflowStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ExitHandlers != nil {
+		in, out := &in.ExitHandlers, &out.ExitHandlers
+		*out = make([]WorkflowStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.StartTime.DeepCopyInto(&out.StartTime)
 }
 
@@ -701,6 +747,11 @@ func (in *WorkflowStep) DeepCopyInto(out *WorkflowStep) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(WorkflowStepRetry)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DependsOn != nil {
 		in, out := &in.DependsOn, &out.DependsOn
 		*out = make([]string, len(*in))
@@ -728,6 +779,27 @@ func (in *WorkflowStep) DeepCopy() *WorkflowStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowStepApproval) DeepCopyInto(out *WorkflowStepApproval) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowStepApproval.
+func (in *WorkflowStepApproval) DeepCopy() *WorkflowStepApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowStepApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkflowStepMeta) DeepCopyInto(out *WorkflowStepMeta) {
 	*out = *in
@@ -743,6 +815,26 @@ func (in *WorkflowStepMeta) DeepCopy() *WorkflowStepMeta {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowStepRetry) DeepCopyInto(out *WorkflowStepRetry) {
+	*out = *in
+	if in.RetryableReasons != nil {
+		in, out := &in.RetryableReasons, &out.RetryableReasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowStepRetry.
+func (in *WorkflowStepRetry) DeepCopy() *WorkflowStepRetry {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowStepRetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkflowStepStatus) DeepCopyInto(out *WorkflowStepStatus) {
 	*out = *in
@@ -779,6 +871,11 @@ func (in *WorkflowSubStep) DeepCopyInto(out *WorkflowSubStep) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(WorkflowStepRetry)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DependsOn != nil {
 		in, out := &in.DependsOn, &out.DependsOn
 		*out = make([]string, len(*in))
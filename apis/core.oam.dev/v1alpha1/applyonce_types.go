@@ -53,17 +53,19 @@ func (in ApplyOncePolicySpec) FindStrategy(manifest *unstructured.Unstructured)
 		return nil
 	}
 	for _, rule := range in.Rules {
-		match := func(src []string, val string) (found bool) {
-			for _, _val := range src {
-				found = found || _val == val
-			}
-			return val != "" && found
-		}
-		if (match(rule.Selector.CompNames, manifest.GetName()) && match(rule.Selector.ResourceTypes, manifest.GetKind())) ||
-			(rule.Selector.CompNames == nil && match(rule.Selector.ResourceTypes, manifest.GetKind()) ||
-				(rule.Selector.ResourceTypes == nil && match(rule.Selector.CompNames, manifest.GetName()))) {
+		if rule.selector().Match(manifest) {
 			return rule.Strategy
 		}
 	}
 	return nil
 }
+
+// selector returns the selector of the rule. To keep compatible with the legacy apply-once rules, if the operator is
+// not set, the conditions are combined by AND.
+func (in ApplyOncePolicyRule) selector() *ResourcePolicyRuleSelector {
+	selector := in.Selector.DeepCopy()
+	if selector.Operator == "" {
+		selector.Operator = ResourcePolicyRuleSelectorOperatorAnd
+	}
+	return selector
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// DriftDetectionPolicyType refers to the type of drift-detection policy
+	DriftDetectionPolicyType = "drift-detection"
+)
+
+// DriftDetectionMode describes how to handle the drift of the managed resources
+type DriftDetectionMode string
+
+const (
+	// DriftDetectionModeCorrect records the drift and re-applies the desired state, which is the default mode
+	DriftDetectionModeCorrect DriftDetectionMode = "correct"
+	// DriftDetectionModeReport only records the drift, the drifted resources are not re-applied
+	DriftDetectionModeReport DriftDetectionMode = "report"
+	// DriftDetectionModeAlert records the drift with warning events, the drifted resources are not re-applied
+	DriftDetectionModeAlert DriftDetectionMode = "alert"
+)
+
+// DriftDetectionPolicySpec defines the spec of detecting the drift of the managed resources
+type DriftDetectionPolicySpec struct {
+	// Mode is the mode for the resources not selected by the rules, the default is correct
+	// +optional
+	Mode DriftDetectionMode `json:"mode,omitempty"`
+	// Rules defines the modes at resource level, if one resource is selected by multiple rules, the first rule is used
+	// +optional
+	Rules []DriftDetectionPolicyRule `json:"rules,omitempty"`
+}
+
+// DriftDetectionPolicyRule defines a single drift-detection policy rule
+type DriftDetectionPolicyRule struct {
+	Selector ResourcePolicyRuleSelector `json:"selector"`
+	Mode     DriftDetectionMode         `json:"mode"`
+}
+
+// FindMode return the drift detection mode of the target resource
+func (in DriftDetectionPolicySpec) FindMode(manifest *unstructured.Unstructured) DriftDetectionMode {
+	for _, rule := range in.Rules {
+		if rule.Selector.Match(manifest) {
+			return rule.Mode
+		}
+	}
+	if in.Mode == "" {
+		return DriftDetectionModeCorrect
+	}
+	return in.Mode
+}
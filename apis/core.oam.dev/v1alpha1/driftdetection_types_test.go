@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDriftDetectionPolicySpec_FindMode(t *testing.T) {
+	deploy := &unstructured.Unstructured{Object: map[string]interface{}{"kind": "Deployment"}}
+	svc := &unstructured.Unstructured{Object: map[string]interface{}{"kind": "Service"}}
+	testCases := map[string]struct {
+		spec   DriftDetectionPolicySpec
+		input  *unstructured.Unstructured
+		expect DriftDetectionMode
+	}{
+		"default": {
+			spec:   DriftDetectionPolicySpec{},
+			input:  deploy,
+			expect: DriftDetectionModeCorrect,
+		},
+		"mode": {
+			spec:   DriftDetectionPolicySpec{Mode: DriftDetectionModeReport},
+			input:  svc,
+			expect: DriftDetectionModeReport,
+		},
+		"rule match": {
+			spec: DriftDetectionPolicySpec{Mode: DriftDetectionModeReport, Rules: []DriftDetectionPolicyRule{{
+				Selector: ResourcePolicyRuleSelector{ResourceTypes: []string{"Deployment"}},
+				Mode:     DriftDetectionModeAlert,
+			}}},
+			input:  deploy,
+			expect: DriftDetectionModeAlert,
+		},
+		"rule mismatch": {
+			spec: DriftDetectionPolicySpec{Rules: []DriftDetectionPolicyRule{{
+				Selector: ResourcePolicyRuleSelector{ResourceTypes: []string{"Deployment"}},
+				Mode:     DriftDetectionModeAlert,
+			}}},
+			input:  svc,
+			expect: DriftDetectionModeCorrect,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expect, tc.spec.FindMode(tc.input))
+		})
+	}
+}
@@ -61,9 +61,50 @@ type Workflow struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
+	// Parameters are the typed parameters of the workflow when it's invoked by the sub-workflow step of an application
+	Parameters []WorkflowParameter `json:"parameters,omitempty"`
+
 	Steps []common.WorkflowStep `json:"steps,omitempty"`
 }
 
+// WorkflowParameterType is the type of the workflow parameter
+type WorkflowParameterType string
+
+const (
+	// WorkflowParameterTypeString is the string parameter
+	WorkflowParameterTypeString WorkflowParameterType = "string"
+	// WorkflowParameterTypeNumber is the number parameter
+	WorkflowParameterTypeNumber WorkflowParameterType = "number"
+	// WorkflowParameterTypeInteger is the integer parameter
+	WorkflowParameterTypeInteger WorkflowParameterType = "integer"
+	// WorkflowParameterTypeBoolean is the boolean parameter
+	WorkflowParameterTypeBoolean WorkflowParameterType = "boolean"
+	// WorkflowParameterTypeObject is the object parameter
+	WorkflowParameterTypeObject WorkflowParameterType = "object"
+	// WorkflowParameterTypeArray is the array parameter
+	WorkflowParameterTypeArray WorkflowParameterType = "array"
+)
+
+// WorkflowParameter defines a typed parameter of the workflow
+type WorkflowParameter struct {
+	// Name is the name of the parameter, the steps of the workflow refer to it by the input from `parameter.<name>`
+	Name string `json:"name"`
+
+	// Type is the type of the parameter, the value of the parameter is not checked if it's empty
+	// +kubebuilder:validation:Enum=string;number;integer;boolean;object;array
+	Type WorkflowParameterType `json:"type,omitempty"`
+
+	// Required means the parameter must be set if it doesn't have a default value
+	Required bool `json:"required,omitempty"`
+
+	// Default is the default value of the parameter, it can be any JSON value
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Default *runtime.RawExtension `json:"default,omitempty"`
+
+	Description string `json:"description,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 
 // WorkflowList contains a list of Workflow
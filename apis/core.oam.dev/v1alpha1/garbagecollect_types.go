@@ -18,9 +18,6 @@ package v1alpha1
 
 import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/utils/strings/slices"
-
-	"github.com/oam-dev/kubevela/pkg/oam"
 )
 
 const (
@@ -56,42 +53,6 @@ type GarbageCollectPolicyRule struct {
 	Strategy GarbageCollectStrategy     `json:"strategy"`
 }
 
-// ResourcePolicyRuleSelector select the targets of the rule
-// 1) for GarbageCollectPolicyRule
-// if both traitTypes, oamTypes and componentTypes are specified, combination logic is OR
-// if one resource is specified with conflict strategies, strategy as component go first.
-// 2) for ApplyOncePolicyRule only CompNames and ResourceTypes are used
-type ResourcePolicyRuleSelector struct {
-	CompNames        []string `json:"componentNames"`
-	CompTypes        []string `json:"componentTypes"`
-	OAMResourceTypes []string `json:"oamTypes"`
-	TraitTypes       []string `json:"traitTypes"`
-	ResourceTypes    []string `json:"resourceTypes"`
-	ResourceNames    []string `json:"resourceNames"`
-}
-
-// Match check if current rule selector match the target resource
-func (in *ResourcePolicyRuleSelector) Match(manifest *unstructured.Unstructured) bool {
-	var compName, compType, oamType, traitType, resourceType, resourceName string
-	if labels := manifest.GetLabels(); labels != nil {
-		compName = labels[oam.LabelAppComponent]
-		compType = labels[oam.WorkloadTypeLabel]
-		oamType = labels[oam.LabelOAMResourceType]
-		traitType = labels[oam.TraitTypeLabel]
-	}
-	resourceType = manifest.GetKind()
-	resourceName = manifest.GetName()
-	match := func(src []string, val string) (found bool) {
-		return val != "" && slices.Contains(src, val)
-	}
-	return match(in.CompNames, compName) ||
-		match(in.CompTypes, compType) ||
-		match(in.OAMResourceTypes, oamType) ||
-		match(in.TraitTypes, traitType) ||
-		match(in.ResourceTypes, resourceType) ||
-		match(in.ResourceNames, resourceName)
-}
-
 // GarbageCollectStrategy the strategy for target resource to recycle
 type GarbageCollectStrategy string
 
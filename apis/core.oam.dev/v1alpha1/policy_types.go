@@ -27,6 +27,10 @@ const (
 	DebugPolicyType = "debug"
 	// SharedResourcePolicyType refers to the type of shared resource policy
 	SharedResourcePolicyType = "shared-resource"
+	// ReadOnlyPolicyType refers to the type of read-only policy
+	ReadOnlyPolicyType = "read-only"
+	// TakeOverPolicyType refers to the type of take-over policy
+	TakeOverPolicyType = "take-over"
 )
 
 // TopologyPolicySpec defines the spec of topology policy
@@ -77,3 +81,45 @@ func (in SharedResourcePolicySpec) FindStrategy(manifest *unstructured.Unstructu
 	}
 	return false
 }
+
+// ReadOnlyPolicySpec defines the spec of read-only policy
+// The selected resources are tracked and health-checked by the application, but never written or deleted.
+type ReadOnlyPolicySpec struct {
+	Rules []ReadOnlyPolicyRule `json:"rules"`
+}
+
+// ReadOnlyPolicyRule defines the rule for read-only resources
+type ReadOnlyPolicyRule struct {
+	Selector ResourcePolicyRuleSelector `json:"selector"`
+}
+
+// FindStrategy return if the target resource is read-only
+func (in ReadOnlyPolicySpec) FindStrategy(manifest *unstructured.Unstructured) bool {
+	for _, rule := range in.Rules {
+		if rule.Selector.Match(manifest) {
+			return true
+		}
+	}
+	return false
+}
+
+// TakeOverPolicySpec defines the spec of take-over policy
+// The selected resources not managed by any application are adopted by the application.
+type TakeOverPolicySpec struct {
+	Rules []TakeOverPolicyRule `json:"rules"`
+}
+
+// TakeOverPolicyRule defines the rule for taking over resources
+type TakeOverPolicyRule struct {
+	Selector ResourcePolicyRuleSelector `json:"selector"`
+}
+
+// FindStrategy return if the target resource should be taken over
+func (in TakeOverPolicySpec) FindStrategy(manifest *unstructured.Unstructured) bool {
+	for _, rule := range in.Rules {
+		if rule.Selector.Match(manifest) {
+			return true
+		}
+	}
+	return false
+}
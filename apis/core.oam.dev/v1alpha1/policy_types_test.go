@@ -67,3 +67,20 @@ func TestSharedResourcePolicySpec_FindStrategy(t *testing.T) {
 		})
 	}
 }
+
+func TestReadOnlyAndTakeOverPolicySpec_FindStrategy(t *testing.T) {
+	r := require.New(t)
+	selector := ResourcePolicyRuleSelector{CompNames: []string{"legacy"}}
+	matched := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{"app.oam.dev/component": "legacy"},
+		},
+	}}
+	mismatched := &unstructured.Unstructured{Object: map[string]interface{}{"kind": "Deployment"}}
+	readOnly := ReadOnlyPolicySpec{Rules: []ReadOnlyPolicyRule{{Selector: selector}}}
+	r.True(readOnly.FindStrategy(matched))
+	r.False(readOnly.FindStrategy(mismatched))
+	takeOver := TakeOverPolicySpec{Rules: []TakeOverPolicyRule{{Selector: selector}}}
+	r.True(takeOver.FindStrategy(matched))
+	r.False(takeOver.FindStrategy(mismatched))
+}
@@ -52,7 +52,14 @@ var (
 	WorkflowGroupVersionKind = SchemeGroupVersion.WithKind(WorkflowKind)
 )
 
+// ResourceAdmissionPolicy meta
+var (
+	ResourceAdmissionPolicyKind             = "ResourceAdmissionPolicy"
+	ResourceAdmissionPolicyGroupVersionKind = SchemeGroupVersion.WithKind(ResourceAdmissionPolicyKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&Policy{}, &PolicyList{})
 	SchemeBuilder.Register(&Workflow{}, &WorkflowList{})
+	SchemeBuilder.Register(&ResourceAdmissionPolicy{}, &ResourceAdmissionPolicyList{})
 }
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+
+// ResourceAdmissionPolicy defines the rules to validate the resources dispatched by applications before they are
+// applied. The rules are written in CUE and evaluated on every dispatched manifest.
+// +kubebuilder:resource:scope=Cluster,categories={oam},shortName=rap
+// +kubebuilder:printcolumn:name="NAMESPACES",type=string,JSONPath=`.spec.namespaces`
+// +kubebuilder:printcolumn:name="PROJECTS",type=string,JSONPath=`.spec.projects`
+// +kubebuilder:printcolumn:name="AGE",type=date,JSONPath=".metadata.creationTimestamp"
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ResourceAdmissionPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ResourceAdmissionPolicySpec `json:"spec"`
+}
+
+// ResourceAdmissionPolicySpec defines the spec of ResourceAdmissionPolicy
+type ResourceAdmissionPolicySpec struct {
+	// Namespaces select the namespaces of the applications the policy applies to, all the namespaces if empty.
+	// The values support the wildcards like `team-*` and the exclusion like `!vela-system`.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Projects select the projects of the applications the policy applies to, all the applications if empty. The
+	// project is read from the `namespace.oam.dev/project` label of the namespace of the application, instead of the
+	// labels of the application set by its author. The values support the wildcards and the exclusion like Namespaces.
+	// +optional
+	Projects []string `json:"projects,omitempty"`
+	// Rules are the admission rules of the policy
+	Rules []ResourceAdmissionRule `json:"rules"`
+}
+
+// ResourceAdmissionRule defines a rule to validate the dispatched resources.
+// The CUE template of the rule can refer to the dispatched manifest by `context.output`, and the application by
+// `context.appName`, `context.namespace`, `context.project` and `context.cluster`. The resource is rejected if the
+// `reject` field is evaluated to true, and the `message` field explains why it's rejected. The rule is skipped if the
+// `reject` field is incomplete, for example, it refers to a field not set in the manifest.
+type ResourceAdmissionRule struct {
+	// Name is the name of the rule, it's shown in the rejection message
+	Name string `json:"name"`
+	// Selector selects the resources validated by the rule, all the dispatched resources if not set
+	// +optional
+	Selector *ResourcePolicyRuleSelector `json:"selector,omitempty"`
+	// CUE is the CUE template of the rule
+	CUE string `json:"cue"`
+}
+
+// Match check if the policy applies to the application with the given namespace and project
+func (in ResourceAdmissionPolicySpec) Match(namespace string, project string) bool {
+	if len(in.Namespaces) > 0 {
+		if _, ok := matchPatterns("namespaces", in.Namespaces, namespace); !ok {
+			return false
+		}
+	}
+	if len(in.Projects) > 0 {
+		if _, ok := matchPatterns("projects", in.Projects, project); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// +kubebuilder:object:root=true
+
+// ResourceAdmissionPolicyList contains a list of ResourceAdmissionPolicy
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ResourceAdmissionPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ResourceAdmissionPolicy `json:"items"`
+}
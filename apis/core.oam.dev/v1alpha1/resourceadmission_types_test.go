@@ -0,0 +1,33 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceAdmissionPolicySpec_Match(t *testing.T) {
+	r := require.New(t)
+	spec := ResourceAdmissionPolicySpec{Namespaces: []string{"*", "!vela-system"}, Projects: []string{"prod-*"}}
+	r.True(spec.Match("default", "prod-a"))
+	r.False(spec.Match("vela-system", "prod-a"))
+	r.False(spec.Match("default", "dev"))
+	r.False(spec.Match("default", ""))
+	r.True(ResourceAdmissionPolicySpec{}.Match("default", ""))
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/oam"
+)
+
+// ResourcePolicyRuleSelectorOperator is the operator to combine the conditions of the selector
+type ResourcePolicyRuleSelectorOperator string
+
+const (
+	// ResourcePolicyRuleSelectorOperatorOr matches the resource if any of the conditions matches, which is the default
+	ResourcePolicyRuleSelectorOperatorOr ResourcePolicyRuleSelectorOperator = "or"
+	// ResourcePolicyRuleSelectorOperatorAnd matches the resource if all of the conditions match
+	ResourcePolicyRuleSelectorOperatorAnd ResourcePolicyRuleSelectorOperator = "and"
+)
+
+// ResourcePolicyRuleSelector select the targets of the rule, it's shared by all the resource policies.
+// The conditions are combined by the Operator, OR by default. If one resource is specified with conflict strategies,
+// the first matched rule goes first.
+// The values of the conditions support the wildcards like `web-*`, and the values starting with `!` exclude the
+// matched resources, for example, `["*", "!kube-system"]`.
+// AllOf, AnyOf and Not compose the selectors, they are combined with the conditions by AND.
+type ResourcePolicyRuleSelector struct {
+	CompNames        []string `json:"componentNames"`
+	CompTypes        []string `json:"componentTypes"`
+	OAMResourceTypes []string `json:"oamTypes"`
+	TraitTypes       []string `json:"traitTypes"`
+	ResourceTypes    []string `json:"resourceTypes"`
+	ResourceNames    []string `json:"resourceNames"`
+	// Namespaces select resources by their namespaces
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Clusters select resources by the clusters they are dispatched to, the control plane is `local`
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+	// APIGroups select resources by their API groups, the core group is `core`
+	// +optional
+	APIGroups []string `json:"apiGroups,omitempty"`
+	// APIVersions select resources by their API versions (like apps/v1)
+	// +optional
+	APIVersions []string `json:"apiVersions,omitempty"`
+	// LabelSelector select resources by the labels of the rendered manifests
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// Operator combines the conditions above, or (default) | and
+	// +optional
+	Operator ResourcePolicyRuleSelectorOperator `json:"operator,omitempty"`
+	// AllOf matches the resource if all the selectors match
+	// +optional
+	AllOf []ResourcePolicyRuleSelector `json:"allOf,omitempty"`
+	// AnyOf matches the resource if any of the selectors matches
+	// +optional
+	AnyOf []ResourcePolicyRuleSelector `json:"anyOf,omitempty"`
+	// Not matches the resource if the selector doesn't match
+	// +optional
+	Not *ResourcePolicyRuleSelector `json:"not,omitempty"`
+}
+
+// Match check if current rule selector match the target resource
+func (in *ResourcePolicyRuleSelector) Match(manifest *unstructured.Unstructured) bool {
+	matched, _ := in.match(manifest)
+	return matched
+}
+
+// MatchWithReason check if current rule selector match the target resource, and explain why it matches
+func (in *ResourcePolicyRuleSelector) MatchWithReason(manifest *unstructured.Unstructured) (bool, string) {
+	matched, reasons := in.match(manifest)
+	if !matched {
+		return false, ""
+	}
+	return true, strings.Join(reasons, ", ")
+}
+
+func (in *ResourcePolicyRuleSelector) match(manifest *unstructured.Unstructured) (bool, []string) {
+	var compName, compType, oamType, traitType string
+	if labels := manifest.GetLabels(); labels != nil {
+		compName = labels[oam.LabelAppComponent]
+		compType = labels[oam.WorkloadTypeLabel]
+		oamType = labels[oam.LabelOAMResourceType]
+		traitType = labels[oam.TraitTypeLabel]
+	}
+	cluster := oam.GetCluster(manifest)
+	if cluster == "" {
+		cluster = types.ClusterLocalName
+	}
+	gvk := manifest.GroupVersionKind()
+	group := gvk.Group
+	if group == "" && gvk.Version != "" {
+		group = "core"
+	}
+	fields := []struct {
+		name     string
+		patterns []string
+		value    string
+	}{
+		{"componentNames", in.CompNames, compName},
+		{"componentTypes", in.CompTypes, compType},
+		{"oamTypes", in.OAMResourceTypes, oamType},
+		{"traitTypes", in.TraitTypes, traitType},
+		{"resourceTypes", in.ResourceTypes, manifest.GetKind()},
+		{"resourceNames", in.ResourceNames, manifest.GetName()},
+		{"namespaces", in.Namespaces, manifest.GetNamespace()},
+		{"clusters", in.Clusters, cluster},
+		{"apiGroups", in.APIGroups, group},
+		{"apiVersions", in.APIVersions, manifest.GetAPIVersion()},
+	}
+
+	var reasons []string
+	conditions, matched := 0, 0
+	for _, field := range fields {
+		if len(field.patterns) == 0 {
+			continue
+		}
+		conditions++
+		if reason, ok := matchPatterns(field.name, field.patterns, field.value); ok {
+			matched++
+			reasons = append(reasons, reason)
+		}
+	}
+	if in.LabelSelector != nil {
+		conditions++
+		if selector, err := metav1.LabelSelectorAsSelector(in.LabelSelector); err == nil && selector.Matches(labels.Set(manifest.GetLabels())) {
+			matched++
+			reasons = append(reasons, fmt.Sprintf("labelSelector %q matches", selector.String()))
+		}
+	}
+	if conditions > 0 {
+		if matched == 0 || (in.Operator == ResourcePolicyRuleSelectorOperatorAnd && matched < conditions) {
+			return false, nil
+		}
+	}
+
+	for i := range in.AllOf {
+		ok, subReasons := in.AllOf[i].match(manifest)
+		if !ok {
+			return false, nil
+		}
+		reasons = append(reasons, subReasons...)
+	}
+	if len(in.AnyOf) > 0 {
+		found := false
+		for i := range in.AnyOf {
+			if ok, subReasons := in.AnyOf[i].match(manifest); ok {
+				found = true
+				reasons = append(reasons, subReasons...)
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+	if in.Not != nil {
+		if ok, _ := in.Not.match(manifest); ok {
+			return false, nil
+		}
+		reasons = append(reasons, "not excluded by the not selector")
+	}
+	// the empty selector matches nothing
+	if len(reasons) == 0 {
+		return false, nil
+	}
+	return true, reasons
+}
+
+// matchPatterns matches the value with the patterns. The patterns without the `!` prefix are combined by OR, and the
+// patterns with the `!` prefix are applied after that to exclude the matched values. If there are only the patterns
+// with the `!` prefix, all the values not excluded match.
+func matchPatterns(name string, patterns []string, value string) (string, bool) {
+	var includes, excludes []string
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			excludes = append(excludes, strings.TrimPrefix(pattern, "!"))
+			continue
+		}
+		includes = append(includes, pattern)
+	}
+	reason := fmt.Sprintf("%s %q is not excluded by %v", name, value, patterns)
+	if len(includes) > 0 {
+		reason = ""
+		for _, pattern := range includes {
+			if value != "" && matchPattern(pattern, value) {
+				reason = fmt.Sprintf("%s %q matches %q", name, value, pattern)
+				break
+			}
+		}
+		if reason == "" {
+			return "", false
+		}
+	}
+	for _, pattern := range excludes {
+		if matchPattern(pattern, value) {
+			return "", false
+		}
+	}
+	return reason, true
+}
+
+// matchPattern matches the value with the glob pattern, `*` matches any sequence of characters and `?` matches any
+// single character. Unlike path.Match, `/` is an ordinary character, so `apps/*` matches `apps/v1`.
+func matchPattern(pattern string, value string) bool {
+	p, v := []rune(pattern), []rune(value)
+	// star and next record the position of the last `*` and the value position to retry from
+	i, j, star, next := 0, 0, -1, 0
+	for j < len(v) {
+		switch {
+		case i < len(p) && p[i] == '*':
+			star, next = i, j
+			i++
+		case i < len(p) && (p[i] == '?' || p[i] == v[j]):
+			i++
+			j++
+		case star >= 0:
+			next++
+			i, j = star+1, next
+		default:
+			return false
+		}
+	}
+	for i < len(p) && p[i] == '*' {
+		i++
+	}
+	return i == len(p)
+}
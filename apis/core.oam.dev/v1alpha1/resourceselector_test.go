@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/oam-dev/kubevela/pkg/oam"
+)
+
+func TestResourcePolicyRuleSelector_Match(t *testing.T) {
+	deploy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "web-canary",
+			"namespace": "prod",
+			"labels": map[string]interface{}{
+				oam.LabelAppComponent: "web",
+				oam.LabelAppCluster:   "hangzhou",
+				"tier":                "frontend",
+			},
+		},
+	}}
+	cm := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "web-config", "namespace": "default"},
+	}}
+	testCases := map[string]struct {
+		selector ResourcePolicyRuleSelector
+		input    *unstructured.Unstructured
+		matched  bool
+		reason   string
+	}{
+		"empty selector": {
+			selector: ResourcePolicyRuleSelector{},
+			input:    deploy,
+		},
+		"or by default": {
+			selector: ResourcePolicyRuleSelector{CompNames: []string{"web"}, ResourceTypes: []string{"Service"}},
+			input:    deploy,
+			matched:  true,
+			reason:   `componentNames "web" matches "web"`,
+		},
+		"and": {
+			selector: ResourcePolicyRuleSelector{CompNames: []string{"web"}, ResourceTypes: []string{"Service"}, Operator: ResourcePolicyRuleSelectorOperatorAnd},
+			input:    deploy,
+		},
+		"wildcard": {
+			selector: ResourcePolicyRuleSelector{ResourceNames: []string{"web-*"}, Namespaces: []string{"prod"}, Operator: ResourcePolicyRuleSelectorOperatorAnd},
+			input:    deploy,
+			matched:  true,
+			reason:   `resourceNames "web-canary" matches "web-*", namespaces "prod" matches "prod"`,
+		},
+		"negation": {
+			selector: ResourcePolicyRuleSelector{ResourceNames: []string{"web-*", "!*-canary"}},
+			input:    deploy,
+		},
+		"negation after or": {
+			selector: ResourcePolicyRuleSelector{ResourceNames: []string{"!*-canary", "web-*", "web-canary"}},
+			input:    deploy,
+		},
+		"negation only": {
+			selector: ResourcePolicyRuleSelector{Namespaces: []string{"!kube-system"}},
+			input:    cm,
+			matched:  true,
+			reason:   `namespaces "default" is not excluded by [!kube-system]`,
+		},
+		"cluster": {
+			selector: ResourcePolicyRuleSelector{Clusters: []string{"local"}},
+			input:    cm,
+			matched:  true,
+			reason:   `clusters "local" matches "local"`,
+		},
+		"cluster mismatch": {
+			selector: ResourcePolicyRuleSelector{Clusters: []string{"local"}},
+			input:    deploy,
+		},
+		"api group": {
+			selector: ResourcePolicyRuleSelector{APIGroups: []string{"core"}},
+			input:    cm,
+			matched:  true,
+			reason:   `apiGroups "core" matches "core"`,
+		},
+		"api version": {
+			selector: ResourcePolicyRuleSelector{APIVersions: []string{"apps/*"}},
+			input:    deploy,
+			matched:  true,
+			reason:   `apiVersions "apps/v1" matches "apps/*"`,
+		},
+		"wildcard matches slash": {
+			selector: ResourcePolicyRuleSelector{APIVersions: []string{"*/v1", "!batch/*"}},
+			input:    deploy,
+			matched:  true,
+			reason:   `apiVersions "apps/v1" matches "*/v1"`,
+		},
+		"label selector": {
+			selector: ResourcePolicyRuleSelector{LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"frontend", "backend"},
+			}}}},
+			input:   deploy,
+			matched: true,
+			reason:  `labelSelector "tier in (backend,frontend)" matches`,
+		},
+		"label selector mismatch": {
+			selector: ResourcePolicyRuleSelector{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "frontend"}}},
+			input:    cm,
+		},
+		"composition": {
+			selector: ResourcePolicyRuleSelector{
+				AllOf: []ResourcePolicyRuleSelector{{ResourceTypes: []string{"Deployment", "StatefulSet"}}},
+				AnyOf: []ResourcePolicyRuleSelector{{Namespaces: []string{"staging"}}, {Namespaces: []string{"prod"}}},
+				Not:   &ResourcePolicyRuleSelector{Clusters: []string{"local"}},
+			},
+			input:   deploy,
+			matched: true,
+			reason:  `resourceTypes "Deployment" matches "Deployment", namespaces "prod" matches "prod", not excluded by the not selector`,
+		},
+		"composition mismatch": {
+			selector: ResourcePolicyRuleSelector{
+				CompNames: []string{"web"},
+				Not:       &ResourcePolicyRuleSelector{Namespaces: []string{"prod"}},
+			},
+			input: deploy,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			r.Equal(tc.matched, tc.selector.Match(tc.input))
+			matched, reason := tc.selector.MatchWithReason(tc.input)
+			r.Equal(tc.matched, matched)
+			r.Equal(tc.reason, reason)
+		})
+	}
+}
+
+func TestApplyOncePolicySpec_FindStrategy(t *testing.T) {
+	r := require.New(t)
+	deploy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web"},
+	}}
+	strategy := &ApplyOnceStrategy{Path: []string{"spec.replicas"}}
+	rule := func(selector ResourcePolicyRuleSelector) ApplyOncePolicySpec {
+		return ApplyOncePolicySpec{Enable: true, Rules: []ApplyOncePolicyRule{{Selector: selector, Strategy: strategy}}}
+	}
+	// the legacy rules combine the conditions by AND, and the component names only match the component label
+	r.Equal(strategy, rule(ResourcePolicyRuleSelector{ResourceTypes: []string{"Deployment"}}).FindStrategy(deploy))
+	r.Nil(rule(ResourcePolicyRuleSelector{CompNames: []string{"web"}}).FindStrategy(deploy))
+	r.Nil(rule(ResourcePolicyRuleSelector{}).FindStrategy(deploy))
+	deploy.SetLabels(map[string]string{oam.LabelAppComponent: "frontend"})
+	r.Nil(rule(ResourcePolicyRuleSelector{CompNames: []string{"frontend"}, ResourceTypes: []string{"Service"}}).FindStrategy(deploy))
+	r.Equal(strategy, rule(ResourcePolicyRuleSelector{CompNames: []string{"frontend"}, ResourceTypes: []string{"Deployment"}}).FindStrategy(deploy))
+	// the rules with the operator follow the unified semantics
+	r.Equal(strategy, rule(ResourcePolicyRuleSelector{CompNames: []string{"frontend"}, ResourceTypes: []string{"Service"}, Operator: ResourcePolicyRuleSelectorOperatorOr}).FindStrategy(deploy))
+	r.Nil(rule(ResourcePolicyRuleSelector{CompNames: []string{"web"}, Operator: ResourcePolicyRuleSelectorOperatorOr}).FindStrategy(deploy))
+	r.Equal(strategy, rule(ResourcePolicyRuleSelector{ResourceNames: []string{"*"}, Namespaces: []string{"!kube-*"}}).FindStrategy(deploy))
+	r.Nil(ApplyOncePolicySpec{Rules: rule(ResourcePolicyRuleSelector{ResourceTypes: []string{"Deployment"}}).Rules}.FindStrategy(deploy))
+}
+
+func TestMatchPattern(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		value   string
+		matched bool
+	}{
+		{"web", "web", true},
+		{"web", "web-1", false},
+		{"web-*", "web-1", true},
+		{"*", "", true},
+		{"*", "apps/v1", true},
+		{"apps/*", "apps/v1", true},
+		{"*/v1", "apps/v1", true},
+		{"*/v1", "apps/v1beta1", false},
+		{"a*b*c", "axxbyybzc", true},
+		{"a*b*c", "axxbyybz", false},
+		{"web-?", "web-1", true},
+		{"web-?", "web-12", false},
+		{"[web]", "[web]", true},
+		{"[web]", "w", false},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.matched, matchPattern(tc.pattern, tc.value), "%s %s", tc.pattern, tc.value)
+	}
+}
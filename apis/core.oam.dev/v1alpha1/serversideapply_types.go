@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// ServerSideApplyPolicyType refers to the type of server-side apply policy
+	ServerSideApplyPolicyType = "server-side-apply"
+)
+
+// ServerSideApplyConflictPolicy describes how to handle the conflicts with other field managers
+type ServerSideApplyConflictPolicy string
+
+const (
+	// ServerSideApplyConflictForce takes the ownership of the conflicting fields
+	ServerSideApplyConflictForce ServerSideApplyConflictPolicy = "force"
+	// ServerSideApplyConflictFail fails the apply if there are conflicting fields
+	ServerSideApplyConflictFail ServerSideApplyConflictPolicy = "fail"
+)
+
+// ServerSideApplyPolicySpec defines the spec of applying resources by the server-side apply
+type ServerSideApplyPolicySpec struct {
+	Enable bool `json:"enable"`
+	// FieldManager is the name of the field manager, the default is kubevela
+	// +optional
+	FieldManager string `json:"fieldManager,omitempty"`
+	// ConflictPolicy is the policy for the conflicts with other field managers, the default is force
+	// +optional
+	ConflictPolicy ServerSideApplyConflictPolicy `json:"conflictPolicy,omitempty"`
+	// Rules select the resources to apply by the server-side apply, all the resources are selected if not set
+	// +optional
+	Rules []ServerSideApplyPolicyRule `json:"rules,omitempty"`
+}
+
+// ServerSideApplyPolicyRule defines a single server-side apply policy rule
+type ServerSideApplyPolicyRule struct {
+	Selector ResourcePolicyRuleSelector `json:"selector"`
+}
+
+// FindStrategy return if the target resource should be applied by the server-side apply
+func (in ServerSideApplyPolicySpec) FindStrategy(manifest *unstructured.Unstructured) bool {
+	if !in.Enable {
+		return false
+	}
+	if len(in.Rules) == 0 {
+		return true
+	}
+	for _, rule := range in.Rules {
+		if rule.Selector.Match(manifest) {
+			return true
+		}
+	}
+	return false
+}
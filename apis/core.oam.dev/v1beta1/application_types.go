@@ -57,6 +57,26 @@ type Workflow struct {
 	Ref   string               `json:"ref,omitempty"`
 	Mode  *WorkflowExecuteMode `json:"mode,omitempty"`
 	Steps []WorkflowStep       `json:"steps,omitempty"`
+	// OnSuccess are the steps executed after all the steps are succeeded.
+	OnSuccess []WorkflowStep `json:"onSuccess,omitempty"`
+	// OnFailure are the steps executed after the workflow is terminated.
+	OnFailure []WorkflowStep `json:"onFailure,omitempty"`
+	// Finally are the steps always executed after the steps reach the terminal state.
+	Finally []WorkflowStep `json:"finally,omitempty"`
+	// Schedule restarts the workflow periodically after it's finished.
+	Schedule *WorkflowSchedule `json:"schedule,omitempty"`
+}
+
+// WorkflowSchedule defines the cron schedule to restart the workflow
+type WorkflowSchedule struct {
+	// Cron is the schedule in cron format, e.g. "0 2 * * *".
+	Cron string `json:"cron"`
+	// TimeZone is the name of the time zone of the schedule, e.g. "Asia/Shanghai". Defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+	// Suspend stops the subsequent scheduled runs if it's true.
+	Suspend bool `json:"suspend,omitempty"`
+	// HistoryLimit is the max number of the scheduled runs kept in the status. Defaults to 10.
+	HistoryLimit int `json:"historyLimit,omitempty"`
 }
 
 // WorkflowExecuteMode defines the mode of workflow execution
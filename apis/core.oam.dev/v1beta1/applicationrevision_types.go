@@ -57,6 +57,9 @@ type ApplicationRevisionSpec struct {
 	// Workflow records the external workflow
 	Workflow *v1alpha1.Workflow `json:"workflow,omitempty"`
 
+	// SubWorkflows records the workflows referred by the sub-workflow steps
+	SubWorkflows map[string]v1alpha1.Workflow `json:"subWorkflows,omitempty"`
+
 	// ReferredObjects records the referred objects used in the ref-object typed components
 	// +kubebuilder:pruning:PreserveUnknownFields
 	ReferredObjects []common.ReferredObject `json:"referredObjects,omitempty"`
@@ -82,6 +82,9 @@ type ManagedResource struct {
 	Data *runtime.RawExtension `json:"raw,omitempty"`
 	// Deleted marks the resource to be deleted
 	Deleted bool `json:"deleted,omitempty"`
+	// ReadOnly marks the resource is selected by the read-only policy when it's dispatched, the resource is never
+	// written or deleted by the application
+	ReadOnly bool `json:"readOnly,omitempty"`
 }
 
 // Equal check if two managed resource equals
@@ -216,7 +219,25 @@ func (in *ResourceTracker) ContainsManagedResource(rsc client.Object) bool {
 
 // AddManagedResource add object to managed resources, if exists, update
 func (in *ResourceTracker) AddManagedResource(rsc client.Object, metaOnly bool, creator common.ResourceCreatorRole) (updated bool) {
+	return in.addManagedResource(rsc, metaOnly, false, creator)
+}
+
+// AddReadOnlyManagedResource add object selected by the read-only policy to managed resources, if exists, update
+func (in *ResourceTracker) AddReadOnlyManagedResource(rsc client.Object, metaOnly bool, creator common.ResourceCreatorRole) (updated bool) {
+	return in.addManagedResource(rsc, metaOnly, true, creator)
+}
+
+// GetManagedResource returns the managed resource recorded for the object
+func (in *ResourceTracker) GetManagedResource(rsc client.Object) (ManagedResource, bool) {
+	if idx := in.findMangedResourceIndex(newManagedResourceFromResource(rsc)); idx >= 0 {
+		return in.Spec.ManagedResources[idx], true
+	}
+	return ManagedResource{}, false
+}
+
+func (in *ResourceTracker) addManagedResource(rsc client.Object, metaOnly bool, readOnly bool, creator common.ResourceCreatorRole) (updated bool) {
 	mr := newManagedResourceFromResource(rsc)
+	mr.ReadOnly = readOnly
 	if !metaOnly {
 		mr.Data = &runtime.RawExtension{Object: rsc}
 	}
@@ -256,6 +277,8 @@ func (in *ResourceTracker) DeleteManagedResource(rsc client.Object, remove bool)
 		if remove {
 			in.Spec.ManagedResources = append(in.Spec.ManagedResources[:idx], in.Spec.ManagedResources[idx+1:]...)
 		} else {
+			// the read-only resource is still not deleted after it's marked as deleted
+			mr.ReadOnly = in.Spec.ManagedResources[idx].ReadOnly
 			if reflect.DeepEqual(in.Spec.ManagedResources[idx], mr) {
 				return false
 			}
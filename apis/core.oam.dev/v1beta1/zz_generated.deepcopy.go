@@ -232,6 +232,13 @@ func (in *ApplicationRevisionSpec) DeepCopyInto(out *ApplicationRevisionSpec) {
 		*out = new(v1alpha1.Workflow)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SubWorkflows != nil {
+		in, out := &in.SubWorkflows, &out.SubWorkflows
+		*out = make(map[string]v1alpha1.Workflow, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	if in.ReferredObjects != nil {
 		in, out := &in.ReferredObjects, &out.ReferredObjects
 		*out = make([]common.ReferredObject, len(*in))
@@ -939,6 +946,32 @@ func (in *Workflow) DeepCopyInto(out *Workflow) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.OnSuccess != nil {
+		in, out := &in.OnSuccess, &out.OnSuccess
+		*out = make([]WorkflowStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OnFailure != nil {
+		in, out := &in.OnFailure, &out.OnFailure
+		*out = make([]WorkflowStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Finally != nil {
+		in, out := &in.Finally, &out.Finally
+		*out = make([]WorkflowStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(WorkflowSchedule)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Workflow.
@@ -966,6 +999,21 @@ func (in *WorkflowExecuteMode) DeepCopy() *WorkflowExecuteMode {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowSchedule) DeepCopyInto(out *WorkflowSchedule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowSchedule.
+func (in *WorkflowSchedule) DeepCopy() *WorkflowSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkflowStep) DeepCopyInto(out *WorkflowStep) {
 	*out = *in
@@ -986,6 +1034,11 @@ func (in *WorkflowStep) DeepCopyInto(out *WorkflowStep) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(common.WorkflowStepRetry)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DependsOn != nil {
 		in, out := &in.DependsOn, &out.DependsOn
 		*out = make([]string, len(*in))
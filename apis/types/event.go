@@ -26,6 +26,8 @@ const (
 	ReasonHealthCheck     = "HealthChecked"
 	ReasonDeployed        = "Deployed"
 	ReasonRollout         = "Rollout"
+	ReasonScheduled       = "Scheduled"
+	ReasonDriftCorrected  = "DriftCorrected"
 
 	ReasonFailedParse       = "FailedParse"
 	ReasonFailedRender      = "FailedRender"
@@ -36,6 +38,8 @@ const (
 	ReasonFailedStateKeep   = "FailedStateKeep"
 	ReasonFailedGC          = "FailedGC"
 	ReasonFailedRollout     = "FailedRollout"
+	ReasonFailedSchedule    = "FailedSchedule"
+	ReasonDriftDetected     = "DriftDetected"
 )
 
 // event message for Application
@@ -49,6 +53,7 @@ const (
 	MessageHealthCheck      = "Health checked healthy"
 	MessageDeployed         = "Deployed successfully"
 	MessageRollout          = "Rollout successfully"
+	MessageScheduled        = "Workflow restarted by schedule"
 
 	MessageFailedParse       = "fail to parse application, err: %v"
 	MessageFailedRender      = "fail to render application, err: %v"
@@ -56,6 +56,7 @@ import (
 	"github.com/oam-dev/kubevela/pkg/utils/system"
 	"github.com/oam-dev/kubevela/pkg/utils/util"
 	oamwebhook "github.com/oam-dev/kubevela/pkg/webhook/core.oam.dev"
+	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
 	wfTypes "github.com/oam-dev/kubevela/pkg/workflow/types"
 	"github.com/oam-dev/kubevela/version"
 )
@@ -145,6 +146,9 @@ func main() {
 	flag.IntVar(&wfTypes.MaxWorkflowWaitBackoffTime, "max-workflow-wait-backoff-time", 60, "Set the max workflow wait backoff time, default is 60")
 	flag.IntVar(&wfTypes.MaxWorkflowFailedBackoffTime, "max-workflow-failed-backoff-time", 300, "Set the max workflow wait backoff time, default is 300")
 	flag.IntVar(&wfTypes.MaxWorkflowStepErrorRetryTimes, "max-workflow-step-error-retry-times", 10, "Set the max workflow step error retry times, default is 10")
+	flag.StringVar(&wfContext.StorageBackend, "workflow-context-storage-backend", wfContext.StorageBackendConfigMap, "Set the storage backend of workflow context, valid values are configmap and chunked-configmap. The running workflows will be migrated to the new backend automatically")
+	flag.StringVar(&wfTypes.ApprovalRecorders, "workflow-approval-recorders", "", "Set the comma separated users trusted to record the approvals of workflow steps on behalf of others, such as the service account of the apiserver. The other users can only append their own approvals")
+	flag.IntVar(&wfContext.ChunkSize, "workflow-context-chunk-size", 512*1024, "Set the max bytes of the compressed workflow context stored in one chunk when using the chunked-configmap storage backend, default is 524288")
 	utilfeature.DefaultMutableFeatureGate.AddFlag(flag.CommandLine)
 
 	flag.Parse()
@@ -198,6 +202,11 @@ func main() {
 	klog.InfoS("Disable capabilities", "name", disableCaps)
 	klog.InfoS("Vela-Core init", "definition namespace", oam.SystemDefinitonNamespace)
 
+	if !wfContext.IsValidStorageBackend(wfContext.StorageBackend) {
+		klog.ErrorS(nil, "Invalid workflow context storage backend", "backend", wfContext.StorageBackend)
+		os.Exit(1)
+	}
+
 	restConfig := ctrl.GetConfigOrDie()
 	restConfig.UserAgent = types.KubeVelaName + "/" + version.GitRevision
 	restConfig.QPS = float32(qps)
@@ -27,6 +27,7 @@ import (
 func init() {
 	RegisterModel(&Workflow{})
 	RegisterModel(&WorkflowRecord{})
+	RegisterModel(&WorkflowCallback{})
 }
 
 // Finished means the workflow record is finished
@@ -123,6 +124,8 @@ type WorkflowStepStatus struct {
 	Reason           string                   `json:"reason,omitempty"`
 	FirstExecuteTime time.Time                `json:"firstExecuteTime,omitempty"`
 	LastExecuteTime  time.Time                `json:"lastExecuteTime,omitempty"`
+	// Approvals record who approved or rejected the approval step
+	Approvals []common.WorkflowStepApproval `json:"approvals,omitempty"`
 }
 
 // TableName return custom table name
@@ -166,3 +169,41 @@ func (w *WorkflowRecord) Index() map[string]string {
 	}
 	return index
 }
+
+// WorkflowCallback is the consumed workflow callback database model, the callback tokens are rejected once their
+// nonces are recorded.
+type WorkflowCallback struct {
+	BaseModel
+	Nonce     string `json:"nonce"`
+	Namespace string `json:"namespace"`
+	AppName   string `json:"appName"`
+	StepID    string `json:"stepID"`
+	Action    string `json:"action"`
+}
+
+// TableName return custom table name
+func (w *WorkflowCallback) TableName() string {
+	return tableNamePrefix + "workflow_callback"
+}
+
+// ShortTableName is the compressed version of table name for kubeapi storage and others
+func (w *WorkflowCallback) ShortTableName() string {
+	return "wfcb"
+}
+
+// PrimaryKey return custom primary key
+func (w *WorkflowCallback) PrimaryKey() string {
+	return w.Nonce
+}
+
+// Index return custom index
+func (w *WorkflowCallback) Index() map[string]string {
+	index := make(map[string]string)
+	if w.Namespace != "" {
+		index["namespace"] = w.Namespace
+	}
+	if w.AppName != "" {
+		index["appName"] = w.AppName
+	}
+	return index
+}
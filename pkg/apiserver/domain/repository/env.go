@@ -53,7 +53,8 @@ func CreateEnv(ctx context.Context, kubeClient client.Client, ds datastore.DataS
 		util.MergeOverrideLabels(map[string]string{
 			oam.LabelControlPlaneNamespaceUsage: oam.VelaNamespaceUsageEnv,
 		}), util.MergeNoConflictLabels(map[string]string{
-			oam.LabelNamespaceOfEnvName: env.Name,
+			oam.LabelNamespaceOfEnvName:     env.Name,
+			oam.LabelNamespaceOfProjectName: env.Project,
 		}))
 	if err != nil {
 		if velaerr.IsLabelConflict(err) {
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"sort"
 
@@ -47,6 +48,7 @@ type EnvService interface {
 	DeleteEnv(ctx context.Context, envName string) error
 	CreateEnv(ctx context.Context, req apisv1.CreateEnvRequest) (*apisv1.Env, error)
 	UpdateEnv(ctx context.Context, envName string, req apisv1.UpdateEnvRequest) (*apisv1.Env, error)
+	Init(ctx context.Context) error
 }
 
 type envServiceImpl struct {
@@ -60,6 +62,37 @@ func NewEnvService() EnvService {
 	return &envServiceImpl{}
 }
 
+// Init backfills the project label of the namespaces bound to the envs created before the label is introduced, the
+// label is used to scope the configs read and written by the workflow steps.
+func (p *envServiceImpl) Init(ctx context.Context) error {
+	envs, err := repository.ListEnvs(ctx, p.Store, &datastore.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("list env failure %w", err)
+	}
+	for _, env := range envs {
+		if env.Namespace == "" || env.Project == "" {
+			continue
+		}
+		namespace, err := util.GetNamespace(ctx, p.KubeClient, env.Namespace)
+		if err != nil {
+			if apierror.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		labels := namespace.GetLabels()
+		if labels[oam.LabelNamespaceOfEnvName] != env.Name || labels[oam.LabelNamespaceOfProjectName] == env.Project {
+			continue
+		}
+		if err := util.UpdateNamespace(ctx, p.KubeClient, env.Namespace, util.MergeOverrideLabels(map[string]string{
+			oam.LabelNamespaceOfProjectName: env.Project,
+		})); err != nil {
+			return fmt.Errorf("failed to backfill the project label of namespace %s: %w", env.Namespace, err)
+		}
+	}
+	return nil
+}
+
 // GetEnv get env
 func (p *envServiceImpl) GetEnv(ctx context.Context, envName string) (*model.Env, error) {
 	return repository.GetEnv(ctx, p.Store, envName)
@@ -81,6 +114,7 @@ func (p *envServiceImpl) DeleteEnv(ctx context.Context, envName string) error {
 	// reset the labels
 	err := util.UpdateNamespace(ctx, p.KubeClient, env.Namespace, util.MergeOverrideLabels(map[string]string{
 		oam.LabelNamespaceOfEnvName:         "",
+		oam.LabelNamespaceOfProjectName:     "",
 		oam.LabelControlPlaneNamespaceUsage: "",
 	}))
 	if err != nil && apierror.IsNotFound(err) {
@@ -25,6 +25,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
@@ -87,6 +88,7 @@ var _ = Describe("Test env service functions", func() {
 		err = k8sClient.Get(context.TODO(), types.NamespacedName{Name: base.Namespace}, &namespace)
 		Expect(err).Should(BeNil())
 		Expect(cmp.Diff(namespace.Labels[oam.LabelNamespaceOfEnvName], req3.Name)).Should(BeEmpty())
+		Expect(cmp.Diff(namespace.Labels[oam.LabelNamespaceOfProjectName], req3.Project)).Should(BeEmpty())
 
 		var roleBinding rbacv1.RoleBinding
 		err = k8sClient.Get(context.TODO(), types.NamespacedName{Name: auth.KubeVelaWriterAppRoleName + ":binding", Namespace: base.Namespace}, &roleBinding)
@@ -124,6 +126,19 @@ var _ = Describe("Test env service functions", func() {
 		Expect(err).Should(BeNil())
 	})
 
+	It("Test backfilling the project label of the env namespaces", func() {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "env-init-test", Labels: map[string]string{
+			oam.LabelNamespaceOfEnvName: "env-init",
+		}}}
+		Expect(k8sClient.Create(context.TODO(), namespace)).Should(BeNil())
+		Expect(ds.Add(context.TODO(), &model.Env{Name: "env-init", Namespace: "env-init-test", Project: "env-project"})).Should(BeNil())
+		Expect(ds.Add(context.TODO(), &model.Env{Name: "env-init-missing", Namespace: "env-init-missing", Project: "env-project"})).Should(BeNil())
+
+		Expect(envService.Init(context.TODO())).Should(BeNil())
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: namespace.Name}, namespace)).Should(BeNil())
+		Expect(cmp.Diff(namespace.Labels[oam.LabelNamespaceOfProjectName], "env-project")).Should(BeEmpty())
+	})
+
 	It("test checkEqual", func() {
 		Expect(checkEqual([]string{"default"}, []string{"default", "dev"})).Should(BeFalse())
 		Expect(checkEqual([]string{"default"}, []string{"default"})).Should(BeTrue())
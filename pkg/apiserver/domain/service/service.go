@@ -46,7 +46,7 @@ func InitServiceBean(c config.Config) []interface{} {
 	configService := NewConfigService()
 	applicationService := NewApplicationService()
 	webhookService := NewWebhookService()
-	needInitData = []DataInit{clusterService, userService, rbacService, projectService, targetService, systemInfoService}
+	needInitData = []DataInit{clusterService, userService, rbacService, projectService, envService, targetService, systemInfoService}
 	return []interface{}{
 		clusterService, rbacService, projectService, envService, targetService, workflowService, oamApplicationService,
 		velaQLService, definitionService, addonService, envBindingService, systemInfoService, helmService, userService,
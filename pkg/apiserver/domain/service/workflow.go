@@ -25,7 +25,9 @@ import (
 
 	"helm.sh/helm/v3/pkg/time"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
@@ -40,9 +42,11 @@ import (
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils"
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+	"github.com/oam-dev/kubevela/pkg/auth"
 	"github.com/oam-dev/kubevela/pkg/oam"
 	pkgUtils "github.com/oam-dev/kubevela/pkg/utils"
 	"github.com/oam-dev/kubevela/pkg/utils/apply"
+	"github.com/oam-dev/kubevela/pkg/workflow/callback"
 	wfTypes "github.com/oam-dev/kubevela/pkg/workflow/types"
 )
 
@@ -61,9 +65,12 @@ type WorkflowService interface {
 	DetailWorkflowRecord(ctx context.Context, workflow *model.Workflow, recordName string) (*apisv1.DetailWorkflowRecordResponse, error)
 	SyncWorkflowRecord(ctx context.Context) error
 	ResumeRecord(ctx context.Context, appModel *model.Application, workflow *model.Workflow, recordName string) error
+	RejectRecord(ctx context.Context, appModel *model.Application, workflow *model.Workflow, recordName, message string) error
 	TerminateRecord(ctx context.Context, appModel *model.Application, workflow *model.Workflow, recordName string) error
 	RollbackRecord(ctx context.Context, appModel *model.Application, workflow *model.Workflow, recordName, revisionName string) error
 	CountWorkflow(ctx context.Context, app *model.Application) int64
+	CheckWorkflowCallback(ctx context.Context, token string) (*apisv1.WorkflowCallbackResponse, error)
+	HandleWorkflowCallback(ctx context.Context, token, message string) (*apisv1.WorkflowCallbackResponse, error)
 }
 
 // NewWorkflowService new workflow service
@@ -502,6 +509,7 @@ func (w *workflowServiceImpl) syncWorkflowStatus(ctx context.Context, appPrimary
 				record.Steps[i].Reason = stepStatus[step.Name].Reason
 				record.Steps[i].FirstExecuteTime = stepStatus[step.Name].FirstExecuteTime.Time
 				record.Steps[i].LastExecuteTime = stepStatus[step.Name].LastExecuteTime.Time
+				record.Steps[i].Approvals = stepStatus[step.Name].Approvals
 			}
 		}
 		record.Finished = strconv.FormatBool(status.Finished)
@@ -635,7 +643,7 @@ func (w *workflowServiceImpl) ResumeRecord(ctx context.Context, appModel *model.
 		return err
 	}
 
-	if err := ResumeWorkflow(ctx, w.KubeClient, oamApp); err != nil {
+	if err := ResumeWorkflow(ctx, w.KubeClient, oamApp, getRequestIdentity(ctx)); err != nil {
 		return err
 	}
 
@@ -646,6 +654,35 @@ func (w *workflowServiceImpl) ResumeRecord(ctx context.Context, appModel *model.
 	return nil
 }
 
+func (w *workflowServiceImpl) RejectRecord(ctx context.Context, appModel *model.Application, workflow *model.Workflow, recordName, message string) error {
+	oamApp, err := w.checkRecordRunning(ctx, appModel, workflow.EnvName)
+	if err != nil {
+		return err
+	}
+	if err := RejectWorkflow(ctx, w.KubeClient, oamApp, getRequestIdentity(ctx), message); err != nil {
+		return err
+	}
+	if err := w.syncWorkflowStatus(ctx, appModel.PrimaryKey(), oamApp, recordName, oamApp.Name); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getRequestIdentity returns the identity of the login user of the request, the groups are the same as the ones
+// impersonated for the requests of the user.
+func getRequestIdentity(ctx context.Context) *auth.Identity {
+	userName, ok := ctx.Value(&apisv1.CtxKeyUser).(string)
+	if !ok || userName == "" {
+		return nil
+	}
+	identity := &auth.Identity{User: userName}
+	if project, ok := utils.ProjectFrom(ctx); ok && project != "" {
+		identity.Groups = []string{utils.KubeVelaProjectGroupPrefix + project}
+	}
+	return identity
+}
+
 func (w *workflowServiceImpl) TerminateRecord(ctx context.Context, appModel *model.Application, workflow *model.Workflow, recordName string) error {
 	oamApp, err := w.checkRecordRunning(ctx, appModel, workflow.EnvName)
 	if err != nil {
@@ -661,8 +698,11 @@ func (w *workflowServiceImpl) TerminateRecord(ctx context.Context, appModel *mod
 	return nil
 }
 
-// ResumeWorkflow resume workflow
-func ResumeWorkflow(ctx context.Context, kubecli client.Client, app *v1beta1.Application) error {
+// ResumeWorkflow resume workflow, the approver is recorded as an approval of the running approval steps
+func ResumeWorkflow(ctx context.Context, kubecli client.Client, app *v1beta1.Application, approver *auth.Identity) error {
+	if _, err := recordApprovals(app, approver, common.WorkflowStepApprovalApprove, ""); err != nil {
+		return err
+	}
 	app.Status.Workflow.Suspend = false
 	steps := app.Status.Workflow.Steps
 	for i, step := range steps {
@@ -681,6 +721,83 @@ func ResumeWorkflow(ctx context.Context, kubecli client.Client, app *v1beta1.App
 	return nil
 }
 
+// RejectWorkflow rejects the running approval steps of the workflow, the workflow will be terminated
+func RejectWorkflow(ctx context.Context, kubecli client.Client, app *v1beta1.Application, approver *auth.Identity, message string) error {
+	found, err := recordApprovals(app, approver, common.WorkflowStepApprovalReject, message)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("there is no approval step waiting for approvals")
+	}
+	app.Status.Workflow.Suspend = false
+	return kubecli.Status().Patch(ctx, app, client.Merge)
+}
+
+// recordApprovals records the action of the approver in the running approval steps, it returns false if
+// there is no running approval step.
+func recordApprovals(app *v1beta1.Application, approver *auth.Identity, action common.WorkflowStepApprovalAction, message string) (bool, error) {
+	specSteps := map[string]v1beta1.WorkflowStep{}
+	if app.Spec.Workflow != nil {
+		for _, step := range app.Spec.Workflow.Steps {
+			specSteps[step.Name] = step
+			for _, sub := range step.SubSteps {
+				specSteps[sub.Name] = v1beta1.WorkflowStep{Name: sub.Name, Type: sub.Type, Properties: sub.Properties}
+			}
+		}
+	}
+	found := false
+	record := func(status *common.StepStatus) error {
+		if status.Type != wfTypes.WorkflowStepTypeApproval || status.Phase != common.WorkflowStepPhaseRunning {
+			return nil
+		}
+		found = true
+		if approver == nil {
+			return fmt.Errorf("the identity of the approver is required for the approval step %s", status.Name)
+		}
+		props, err := wfTypes.GetApprovalProperties(specSteps[status.Name])
+		if err != nil {
+			return err
+		}
+		name := getApproverName(approver)
+		if !props.IsEligible(name, approver.Groups) {
+			return fmt.Errorf("%s is not an approver of the approval step %s", name, status.Name)
+		}
+		for _, approval := range status.Approvals {
+			if approval.Approver == name && approval.Action == action {
+				return nil
+			}
+		}
+		status.Approvals = append(status.Approvals, common.WorkflowStepApproval{
+			Approver: name,
+			Groups:   approver.Groups,
+			Action:   action,
+			Message:  message,
+			Time:     metav1.Now(),
+		})
+		return nil
+	}
+	steps := app.Status.Workflow.Steps
+	for i := range steps {
+		if err := record(&steps[i].StepStatus); err != nil {
+			return found, err
+		}
+		for j := range steps[i].SubStepsStatus {
+			if err := record(&steps[i].SubStepsStatus[j].StepStatus); err != nil {
+				return found, err
+			}
+		}
+	}
+	return found, nil
+}
+
+func getApproverName(identity *auth.Identity) string {
+	if identity.ServiceAccount != "" {
+		return serviceaccount.MakeUsername(identity.ServiceAccountNamespace, identity.ServiceAccount)
+	}
+	return identity.User
+}
+
 // TerminateWorkflow terminate workflow
 func TerminateWorkflow(ctx context.Context, kubecli client.Client, app *v1beta1.Application) error {
 	// set the workflow terminated to true
@@ -833,3 +950,138 @@ func (w *workflowServiceImpl) checkRecordRunning(ctx context.Context, appModel *
 	oamApp.SetGroupVersionKind(v1beta1.ApplicationKindVersionKind)
 	return oamApp, nil
 }
+
+// CheckWorkflowCallback verifies the callback token and checks whether the workflow is waiting for the callback,
+// the workflow is not changed.
+func (w *workflowServiceImpl) CheckWorkflowCallback(ctx context.Context, token string) (*apisv1.WorkflowCallbackResponse, error) {
+	claims, app, err := w.loadWorkflowCallback(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return convertWorkflowCallback(claims, app), nil
+}
+
+// HandleWorkflowCallback verifies the callback token, and resumes the suspended workflow for the approve callbacks,
+// or rejects the approval steps for the reject callbacks. The workflow is terminated if the reject callback is not
+// sent for an approval step. The approvals are recorded as callback.Approver, and every token is used only once.
+func (w *workflowServiceImpl) HandleWorkflowCallback(ctx context.Context, token, message string) (*apisv1.WorkflowCallbackResponse, error) {
+	claims, app, err := w.loadWorkflowCallback(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Action != callback.ActionApprove && claims.Action != callback.ActionReject {
+		return nil, bcode.ErrInvalidWorkflowCallback
+	}
+	consumed := &model.WorkflowCallback{
+		Nonce:     claims.Nonce,
+		Namespace: claims.Namespace,
+		AppName:   claims.Name,
+		StepID:    claims.StepID,
+		Action:    string(claims.Action),
+	}
+	if err := w.Store.Add(ctx, consumed); err != nil {
+		if errors.Is(err, datastore.ErrRecordExist) {
+			return nil, bcode.ErrWorkflowCallbackUsed
+		}
+		return nil, err
+	}
+	approver := &auth.Identity{User: callback.Approver}
+	switch claims.Action {
+	case callback.ActionApprove:
+		err = ResumeWorkflow(ctx, w.KubeClient, app, approver)
+	case callback.ActionReject:
+		if hasRunningApprovalStep(app) {
+			err = RejectWorkflow(ctx, w.KubeClient, app, approver, message)
+		} else {
+			err = TerminateWorkflow(ctx, w.KubeClient, app)
+		}
+	}
+	if err != nil {
+		// release the token so that the callback can be retried
+		if err := w.Store.Delete(ctx, consumed); err != nil {
+			klog.Errorf("failed to release the workflow callback of application %s/%s: %s", app.Namespace, app.Name, err.Error())
+		}
+		return nil, err
+	}
+	klog.Infof("workflow of application %s/%s is %s by the callback of step %s", app.Namespace, app.Name, claims.Action, claims.StepID)
+	return convertWorkflowCallback(claims, app), nil
+}
+
+func (w *workflowServiceImpl) loadWorkflowCallback(ctx context.Context, token string) (*callback.Claims, *v1beta1.Application, error) {
+	key, err := callback.GetKey(ctx, w.KubeClient)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, bcode.ErrInvalidWorkflowCallback
+		}
+		return nil, nil, err
+	}
+	claims, err := callback.Verify(key, token, time.Now().Time)
+	if err != nil {
+		if errors.Is(err, callback.ErrExpired) {
+			return nil, nil, bcode.ErrWorkflowCallbackExpired
+		}
+		return nil, nil, bcode.ErrInvalidWorkflowCallback
+	}
+	if claims.Nonce == "" {
+		return nil, nil, bcode.ErrInvalidWorkflowCallback
+	}
+	used, err := w.Store.IsExist(ctx, &model.WorkflowCallback{Nonce: claims.Nonce})
+	if err != nil {
+		return nil, nil, err
+	}
+	if used {
+		return nil, nil, bcode.ErrWorkflowCallbackUsed
+	}
+	app := &v1beta1.Application{}
+	if err := w.KubeClient.Get(ctx, types.NamespacedName{Namespace: claims.Namespace, Name: claims.Name}, app); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, bcode.ErrApplicationNotExist
+		}
+		return nil, nil, err
+	}
+	if err := callback.CheckStep(app, claims); err != nil {
+		if errors.Is(err, callback.ErrOutdated) {
+			return nil, nil, bcode.ErrWorkflowCallbackOutdated
+		}
+		return nil, nil, bcode.ErrInvalidWorkflowCallback
+	}
+	if !callback.IsSuspended(app) {
+		return nil, nil, bcode.ErrWorkflowNotSuspended
+	}
+	app.SetGroupVersionKind(v1beta1.ApplicationKindVersionKind)
+	return claims, app, nil
+}
+
+func hasRunningApprovalStep(app *v1beta1.Application) bool {
+	for _, step := range app.Status.Workflow.Steps {
+		if step.Type == wfTypes.WorkflowStepTypeApproval && step.Phase == common.WorkflowStepPhaseRunning {
+			return true
+		}
+		for _, sub := range step.SubStepsStatus {
+			if sub.Type == wfTypes.WorkflowStepTypeApproval && sub.Phase == common.WorkflowStepPhaseRunning {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func convertWorkflowCallback(claims *callback.Claims, app *v1beta1.Application) *apisv1.WorkflowCallbackResponse {
+	res := &apisv1.WorkflowCallbackResponse{
+		Application: app.Name,
+		Namespace:   app.Namespace,
+		Action:      string(claims.Action),
+		Approver:    callback.Approver,
+	}
+	for _, step := range app.Status.Workflow.Steps {
+		if step.ID == claims.StepID {
+			res.Step = step.Name
+		}
+		for _, sub := range step.SubStepsStatus {
+			if sub.ID == claims.StepID {
+				res.Step = sub.Name
+			}
+		}
+	}
+	return res
+}
@@ -21,22 +21,33 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/yaml"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/clients"
 	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore/kubeapi"
 	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+	"github.com/oam-dev/kubevela/pkg/auth"
 	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/utils/apply"
+	"github.com/oam-dev/kubevela/pkg/workflow/callback"
 )
 
 var appName = "app-workflow"
@@ -614,3 +625,129 @@ func (w *workflowServiceImpl) createTestApplicationRevision(ctx context.Context,
 	}
 	return nil
 }
+
+func TestHandleWorkflowCallback(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	assert.NilError(t, v1beta1.AddToScheme(s))
+	assert.NilError(t, corev1.AddToScheme(s))
+	app := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Status: common.AppStatus{
+			Phase: common.ApplicationWorkflowSuspending,
+			Workflow: &common.WorkflowStatus{
+				Suspend: true,
+				Steps: []common.WorkflowStepStatus{{
+					StepStatus: common.StepStatus{ID: "s1", Name: "notify", Type: "notification", Phase: common.WorkflowStepPhaseSucceeded},
+				}, {
+					StepStatus: common.StepStatus{ID: "s2", Name: "suspend", Type: "suspend", Phase: common.WorkflowStepPhaseRunning},
+				}},
+			},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(app).Build()
+	clients.SetKubeClient(cli)
+	ds, err := kubeapi.New(ctx, datastore.Config{Database: "kubevela"})
+	assert.NilError(t, err)
+	w := &workflowServiceImpl{KubeClient: cli, Store: ds}
+	key, err := callback.GetOrCreateKey(ctx, cli)
+	assert.NilError(t, err)
+	sign := func(stepID string, action callback.Action, expiresAt int64) string {
+		token, err := callback.Sign(key, callback.Claims{Namespace: "default", Name: "app", StepID: stepID, Action: action, ExpiresAt: expiresAt})
+		assert.NilError(t, err)
+		return token
+	}
+
+	_, err = w.CheckWorkflowCallback(ctx, "invalid")
+	assert.Equal(t, err, bcode.ErrInvalidWorkflowCallback)
+	_, err = w.CheckWorkflowCallback(ctx, sign("s1", callback.ActionApprove, time.Now().Add(-time.Minute).Unix()))
+	assert.Equal(t, err, bcode.ErrWorkflowCallbackExpired)
+	_, err = w.CheckWorkflowCallback(ctx, sign("s0", callback.ActionApprove, 0))
+	assert.Equal(t, err, bcode.ErrWorkflowCallbackOutdated)
+
+	token := sign("s1", callback.ActionApprove, 0)
+	res, err := w.CheckWorkflowCallback(ctx, token)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, res, &apisv1.WorkflowCallbackResponse{Application: "app", Namespace: "default", Step: "notify", Action: "approve", Approver: callback.Approver})
+	res, err = w.HandleWorkflowCallback(ctx, token, "")
+	assert.NilError(t, err)
+	assert.Equal(t, res.Action, "approve")
+	updated := &v1beta1.Application{}
+	assert.NilError(t, cli.Get(ctx, client.ObjectKeyFromObject(app), updated))
+	assert.Equal(t, updated.Status.Workflow.Suspend, false)
+	assert.Equal(t, updated.Status.Workflow.Steps[1].Phase, common.WorkflowStepPhaseSucceeded)
+	_, err = w.HandleWorkflowCallback(ctx, sign("s1", callback.ActionApprove, 0), "")
+	assert.Equal(t, err, bcode.ErrWorkflowNotSuspended)
+
+	updated.Status.Workflow.Suspend = true
+	updated.Status.Workflow.Steps[1].Phase = common.WorkflowStepPhaseRunning
+	assert.NilError(t, cli.Status().Update(ctx, updated))
+	_, err = w.CheckWorkflowCallback(ctx, token)
+	assert.Equal(t, err, bcode.ErrWorkflowCallbackUsed)
+	_, err = w.HandleWorkflowCallback(ctx, token, "")
+	assert.Equal(t, err, bcode.ErrWorkflowCallbackUsed)
+	_, err = w.HandleWorkflowCallback(ctx, sign("s1", callback.ActionReject, 0), "")
+	assert.NilError(t, err)
+	assert.NilError(t, cli.Get(ctx, client.ObjectKeyFromObject(app), updated))
+	assert.Equal(t, updated.Status.Workflow.Terminated, true)
+	assert.Equal(t, updated.Status.Workflow.Steps[1].Phase, common.WorkflowStepPhaseFailed)
+}
+
+func TestHandleWorkflowCallbackOfApprovalStep(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	assert.NilError(t, v1beta1.AddToScheme(s))
+	assert.NilError(t, corev1.AddToScheme(s))
+	app := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: v1beta1.ApplicationSpec{Workflow: &v1beta1.Workflow{Steps: []v1beta1.WorkflowStep{{
+			Name: "notify", Type: "notification",
+		}, {
+			Name: "approve", Type: "approval",
+		}}}},
+		Status: common.AppStatus{
+			Phase: common.ApplicationWorkflowSuspending,
+			Workflow: &common.WorkflowStatus{
+				Suspend: true,
+				Steps: []common.WorkflowStepStatus{{
+					StepStatus: common.StepStatus{ID: "s1", Name: "notify", Type: "notification", Phase: common.WorkflowStepPhaseSucceeded},
+				}, {
+					StepStatus: common.StepStatus{ID: "s2", Name: "approve", Type: "approval", Phase: common.WorkflowStepPhaseRunning},
+				}},
+			},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(app).Build()
+	clients.SetKubeClient(cli)
+	ds, err := kubeapi.New(ctx, datastore.Config{Database: "kubevela"})
+	assert.NilError(t, err)
+	w := &workflowServiceImpl{KubeClient: cli, Store: ds}
+	key, err := callback.GetOrCreateKey(ctx, cli)
+	assert.NilError(t, err)
+	token, err := callback.Sign(key, callback.Claims{Namespace: "default", Name: "app", StepID: "s1", Action: callback.ActionApprove})
+	assert.NilError(t, err)
+
+	// the callback identity must be listed in the approvers explicitly
+	_, err = w.HandleWorkflowCallback(ctx, token, "")
+	assert.Assert(t, err != nil)
+	updated := &v1beta1.Application{}
+	assert.NilError(t, cli.Get(ctx, client.ObjectKeyFromObject(app), updated))
+	assert.Equal(t, len(updated.Status.Workflow.Steps[1].Approvals), 0)
+
+	updated.Spec.Workflow.Steps[1].Properties = &runtime.RawExtension{Raw: []byte(`{"approvers":["vela:workflow-callback"]}`)}
+	assert.NilError(t, cli.Update(ctx, updated))
+	_, err = w.HandleWorkflowCallback(ctx, token, "")
+	assert.NilError(t, err)
+	assert.NilError(t, cli.Get(ctx, client.ObjectKeyFromObject(app), updated))
+	assert.Equal(t, len(updated.Status.Workflow.Steps[1].Approvals), 1)
+	assert.Equal(t, updated.Status.Workflow.Steps[1].Approvals[0].Approver, callback.Approver)
+}
+
+func TestGetRequestIdentity(t *testing.T) {
+	ctx := context.Background()
+	assert.Assert(t, getRequestIdentity(ctx) == nil)
+	ctx = context.WithValue(ctx, &apisv1.CtxKeyUser, "alice")
+	assert.DeepEqual(t, getRequestIdentity(ctx), &auth.Identity{User: "alice"})
+	ctx = utils.WithProject(ctx, "demo")
+	assert.DeepEqual(t, getRequestIdentity(ctx), &auth.Identity{User: "alice", Groups: []string{"kubevela:project:demo"}})
+}
@@ -514,6 +514,20 @@ func (c *applicationAPIInterface) GetWebServiceRoute() *restful.WebService {
 		Returns(400, "Bad Request", bcode.Bcode{}).
 		Writes(apis.DetailWorkflowRecordResponse{}))
 
+	ws.Route(ws.GET("/{appName}/workflows/{workflowName}/records/{record}/reject").To(c.WorkflowAPI.rejectWorkflowRecord).
+		Doc("reject the approval steps of suspend workflow record").
+		Filter(c.RbacService.CheckPerm("application/workflow/record", "reject")).
+		Param(ws.PathParameter("appName", "identifier of the application.").DataType("string").Required(true)).
+		Param(ws.PathParameter("workflowName", "identifier of the workflow").DataType("string")).
+		Param(ws.PathParameter("record", "identifier of the workflow record").DataType("string")).
+		Param(ws.QueryParameter("message", "the reason of the rejection").DataType("string")).
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Filter(c.appCheckFilter).
+		Filter(c.WorkflowAPI.workflowCheckFilter).
+		Returns(200, "OK", nil).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.DetailWorkflowRecordResponse{}))
+
 	ws.Route(ws.GET("/{appName}/workflows/{workflowName}/records/{record}/terminate").To(c.WorkflowAPI.terminateWorkflowRecord).
 		Doc("terminate suspend workflow record").
 		Filter(c.RbacService.CheckPerm("application/workflow/record", "terminate")).
@@ -1015,6 +1015,22 @@ const (
 	TriggerTypeWebhook string = "webhook"
 )
 
+// HandleWorkflowCallbackRequest the request of handling the workflow callback
+type HandleWorkflowCallbackRequest struct {
+	// Message the message of rejecting the approval steps
+	Message string `json:"message,omitempty"`
+}
+
+// WorkflowCallbackResponse the workflow waiting for the callback
+type WorkflowCallbackResponse struct {
+	Application string `json:"application"`
+	Namespace   string `json:"namespace"`
+	Step        string `json:"step"`
+	// Action approve or reject
+	Action   string `json:"action"`
+	Approver string `json:"approver,omitempty"`
+}
+
 // DetailWorkflowRecordResponse get workflow record detail
 type DetailWorkflowRecordResponse struct {
 	WorkflowRecord
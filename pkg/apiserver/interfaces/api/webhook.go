@@ -17,17 +17,24 @@ limitations under the License.
 package api
 
 import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+
 	restfulspec "github.com/emicklei/go-restful-openapi/v2"
 	"github.com/emicklei/go-restful/v3"
 
 	"github.com/oam-dev/kubevela/pkg/apiserver/domain/service"
 	apis "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
 )
 
 type webhookAPIInterface struct {
 	WebhookService     service.WebhookService     `inject:""`
 	ApplicationService service.ApplicationService `inject:""`
+	WorkflowService    service.WorkflowService    `inject:""`
 }
 
 // NewWebhookAPIInterface new application manage APIInterface
@@ -52,6 +59,28 @@ func (c *webhookAPIInterface) GetWebServiceRoute() *restful.WebService {
 		Returns(200, "OK", apis.ApplicationDeployResponse{}).
 		Returns(400, "Bad Request", bcode.Bcode{}).
 		Writes(apis.ApplicationDeployResponse{}))
+
+	// the links in the notifications are opened with GET, which only shows the confirmation page. So the workflow
+	// is not changed by the link previews of the chat tools.
+	ws.Route(ws.GET("/workflow-callbacks/{token}").To(c.checkWorkflowCallback).
+		Doc("show the confirmation page of the workflow callback").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Produces("text/html", restful.MIME_JSON).
+		Param(ws.PathParameter("token", "workflow callback token").DataType("string")).
+		Returns(200, "OK", apis.WorkflowCallbackResponse{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.WorkflowCallbackResponse{}))
+
+	ws.Route(ws.POST("/workflow-callbacks/{token}").To(c.handleWorkflowCallback).
+		Doc("approve or reject the suspended workflow").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Consumes(formContentType, restful.MIME_JSON).
+		Produces("text/html", restful.MIME_JSON).
+		Param(ws.PathParameter("token", "workflow callback token").DataType("string")).
+		Reads(apis.HandleWorkflowCallbackRequest{}).
+		Returns(200, "OK", apis.WorkflowCallbackResponse{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.WorkflowCallbackResponse{}))
 	return ws
 }
 
@@ -66,3 +95,84 @@ func (c *webhookAPIInterface) handleApplicationWebhook(req *restful.Request, res
 		return
 	}
 }
+
+const formContentType = "application/x-www-form-urlencoded"
+
+var workflowCallbackPage = template.Must(template.New("callback").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>KubeVela Workflow</title></head>
+<body>
+{{- if .Error }}
+<p>{{ .Error }}</p>
+{{- else if .Done }}
+<p>The workflow of application {{ .Application }} in namespace {{ .Namespace }} is {{ if eq .Action "approve" }}approved{{ else }}rejected{{ end }}.</p>
+{{- else }}
+<form method="post">
+<p>{{ if eq .Action "approve" }}Approve{{ else }}Reject{{ end }} the step {{ .Step }} of the workflow of application {{ .Application }} in namespace {{ .Namespace }}?</p>
+{{- if ne .Action "approve" }}
+<p><input type="text" name="message" placeholder="Reason"></p>
+{{- end }}
+<p><input type="submit" value="{{ if eq .Action "approve" }}Approve{{ else }}Reject{{ end }}"></p>
+</form>
+{{- end }}
+</body>
+</html>
+`))
+
+type workflowCallbackPageData struct {
+	*apis.WorkflowCallbackResponse
+	Done  bool
+	Error string
+}
+
+func (c *webhookAPIInterface) checkWorkflowCallback(req *restful.Request, res *restful.Response) {
+	callback, err := c.WorkflowService.CheckWorkflowCallback(req.Request.Context(), req.PathParameter("token"))
+	c.writeWorkflowCallback(req, res, callback, false, err)
+}
+
+func (c *webhookAPIInterface) handleWorkflowCallback(req *restful.Request, res *restful.Response) {
+	var message string
+	if strings.HasPrefix(req.HeaderParameter(restful.HEADER_ContentType), formContentType) {
+		message = req.Request.FormValue("message")
+	} else if req.Request.ContentLength > 0 {
+		var callbackReq apis.HandleWorkflowCallbackRequest
+		if err := req.ReadEntity(&callbackReq); err != nil {
+			bcode.ReturnError(req, res, err)
+			return
+		}
+		message = callbackReq.Message
+	}
+	callback, err := c.WorkflowService.HandleWorkflowCallback(req.Request.Context(), req.PathParameter("token"), message)
+	c.writeWorkflowCallback(req, res, callback, true, err)
+}
+
+// writeWorkflowCallback writes the html page for the browsers, and the json entity for the others
+func (c *webhookAPIInterface) writeWorkflowCallback(req *restful.Request, res *restful.Response, callback *apis.WorkflowCallbackResponse, done bool, err error) {
+	if !strings.Contains(req.HeaderParameter(restful.HEADER_Accept), "text/html") {
+		if err != nil {
+			bcode.ReturnError(req, res, err)
+			return
+		}
+		if err := res.WriteEntity(callback); err != nil {
+			bcode.ReturnError(req, res, err)
+		}
+		return
+	}
+	data := workflowCallbackPageData{WorkflowCallbackResponse: callback, Done: done}
+	status := http.StatusOK
+	if err != nil {
+		data.WorkflowCallbackResponse = &apis.WorkflowCallbackResponse{}
+		data.Error = err.Error()
+		status = http.StatusInternalServerError
+		var bc *bcode.Bcode
+		if errors.As(err, &bc) {
+			data.Error = bc.Message
+			status = int(bc.HTTPCode)
+		}
+	}
+	res.AddHeader(restful.HEADER_ContentType, "text/html; charset=utf-8")
+	res.WriteHeader(status)
+	if err := workflowCallbackPage.Execute(res, data); err != nil {
+		log.Logger.Errorf("failed to write the workflow callback page: %s", err.Error())
+	}
+}
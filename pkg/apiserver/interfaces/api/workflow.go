@@ -186,6 +186,20 @@ func (w *WorkflowAPIInterface) resumeWorkflowRecord(req *restful.Request, res *r
 	}
 }
 
+func (w *WorkflowAPIInterface) rejectWorkflowRecord(req *restful.Request, res *restful.Response) {
+	app := req.Request.Context().Value(&apis.CtxKeyApplication).(*model.Application)
+	workflow := req.Request.Context().Value(&apis.CtxKeyWorkflow).(*model.Workflow)
+	err := w.WorkflowService.RejectRecord(req.Request.Context(), app, workflow, req.PathParameter("record"), req.QueryParameter("message"))
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(apis.EmptyResponse{}); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
 func (w *WorkflowAPIInterface) terminateWorkflowRecord(req *restful.Request, res *restful.Response) {
 	app := req.Request.Context().Value(&apis.CtxKeyApplication).(*model.Application)
 	workflow := req.Request.Context().Value(&apis.CtxKeyWorkflow).(*model.Workflow)
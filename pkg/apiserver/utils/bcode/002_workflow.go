@@ -33,3 +33,18 @@ var ErrWorkflowNoEnv = NewBcode(400, 20006, "workflow must set env name")
 
 // ErrWorkflowRecordNotExist workflow record is not exist
 var ErrWorkflowRecordNotExist = NewBcode(404, 20007, "workflow record is not exist")
+
+// ErrInvalidWorkflowCallback the workflow callback token is invalid
+var ErrInvalidWorkflowCallback = NewBcode(400, 20008, "invalid workflow callback token")
+
+// ErrWorkflowCallbackExpired the workflow callback token is expired
+var ErrWorkflowCallbackExpired = NewBcode(400, 20009, "the workflow callback is expired")
+
+// ErrWorkflowCallbackOutdated the workflow is restarted after the callback is sent
+var ErrWorkflowCallbackOutdated = NewBcode(400, 20010, "the workflow callback is outdated, the workflow has been restarted")
+
+// ErrWorkflowNotSuspended the workflow is not suspended
+var ErrWorkflowNotSuspended = NewBcode(400, 20011, "the workflow is not suspended")
+
+// ErrWorkflowCallbackUsed the workflow callback token has been used
+var ErrWorkflowCallbackUsed = NewBcode(400, 20012, "the workflow callback has been used")
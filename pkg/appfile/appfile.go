@@ -187,6 +187,8 @@ type Appfile struct {
 	ExternalWorkflow *v1alpha1.Workflow
 	ReferredObjects  []*unstructured.Unstructured
 
+	ExternalSubWorkflows map[string]*v1alpha1.Workflow
+
 	parser *Parser
 	app    *v1beta1.Application
 
@@ -891,6 +893,31 @@ func (af *Appfile) WorkflowClient(cli client.Client) client.Client {
 	}
 }
 
+// SubWorkflowClient cache retrieved workflows of the sub-workflow steps if ApplicationRevision not exists in appfile
+// else use the workflows in ApplicationRevision
+func (af *Appfile) SubWorkflowClient(cli client.Client) client.Client {
+	return velaclient.DelegatingHandlerClient{
+		Client: cli,
+		Getter: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			if wf, ok := obj.(*v1alpha1.Workflow); ok {
+				if af.AppRevision != nil {
+					if w, found := af.ExternalSubWorkflows[key.String()]; found {
+						w.DeepCopyInto(wf)
+						return nil
+					}
+					return kerrors.NewNotFound(v1alpha1.SchemeGroupVersion.WithResource("workflow").GroupResource(), key.Name)
+				}
+				if err := cli.Get(ctx, key, obj); err != nil {
+					return err
+				}
+				af.ExternalSubWorkflows[key.String()] = obj.(*v1alpha1.Workflow)
+				return nil
+			}
+			return cli.Get(ctx, key, obj)
+		},
+	}
+}
+
 // PolicyClient cache retrieved policy if ApplicationRevision not exists in appfile
 // else use the policy in ApplicationRevision
 func (af *Appfile) PolicyClient(cli client.Client) client.Client {
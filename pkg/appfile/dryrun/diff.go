@@ -151,6 +151,13 @@ func (l *LiveDiffOption) RenderlessDiff(ctx context.Context, base, comparor Live
 				m.Subs = append(m.Subs, &manifest{Name: af.ExternalWorkflow.Name, Kind: WorkflowKind, Data: "Error: " + errors.Wrapf(err, "failed to marshal external workflow %s", af.ExternalWorkflow.Name).Error()})
 			}
 		}
+		for _, wf := range af.ExternalSubWorkflows {
+			if bs, err = marshalObject(wf); err == nil {
+				m.Subs = append(m.Subs, &manifest{Name: wf.Name, Kind: WorkflowKind, Data: string(bs)})
+			} else {
+				m.Subs = append(m.Subs, &manifest{Name: wf.Name, Kind: WorkflowKind, Data: "Error: " + errors.Wrapf(err, "failed to marshal sub workflow %s", wf.Name).Error()})
+			}
+		}
 		if af.ReferredObjects != nil {
 			for _, refObj := range af.ReferredObjects {
 				manifestName := fmt.Sprintf("%s %s %s", refObj.GetAPIVersion(), refObj.GetKind(), client.ObjectKeyFromObject(refObj).String())
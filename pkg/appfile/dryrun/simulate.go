@@ -0,0 +1,391 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/appfile"
+	appController "github.com/oam-dev/kubevela/pkg/controller/core.oam.dev/v1alpha2/application"
+	"github.com/oam-dev/kubevela/pkg/controller/core.oam.dev/v1alpha2/application/assemble"
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	"github.com/oam-dev/kubevela/pkg/cue/process"
+	monitorContext "github.com/oam-dev/kubevela/pkg/monitor/context"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/velaql/providers/query"
+	"github.com/oam-dev/kubevela/pkg/workflow"
+	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers/kube"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers/mock"
+	multiclusterProvider "github.com/oam-dev/kubevela/pkg/workflow/providers/multicluster"
+	oamProvider "github.com/oam-dev/kubevela/pkg/workflow/providers/oam"
+	secretProvider "github.com/oam-dev/kubevela/pkg/workflow/providers/secret"
+	terraformProvider "github.com/oam-dev/kubevela/pkg/workflow/providers/terraform"
+	"github.com/oam-dev/kubevela/pkg/workflow/tasks"
+	wfTypes "github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+// MaxSimulationRounds is the max rounds to execute the workflow in the simulation, the simulation stops
+// once the workflow reaches the terminal state or makes no progress.
+var MaxSimulationRounds = 100
+
+// WorkflowSimulation is the result of the workflow simulation
+type WorkflowSimulation struct {
+	// Mode is the execute mode of the steps
+	Mode common.WorkflowMode
+	// Steps are the steps of the workflow, including the generated ones
+	Steps []v1beta1.WorkflowStep
+	// OnSuccess, OnFailure and Finally are the exit handlers of the workflow
+	OnSuccess []v1beta1.WorkflowStep
+	OnFailure []v1beta1.WorkflowStep
+	Finally   []v1beta1.WorkflowStep
+	// Calls are the provider calls made by the steps in order
+	Calls []mock.Call
+	// State is the state of the workflow when the simulation stops
+	State common.WorkflowState
+	// Status is the status of the workflow when the simulation stops
+	Status *common.WorkflowStatus
+}
+
+// SimulateWorkflow executes the workflow of the application against a fake cluster. The providers that
+// talk to the cluster or the outside are swapped for fakes, and all the provider calls are recorded.
+func (d *Option) SimulateWorkflow(ctx context.Context, application *v1beta1.Application) (*WorkflowSimulation, error) {
+	app := application.DeepCopy()
+	if app.Namespace == "" {
+		app.Namespace = corev1.NamespaceDefault
+	}
+	if app.UID == "" {
+		app.UID = "dry-run"
+	}
+	app.Status = common.AppStatus{}
+	parser := appfile.NewDryRunApplicationParser(d.Client, d.DiscoveryMapper, d.PackageDiscover, d.Auxiliaries)
+	af, err := parser.GenerateAppFileFromApp(ctx, app)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot generate appFile from application")
+	}
+	appRev := generateSimulationRevision(app, af)
+
+	cli, err := newSimulationClient(app)
+	if err != nil {
+		return nil, err
+	}
+	logCtx := monitorContext.NewTraceContext(ctx, "dry-run")
+	recorder := mock.NewRecorder(providers.NewProviders())
+	dispatch := mock.NewDispatcher(cli)
+	apply := func(comp common.ApplicationComponent, patcher *value.Value, clusterName string, overrideNamespace string, env string) (*unstructured.Unstructured, []*unstructured.Unstructured, bool, error) {
+		workload, traits, err := renderSimulationComponent(parser, af, appRev, comp, patcher, clusterName, overrideNamespace)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if err := dispatch(ctx, clusterName, common.WorkflowResourceCreator, append([]*unstructured.Unstructured{workload}, traits...)...); err != nil {
+			return nil, nil, false, errors.WithMessage(err, "Dispatch")
+		}
+		return workload, traits, true, nil
+	}
+	render := func(comp common.ApplicationComponent, patcher *value.Value, clusterName string, overrideNamespace string, env string) (*unstructured.Unstructured, []*unstructured.Unstructured, error) {
+		return renderSimulationComponent(parser, af, appRev, comp, patcher, clusterName, overrideNamespace)
+	}
+	healthCheck := func(comp common.ApplicationComponent, patcher *value.Value, clusterName string, overrideNamespace string, env string) (bool, error) {
+		return true, nil
+	}
+	renderer := func(comp common.ApplicationComponent) (*appfile.Workload, error) {
+		return parser.ParseWorkloadFromRevision(comp, appRev)
+	}
+	kube.Install(recorder, app, cli, dispatch, mock.NewDeleter(cli))
+	oamProvider.Install(recorder, app, af, cli, apply, render)
+	multiclusterProvider.Install(recorder, cli, app, af, apply, healthCheck, renderer)
+	terraformProvider.Install(recorder, app, renderer)
+	query.Install(recorder, cli, nil)
+	secretProvider.Install(recorder, app, cli, dispatch)
+	pCtx := process.NewContext(process.ContextData{
+		Namespace:       app.Namespace,
+		AppName:         app.Name,
+		CompName:        app.Name,
+		AppRevisionName: appRev.Name,
+		WorkflowName:    app.Annotations[oam.AnnotationWorkflowName],
+		PublishVersion:  app.Annotations[oam.AnnotationPublishVersion],
+	})
+	taskDiscover := &simulatedTaskDiscover{
+		TaskDiscover: tasks.NewTaskDiscoverFromRevision(logCtx, recorder, d.PackageDiscover, appRev, d.DiscoveryMapper, pCtx),
+		recorder:     recorder,
+	}
+	// the builtin providers are installed by the task discover, replace them with the fakes
+	mock.InstallFakes(recorder)
+
+	sim := &WorkflowSimulation{Mode: af.WorkflowMode, Steps: af.WorkflowSteps}
+	if wf := app.Spec.Workflow; wf != nil {
+		sim.OnSuccess, sim.OnFailure, sim.Finally = wf.OnSuccess, wf.OnFailure, wf.Finally
+	}
+	generateSteps := func(steps []v1beta1.WorkflowStep) ([]wfTypes.TaskRunner, error) {
+		var runners []wfTypes.TaskRunner
+		for _, step := range steps {
+			runner, err := appController.GenerateWorkflowStep(logCtx, app, step, taskDiscover, d.PackageDiscover, pCtx)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "generate step %s", step.Name)
+			}
+			runners = append(runners, runner)
+		}
+		return runners, nil
+	}
+	runners, err := generateSteps(sim.Steps)
+	if err != nil {
+		return nil, err
+	}
+	wf := workflow.NewWorkflow(app, cli, af.WorkflowMode, false, nil)
+	if len(sim.OnSuccess) > 0 || len(sim.OnFailure) > 0 || len(sim.Finally) > 0 {
+		handlers := &wfTypes.ExitHandlers{}
+		if handlers.OnSuccess, err = generateSteps(sim.OnSuccess); err != nil {
+			return nil, err
+		}
+		if handlers.OnFailure, err = generateSteps(sim.OnFailure); err != nil {
+			return nil, err
+		}
+		if handlers.Finally, err = generateSteps(sim.Finally); err != nil {
+			return nil, err
+		}
+		wf.SetExitHandlers(handlers)
+	}
+
+	lastPhases := ""
+	for i := 0; i < MaxSimulationRounds; i++ {
+		if sim.State, err = wf.ExecuteSteps(logCtx, appRev, runners); err != nil {
+			return nil, errors.WithMessage(err, "execute workflow")
+		}
+		if sim.State != common.WorkflowStateInitializing && sim.State != common.WorkflowStateExecuting &&
+			sim.State != common.WorkflowStateSkipping {
+			break
+		}
+		phases := getStepPhases(app.Status.Workflow)
+		if sim.State == common.WorkflowStateExecuting && phases == lastPhases {
+			break
+		}
+		lastPhases = phases
+	}
+	sim.Calls = recorder.Calls()
+	sim.Status = app.Status.Workflow
+	return sim, nil
+}
+
+// PrintWorkflowSimulation will print the result of the workflow simulation
+func (d *Option) PrintWorkflowSimulation(buff *bytes.Buffer, appName string, sim *WorkflowSimulation) error {
+	if _, err := fmt.Fprintf(buff, "---\n# Application(%s) -- Workflow(%s) \n---\n\n", appName, sim.Mode); err != nil {
+		return errors.Wrap(err, "fail to write buff")
+	}
+	buff.WriteString("## Steps\n")
+	printSimulationSteps(buff, sim.Steps, "")
+	for _, handler := range []struct {
+		name  string
+		steps []v1beta1.WorkflowStep
+	}{{"onSuccess", sim.OnSuccess}, {"onFailure", sim.OnFailure}, {"finally", sim.Finally}} {
+		if len(handler.steps) > 0 {
+			buff.WriteString(fmt.Sprintf("## Steps (%s)\n", handler.name))
+			printSimulationSteps(buff, handler.steps, "")
+		}
+	}
+
+	buff.WriteString("\n## Provider Calls\n")
+	for _, call := range sim.Calls {
+		buff.WriteString(fmt.Sprintf("- step: %s, call: %s.%s\n", call.Step, call.Provider, call.Handler))
+		if call.Error != "" {
+			buff.WriteString(fmt.Sprintf("  error: %s\n", call.Error))
+		}
+		buff.WriteString("  params:\n")
+		for _, line := range strings.Split(strings.TrimSpace(call.Params), "\n") {
+			buff.WriteString("    " + line + "\n")
+		}
+	}
+
+	buff.WriteString("\n## Step Phases\n")
+	if sim.Status != nil {
+		for _, step := range sim.Status.Steps {
+			printSimulationStepStatus(buff, step.StepStatus, "")
+			for _, sub := range step.SubStepsStatus {
+				printSimulationStepStatus(buff, sub.StepStatus, "  ")
+			}
+		}
+	}
+	buff.WriteString(fmt.Sprintf("\nWorkflow State: %s\n", sim.State))
+	return nil
+}
+
+func printSimulationSteps(buff *bytes.Buffer, steps []v1beta1.WorkflowStep, indent string) {
+	for _, step := range steps {
+		line := fmt.Sprintf("%s- %s (%s)", indent, step.Name, step.Type)
+		if len(step.DependsOn) > 0 {
+			line += fmt.Sprintf(" dependsOn: [%s]", strings.Join(step.DependsOn, ", "))
+		}
+		if step.If != "" {
+			line += fmt.Sprintf(" if: %s", step.If)
+		}
+		buff.WriteString(line + "\n")
+		for _, sub := range step.SubSteps {
+			printSimulationSteps(buff, []v1beta1.WorkflowStep{{
+				Name:      sub.Name,
+				Type:      sub.Type,
+				DependsOn: sub.DependsOn,
+				If:        sub.If,
+			}}, indent+"  ")
+		}
+	}
+}
+
+func printSimulationStepStatus(buff *bytes.Buffer, status common.StepStatus, indent string) {
+	line := fmt.Sprintf("%s- %s: %s", indent, status.Name, status.Phase)
+	if status.Reason != "" {
+		line += fmt.Sprintf(" (%s)", status.Reason)
+	}
+	if status.Message != "" {
+		line += fmt.Sprintf(" %s", status.Message)
+	}
+	buff.WriteString(line + "\n")
+}
+
+func getStepPhases(status *common.WorkflowStatus) string {
+	if status == nil {
+		return ""
+	}
+	var phases []string
+	for _, step := range status.Steps {
+		phases = append(phases, fmt.Sprintf("%s:%s", step.Name, step.Phase))
+		for _, sub := range step.SubStepsStatus {
+			phases = append(phases, fmt.Sprintf("%s:%s", sub.Name, sub.Phase))
+		}
+	}
+	return strings.Join(phases, ",")
+}
+
+func newSimulationClient(app *v1beta1.Application) (client.Client, error) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1beta1.AddToScheme(scheme))
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+	if err := cli.Create(context.Background(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: app.Namespace}}); err != nil {
+		return nil, err
+	}
+	if err := cli.Create(context.Background(), app.DeepCopy()); err != nil {
+		return nil, err
+	}
+	return cli, nil
+}
+
+func generateSimulationRevision(app *v1beta1.Application, af *appfile.Appfile) *v1beta1.ApplicationRevision {
+	appRev := &v1beta1.ApplicationRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: app.Name + "-v1", Namespace: app.Namespace},
+		Spec: v1beta1.ApplicationRevisionSpec{
+			Application:             *app.DeepCopy(),
+			ComponentDefinitions:    make(map[string]v1beta1.ComponentDefinition),
+			TraitDefinitions:        make(map[string]v1beta1.TraitDefinition),
+			PolicyDefinitions:       make(map[string]v1beta1.PolicyDefinition),
+			WorkflowStepDefinitions: make(map[string]v1beta1.WorkflowStepDefinition),
+		},
+	}
+	for name, def := range af.RelatedComponentDefinitions {
+		appRev.Spec.ComponentDefinitions[name] = *def
+	}
+	for name, def := range af.RelatedTraitDefinitions {
+		appRev.Spec.TraitDefinitions[name] = *def
+	}
+	for name, def := range af.RelatedWorkflowStepDefinitions {
+		appRev.Spec.WorkflowStepDefinitions[name] = *def
+	}
+	for _, p := range af.PolicyWorkloads {
+		if p != nil && p.FullTemplate != nil && p.FullTemplate.PolicyDefinition != nil {
+			appRev.Spec.PolicyDefinitions[p.FullTemplate.PolicyDefinition.Name] = *p.FullTemplate.PolicyDefinition
+		}
+	}
+	return appRev
+}
+
+func renderSimulationComponent(parser *appfile.Parser, af *appfile.Appfile, appRev *v1beta1.ApplicationRevision, comp common.ApplicationComponent, patcher *value.Value, clusterName string, overrideNamespace string) (*unstructured.Unstructured, []*unstructured.Unstructured, error) {
+	wl, err := parser.ParseWorkloadFromRevision(comp, appRev)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "ParseWorkload")
+	}
+	wl.Patch = patcher
+	manifest, err := af.GenerateComponentManifest(wl, func(ctxData *process.ContextData) {
+		if overrideNamespace != "" {
+			ctxData.Namespace = overrideNamespace
+		}
+	})
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "GenerateComponentManifest")
+	}
+	if err := af.SetOAMContract(manifest); err != nil {
+		return nil, nil, errors.WithMessage(err, "SetOAMContract")
+	}
+	workload, traits, err := assemble.PrepareBeforeApply(manifest, appRev, nil)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "assemble resources before apply fail")
+	}
+	for _, obj := range append([]*unstructured.Unstructured{workload}, traits...) {
+		if clusterName != "" {
+			oam.SetCluster(obj, clusterName)
+		}
+		if overrideNamespace != "" {
+			obj.SetNamespace(overrideNamespace)
+		}
+	}
+	return workload, traits, nil
+}
+
+// simulatedTaskDiscover wraps the task runners to attribute the provider calls to the steps
+type simulatedTaskDiscover struct {
+	wfTypes.TaskDiscover
+	recorder *mock.Recorder
+}
+
+// GetTaskGenerator get task generator by name.
+func (td *simulatedTaskDiscover) GetTaskGenerator(ctx context.Context, name string) (wfTypes.TaskGenerator, error) {
+	gen, err := td.TaskDiscover.GetTaskGenerator(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return func(step v1beta1.WorkflowStep, opt *wfTypes.GeneratorOptions) (wfTypes.TaskRunner, error) {
+		runner, err := gen(step, opt)
+		if err != nil {
+			return nil, err
+		}
+		return &simulatedTaskRunner{TaskRunner: runner, recorder: td.recorder}, nil
+	}, nil
+}
+
+type simulatedTaskRunner struct {
+	wfTypes.TaskRunner
+	recorder *mock.Recorder
+}
+
+// Run records the step name before running the step.
+func (tr *simulatedTaskRunner) Run(ctx wfContext.Context, options *wfTypes.TaskRunOptions) (common.StepStatus, *wfTypes.Operation, error) {
+	tr.recorder.SetStep(tr.Name())
+	return tr.TaskRunner.Run(ctx, options)
+}
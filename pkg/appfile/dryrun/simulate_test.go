@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	common2 "github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestSimulateWorkflow(t *testing.T) {
+	r := require.New(t)
+	compDef := &unstructured.Unstructured{}
+	r.NoError(yaml.Unmarshal([]byte(simulateCompDef), &compDef.Object))
+	stepDef := &v1beta1.WorkflowStepDefinition{}
+	r.NoError(yaml.Unmarshal([]byte(simulateStepDef), stepDef))
+	app := &v1beta1.Application{}
+	r.NoError(yaml.Unmarshal([]byte(simulateApp), app))
+
+	cli := fake.NewClientBuilder().WithScheme(common2.Scheme).WithObjects(stepDef).Build()
+	d := NewDryRunOption(cli, nil, nil, nil, []oam.Object{compDef}, false)
+	sim, err := d.SimulateWorkflow(context.Background(), app)
+	r.NoError(err)
+	r.Equal(common.WorkflowStateSucceeded, sim.State)
+	r.Equal(3, len(sim.Status.Steps))
+	r.Equal(common.WorkflowStepPhaseSucceeded, sim.Status.Steps[0].Phase)
+	r.Equal(common.WorkflowStepPhaseSucceeded, sim.Status.Steps[1].Phase)
+	r.Equal(common.WorkflowStepPhaseSkipped, sim.Status.Steps[2].Phase)
+
+	r.Equal(2, len(sim.Calls))
+	r.Equal("apply", sim.Calls[0].Step)
+	r.Equal("oam", sim.Calls[0].Provider)
+	r.Equal("component-apply", sim.Calls[0].Handler)
+	r.Equal("notify", sim.Calls[1].Step)
+	r.Equal("http", sim.Calls[1].Provider)
+	r.Contains(sim.Calls[1].Params, "https://example.com/hook")
+
+	buff := &bytes.Buffer{}
+	r.NoError(d.PrintWorkflowSimulation(buff, app.Name, sim))
+	r.Contains(buff.String(), "- notify (notify) dependsOn: [apply]")
+	r.Contains(buff.String(), "- step: notify, call: http.do")
+	r.Contains(buff.String(), "- rollback: skipped")
+}
+
+var simulateCompDef = `apiVersion: core.oam.dev/v1beta1
+kind: ComponentDefinition
+metadata:
+  name: config
+  namespace: vela-system
+spec:
+  workload:
+    definition:
+      apiVersion: v1
+      kind: ConfigMap
+  schematic:
+    cue:
+      template: |
+        output: {
+        	apiVersion: "v1"
+        	kind:       "ConfigMap"
+        	metadata: name: context.name
+        	data: key:      parameter.value
+        }
+        parameter: value: string
+`
+
+var simulateStepDef = `apiVersion: core.oam.dev/v1beta1
+kind: WorkflowStepDefinition
+metadata:
+  name: notify
+  namespace: vela-system
+spec:
+  schematic:
+    cue:
+      template: |
+        import "vela/op"
+
+        req: op.#HTTPPost & {
+        	url: parameter.url
+        	request: body: "deployed"
+        }
+        parameter: url: string
+`
+
+var simulateApp = `apiVersion: core.oam.dev/v1beta1
+kind: Application
+metadata:
+  name: app
+  namespace: default
+spec:
+  components:
+    - name: config
+      type: config
+      properties:
+        value: hello
+  workflow:
+    steps:
+      - name: apply
+        type: apply-component
+        properties:
+          component: config
+      - name: notify
+        type: notify
+        dependsOn: ["apply"]
+        properties:
+          url: https://example.com/hook
+      - name: rollback
+        type: notify
+        if: status.apply.failed
+        properties:
+          url: https://example.com/rollback
+`
@@ -181,7 +181,8 @@ func (p *Parser) newAppfile(appName, ns string, app *v1beta1.Application) *Appfi
 		RelatedScopeDefinitions:        make(map[string]*v1beta1.ScopeDefinition),
 		RelatedWorkflowStepDefinitions: make(map[string]*v1beta1.WorkflowStepDefinition),
 
-		ExternalPolicies: make(map[string]*v1alpha1.Policy),
+		ExternalPolicies:     make(map[string]*v1alpha1.Policy),
+		ExternalSubWorkflows: make(map[string]*v1alpha1.Workflow),
 
 		parser: p,
 		app:    app,
@@ -262,6 +263,9 @@ func (p *Parser) GenerateAppFileFromRevision(appRev *v1beta1.ApplicationRevision
 		appfile.ExternalPolicies[key] = po.DeepCopy()
 	}
 	appfile.ExternalWorkflow = appRev.Spec.Workflow
+	for key, wf := range appRev.Spec.SubWorkflows {
+		appfile.ExternalSubWorkflows[key] = wf.DeepCopy()
+	}
 
 	var wds []*Workload
 	for _, comp := range app.Spec.Components {
@@ -385,6 +389,8 @@ func (p *Parser) parsePoliciesFromRevision(ctx context.Context, af *Appfile) (er
 		case v1alpha1.GarbageCollectPolicyType:
 		case v1alpha1.ApplyOncePolicyType:
 		case v1alpha1.SharedResourcePolicyType:
+		case v1alpha1.ReadOnlyPolicyType:
+		case v1alpha1.TakeOverPolicyType:
 		case v1alpha1.EnvBindingPolicyType:
 		case v1alpha1.TopologyPolicyType:
 		case v1alpha1.OverridePolicyType:
@@ -411,6 +417,8 @@ func (p *Parser) parsePolicies(ctx context.Context, af *Appfile) (err error) {
 		case v1alpha1.GarbageCollectPolicyType:
 		case v1alpha1.ApplyOncePolicyType:
 		case v1alpha1.SharedResourcePolicyType:
+		case v1alpha1.ReadOnlyPolicyType:
+		case v1alpha1.TakeOverPolicyType:
 		case v1alpha1.EnvBindingPolicyType:
 		case v1alpha1.TopologyPolicyType:
 		case v1alpha1.DebugPolicyType:
@@ -451,6 +459,7 @@ func (p *Parser) loadWorkflowToAppfile(ctx context.Context, af *Appfile) error {
 	}
 	af.WorkflowSteps, err = step.NewChainWorkflowStepGenerator(
 		&step.RefWorkflowStepGenerator{Client: af.WorkflowClient(p.client), Context: ctx},
+		&step.SubWorkflowStepGenerator{Client: af.SubWorkflowClient(p.client), Context: ctx},
 		&step.DeployWorkflowStepGenerator{},
 		&step.Deploy2EnvWorkflowStepGenerator{},
 		&step.ApplyComponentWorkflowStepGenerator{},
@@ -467,7 +476,15 @@ func (p *Parser) parseWorkflowSteps(ctx context.Context, af *Appfile) error {
 	if err := p.loadWorkflowToAppfile(ctx, af); err != nil {
 		return err
 	}
-	for _, workflowStep := range af.WorkflowSteps {
+	var workflowSteps []v1beta1.WorkflowStep
+	workflowSteps = append(workflowSteps, af.WorkflowSteps...)
+	if wf := af.app.Spec.Workflow; wf != nil {
+		// the definitions of the exit handlers should also be recorded in the revision
+		workflowSteps = append(workflowSteps, wf.OnSuccess...)
+		workflowSteps = append(workflowSteps, wf.OnFailure...)
+		workflowSteps = append(workflowSteps, wf.Finally...)
+	}
+	for _, workflowStep := range workflowSteps {
 		err := p.parseWorkflowStep(ctx, af, workflowStep.Type)
 		if err != nil {
 			return err
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	authv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// selfSubjectReviewVersions are the versions of the SelfSubjectReview API tried in order, the API is not served
+// by the clusters before v1.26 and the client of it is not vendored yet
+var selfSubjectReviewVersions = []string{"v1", "v1beta1", "v1alpha1"}
+
+type selfSubjectReview struct {
+	metav1.TypeMeta `json:",inline"`
+	Status          struct {
+		UserInfo authv1.UserInfo `json:"userInfo"`
+	} `json:"status"`
+}
+
+// ReviewIdentity asks the kubernetes apiserver for the identity authenticated with the rest config, so the identity
+// is the same as the one seen by the apiserver whichever authentication (certificate, token, exec plugin, OIDC...)
+// is used. It uses the SelfSubjectReview API and falls back to TokenReview for the clusters not serving it.
+func ReviewIdentity(ctx context.Context, cfg *rest.Config) (*Identity, error) {
+	cli, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range selfSubjectReviewVersions {
+		review := &selfSubjectReview{TypeMeta: metav1.TypeMeta{APIVersion: authv1.GroupName + "/" + version, Kind: "SelfSubjectReview"}}
+		body, err := json.Marshal(review)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := cli.AuthenticationV1().RESTClient().Post().
+			AbsPath("/apis", authv1.GroupName, version, "selfsubjectreviews").
+			Body(body).Do(ctx).Raw()
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to review the identity with SelfSubjectReview: %w", err)
+		}
+		if err = json.Unmarshal(raw, review); err != nil {
+			return nil, err
+		}
+		return identityFromUserInfo(review.Status.UserInfo)
+	}
+
+	token := cfg.BearerToken
+	if token == "" && cfg.BearerTokenFile != "" {
+		bs, err := ioutil.ReadFile(cfg.BearerTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read token file %s: %w", cfg.BearerTokenFile, err)
+		}
+		token = strings.TrimSpace(string(bs))
+	}
+	if token == "" {
+		return nil, fmt.Errorf("SelfSubjectReview is not served by the cluster and there is no bearer token for TokenReview")
+	}
+	review, err := cli.AuthenticationV1().TokenReviews().Create(ctx, &authv1.TokenReview{Spec: authv1.TokenReviewSpec{Token: token}}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to review the identity with TokenReview: %w", err)
+	}
+	if !review.Status.Authenticated {
+		return nil, fmt.Errorf("the token is not authenticated: %s", review.Status.Error)
+	}
+	return identityFromUserInfo(review.Status.User)
+}
+
+func identityFromUserInfo(userInfo authv1.UserInfo) (*Identity, error) {
+	if userInfo.Username == "" {
+		return nil, fmt.Errorf("the username is not returned by the apiserver")
+	}
+	if namespace, name, err := serviceaccount.SplitUsername(userInfo.Username); err == nil {
+		return &Identity{ServiceAccount: name, ServiceAccountNamespace: namespace}, nil
+	}
+	return &Identity{User: userInfo.Username, Groups: userInfo.Groups}, nil
+}
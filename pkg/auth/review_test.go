@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authentication/v1"
+	"k8s.io/client-go/rest"
+)
+
+func TestReviewIdentity(t *testing.T) {
+	testCases := map[string]struct {
+		Path         string
+		Token        string
+		Response     string
+		ExpectErr    bool
+		ExpectResult *Identity
+	}{
+		"self-subject-review": {
+			Path:         "/apis/authentication.k8s.io/v1beta1/selfsubjectreviews",
+			Response:     `{"apiVersion":"authentication.k8s.io/v1beta1","kind":"SelfSubjectReview","status":{"userInfo":{"username":"oidc:alice","groups":["sre"]}}}`,
+			ExpectResult: &Identity{User: "oidc:alice", Groups: []string{"sre"}},
+		},
+		"token-review": {
+			Path:         "/apis/authentication.k8s.io/v1/tokenreviews",
+			Token:        "token",
+			Response:     `{"apiVersion":"authentication.k8s.io/v1","kind":"TokenReview","status":{"authenticated":true,"user":{"username":"system:serviceaccount:vela:approver","groups":["system:serviceaccounts"]}}}`,
+			ExpectResult: &Identity{ServiceAccount: "approver", ServiceAccountNamespace: "vela"},
+		},
+		"no-token": {
+			Path:      "/apis/authentication.k8s.io/v1/tokenreviews",
+			Response:  `{}`,
+			ExpectErr: true,
+		},
+	}
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.URL.Path != tt.Path {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				if tt.Token != "" {
+					review := &authv1.TokenReview{}
+					r.NoError(json.NewDecoder(req.Body).Decode(review))
+					r.Equal(tt.Token, review.Spec.Token)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.Response))
+			}))
+			defer server.Close()
+			identity, err := ReviewIdentity(context.Background(), &rest.Config{Host: server.URL, BearerToken: tt.Token})
+			if tt.ExpectErr {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.ExpectResult, identity)
+		})
+	}
+}
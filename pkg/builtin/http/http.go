@@ -34,6 +34,9 @@ func init() {
 	registry.RegisterRunner("http", newHTTPCmd)
 }
 
+// DefaultTimeout is the default timeout of the http request
+const DefaultTimeout = 3 * time.Second
+
 // HTTPCmd provides methods for http task
 type HTTPCmd struct{}
 
@@ -52,9 +55,18 @@ func (c *HTTPCmd) Run(meta *registry.Meta) (res interface{}, err error) {
 		r      io.Reader
 		client = &http.Client{
 			Transport: http.DefaultTransport,
-			Timeout:   time.Second * 3,
+			Timeout:   DefaultTimeout,
 		}
 	)
+	if timeout := meta.Obj.Lookup("timeout"); timeout.Exists() {
+		str, err := timeout.String()
+		if err != nil {
+			return nil, errors.WithMessage(err, "parse timeout")
+		}
+		if client.Timeout, err = time.ParseDuration(str); err != nil {
+			return nil, errors.Wrap(err, "parse timeout")
+		}
+	}
 	if obj := meta.Obj.Lookup("request"); obj.Exists() {
 		if v := obj.Lookup("body"); v.Exists() {
 			r, err = v.Reader()
@@ -126,11 +138,12 @@ func (c *HTTPCmd) Run(meta *registry.Meta) (res interface{}, err error) {
 	//nolint:errcheck
 	defer resp.Body.Close()
 	b, err := io.ReadAll(resp.Body)
-	// parse response body and headers
+	// parse response body, headers and status code
 	return map[string]interface{}{
-		"body":    string(b),
-		"header":  resp.Header,
-		"trailer": resp.Trailer,
+		"body":       string(b),
+		"header":     resp.Header,
+		"trailer":    resp.Trailer,
+		"statusCode": resp.StatusCode,
 	}, err
 }
 
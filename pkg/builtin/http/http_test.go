@@ -100,6 +100,39 @@ func TestHTTPCmdRun(t *testing.T) {
 
 }
 
+func TestHTTPCmdTimeout(t *testing.T) {
+	s := NewMock()
+	defer s.Close()
+
+	r := cue.Runtime{}
+	runner, _ := newHTTPCmd(cue.Value{})
+	reqInst, err := r.Compile("", `
+method: "GET"
+url: "http://127.0.0.1:8090/api/v1/token?val=test-token"
+timeout: "10s"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := runner.Run(&registry.Meta{Obj: reqInst.Value()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 200, (got.(map[string]interface{}))["statusCode"].(int))
+
+	reqInst, err = r.Compile("", `
+method: "GET"
+url: "http://127.0.0.1:8090/api/v1/token?val=test-token"
+timeout: "ten seconds"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runner.Run(&registry.Meta{Obj: reqInst.Value()}); err == nil {
+		t.Error("expect error for invalid timeout")
+	}
+}
+
 func TestHTTPSRun(t *testing.T) {
 	s := newMockHttpsServer()
 	defer s.Close()
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -42,6 +43,7 @@ import (
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/condition"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	velatypes "github.com/oam-dev/kubevela/apis/types"
 	"github.com/oam-dev/kubevela/pkg/appfile"
@@ -58,6 +60,7 @@ import (
 	"github.com/oam-dev/kubevela/pkg/resourcetracker"
 	"github.com/oam-dev/kubevela/pkg/workflow"
 	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
+	jobProvider "github.com/oam-dev/kubevela/pkg/workflow/providers/job"
 	"github.com/oam-dev/kubevela/version"
 )
 
@@ -83,6 +86,7 @@ var (
 // Reconciler reconciles an Application object
 type Reconciler struct {
 	client.Client
+	readLogs jobProvider.LogReader
 	dm       discoverymapper.DiscoveryMapper
 	pd       *packages.PackageDiscover
 	Scheme   *runtime.Scheme
@@ -103,7 +107,7 @@ type options struct {
 
 // Reconcile process app event
 // nolint:gocyclo
-func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 
 	ctx, cancel := context.WithTimeout(ctx, common2.ReconcileTimeout)
 	defer cancel()
@@ -154,6 +158,30 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return result, nil
 	}
 
+	scheduled, err := checkWorkflowSchedule(app, time.Now())
+	if err != nil {
+		r.Recorder.Event(app, event.Warning(velatypes.ReasonFailedSchedule, err))
+		return r.endWithNegativeCondition(logCtx, app, condition.ErrorCondition("Schedule", err), app.Status.Phase)
+	}
+	// requeue the application at the next scheduled time of the workflow
+	defer func() {
+		if scheduled.next > 0 && (result.RequeueAfter == 0 || scheduled.next < result.RequeueAfter) {
+			result.RequeueAfter = scheduled.next
+		}
+	}()
+	if scheduled.restart {
+		logCtx.Info("Restart workflow by schedule")
+		r.Recorder.Event(app, event.Normal(velatypes.ReasonScheduled, velatypes.MessageScheduled))
+		// reset the workflow status to restart the workflow
+		app.Status.Workflow = nil
+		return r.result(r.updateStatus(logCtx, app, common.ApplicationRunningWorkflow)).ret()
+	}
+	if scheduled.statusChanged {
+		if err := r.patchStatus(logCtx, app, app.Status.Phase); err != nil {
+			return r.result(errors.WithMessage(err, "cannot update scheduled runs")).ret()
+		}
+	}
+
 	appFile, err := appParser.GenerateAppFile(logCtx, app)
 	if err != nil {
 		r.Recorder.Event(app, event.Warning(velatypes.ReasonFailedParse, err))
@@ -200,6 +228,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	app.Status.SetConditions(condition.ReadyCondition(common.RenderCondition.String()))
 	r.Recorder.Event(app, event.Normal(velatypes.ReasonRendered, velatypes.MessageRendered))
 	wf := workflow.NewWorkflow(app, r.Client, appFile.WorkflowMode, appFile.Debug, handler.resourceKeeper)
+	wf.SetExitHandlers(handler.exitHandlers)
 	workflowState, err := wf.ExecuteSteps(logCtx.Fork("workflow"), handler.currentAppRev, steps)
 	if err != nil {
 		logCtx.Error(err, "[handle workflow]")
@@ -290,7 +319,57 @@ func (r *Reconciler) stateKeep(logCtx monitorContext.Context, handler *AppHandle
 		logCtx.Error(err, "Failed to run prevent-configuration-drift")
 		r.Recorder.Event(app, event.Warning(velatypes.ReasonFailedStateKeep, err))
 		app.Status.SetConditions(condition.ErrorCondition("StateKeep", err))
+		return
+	}
+	r.recordDrifts(app, handler.resourceKeeper.Drifts())
+}
+
+// recordDrifts records the drift detected by the state keep as the condition, events and metrics of the application
+func (r *Reconciler) recordDrifts(app *v1beta1.Application, drifts []resourcekeeper.DriftedResource) {
+	driftCondition := condition.ConditionType(common.DriftCondition.String())
+	if len(drifts) == 0 {
+		metrics.DriftedResourceGauge.DeleteLabelValues(app.Namespace, app.Name)
+		if app.Status.GetCondition(driftCondition).Status == corev1.ConditionTrue {
+			app.Status.SetConditions(condition.Condition{
+				Type:               driftCondition,
+				Status:             corev1.ConditionFalse,
+				LastTransitionTime: metav1.Now(),
+				Reason:             condition.ReasonAvailable,
+			})
+		}
+		return
+	}
+	reason, messages := velatypes.ReasonDriftCorrected, make([]string, 0, len(drifts))
+	for _, drift := range drifts {
+		messages = append(messages, drift.String())
+		if drift.Mode != v1alpha1.DriftDetectionModeCorrect {
+			reason = velatypes.ReasonDriftDetected
+		}
+	}
+	message := strings.Join(messages, "; ")
+	metrics.DriftedResourceGauge.WithLabelValues(app.Namespace, app.Name).Set(float64(len(drifts)))
+	if cond := app.Status.GetCondition(driftCondition); cond.Status == corev1.ConditionTrue && cond.Message == message {
+		// the same drifts are already recorded, the events and the counter are only for the changes
+		return
+	}
+	for _, drift := range drifts {
+		metrics.ResourceDriftCounter.WithLabelValues(drift.Resource.Kind, string(drift.Mode)).Inc()
+		switch drift.Mode {
+		case v1alpha1.DriftDetectionModeAlert:
+			r.Recorder.Event(app, event.Warning(velatypes.ReasonDriftDetected, errors.New(drift.String())))
+		case v1alpha1.DriftDetectionModeReport:
+			r.Recorder.Event(app, event.Normal(velatypes.ReasonDriftDetected, drift.String()))
+		default:
+			r.Recorder.Event(app, event.Normal(velatypes.ReasonDriftCorrected, drift.String()))
+		}
 	}
+	app.Status.SetConditions(condition.Condition{
+		Type:               driftCondition,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             condition.ConditionReason(reason),
+		Message:            message,
+	})
 }
 
 func (r *Reconciler) gcResourceTrackers(logCtx monitorContext.Context, handler *AppHandler, phase common.ApplicationPhase, gcOutdated bool, isPatch bool) (ctrl.Result, error) {
@@ -384,6 +463,7 @@ func (r *Reconciler) handleFinalizers(ctx monitorContext.Context, app *v1beta1.A
 			}
 			if rootRT == nil && currentRT == nil && len(historyRTs) == 0 && cvRT == nil {
 				meta.RemoveFinalizer(app, resourceTrackerFinalizer)
+				metrics.DriftedResourceGauge.DeleteLabelValues(app.Namespace, app.Name)
 				return r.result(errors.Wrap(r.Client.Update(ctx, app), errUpdateApplicationFinalizer)).end(true)
 			}
 			if wfContext.EnableInMemoryContext {
@@ -546,6 +626,7 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 func Setup(mgr ctrl.Manager, args core.Args) error {
 	reconciler := Reconciler{
 		Client:   mgr.GetClient(),
+		readLogs: jobProvider.NewLogReader(mgr.GetConfig()),
 		Scheme:   mgr.GetScheme(),
 		Recorder: event.NewAPIRecorder(mgr.GetEventRecorderFor("Application")),
 		dm:       args.DiscoveryMapper,
@@ -33,7 +33,9 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -49,6 +51,7 @@ import (
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/condition"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha2"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	stdv1alpha1 "github.com/oam-dev/kubevela/apis/standard.oam.dev/v1alpha1"
@@ -57,6 +60,7 @@ import (
 	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/oam/testutil"
 	"github.com/oam-dev/kubevela/pkg/oam/util"
+	"github.com/oam-dev/kubevela/pkg/resourcekeeper"
 	common2 "github.com/oam-dev/kubevela/pkg/utils/common"
 	"github.com/oam-dev/kubevela/pkg/workflow"
 	"github.com/oam-dev/kubevela/pkg/workflow/debug"
@@ -6429,3 +6433,50 @@ func newMockHTTP() *httptest.Server {
 	ts.Start()
 	return ts
 }
+
+type countingRecorder struct {
+	events []event.Event
+}
+
+func (c *countingRecorder) Event(obj runtime.Object, e event.Event) {
+	c.events = append(c.events, e)
+}
+
+func (c *countingRecorder) WithAnnotations(keysAndValues ...string) event.Recorder {
+	return c
+}
+
+func TestRecordDrifts(t *testing.T) {
+	r := require.New(t)
+	recorder := &countingRecorder{}
+	reconciler := &Reconciler{Recorder: recorder}
+	app := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	driftCondition := condition.ConditionType(common.DriftCondition.String())
+	drift := resourcekeeper.DriftedResource{
+		Resource: v1beta1.ManagedResource{ClusterObjectReference: common.ClusterObjectReference{ObjectReference: corev1.ObjectReference{Kind: "ConfigMap", Namespace: "default", Name: "cm"}}},
+		Mode:     v1alpha1.DriftDetectionModeAlert,
+		Fields:   []resourcekeeper.DriftedField{{Path: "data.key"}},
+	}
+
+	reconciler.recordDrifts(app, []resourcekeeper.DriftedResource{drift})
+	r.Len(recorder.events, 1)
+	r.Equal(corev1.ConditionTrue, app.Status.GetCondition(driftCondition).Status)
+	transition := metav1.NewTime(time.Now().Add(-time.Hour))
+	for i := range app.Status.Conditions {
+		app.Status.Conditions[i].LastTransitionTime = transition
+	}
+
+	// the same drifts are not recorded again
+	reconciler.recordDrifts(app, []resourcekeeper.DriftedResource{drift})
+	r.Len(recorder.events, 1)
+	r.Equal(transition, app.Status.GetCondition(driftCondition).LastTransitionTime)
+
+	// the changed drifts are recorded
+	drift.Fields = append(drift.Fields, resourcekeeper.DriftedField{Path: "data.other"})
+	reconciler.recordDrifts(app, []resourcekeeper.DriftedResource{drift})
+	r.Len(recorder.events, 2)
+	r.NotEqual(transition, app.Status.GetCondition(driftCondition).LastTransitionTime)
+
+	reconciler.recordDrifts(app, nil)
+	r.Equal(corev1.ConditionFalse, app.Status.GetCondition(driftCondition).Status)
+}
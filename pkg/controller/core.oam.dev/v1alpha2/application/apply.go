@@ -40,6 +40,7 @@ import (
 	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/oam/util"
 	"github.com/oam-dev/kubevela/pkg/resourcekeeper"
+	wfTypes "github.com/oam-dev/kubevela/pkg/workflow/types"
 )
 
 // AppHandler handles application reconcile
@@ -57,6 +58,7 @@ type AppHandler struct {
 	appliedResources []common.ClusterObjectReference
 	deletedResources []common.ClusterObjectReference
 	parser           *appfile.Parser
+	exitHandlers     *wfTypes.ExitHandlers
 
 	mu sync.Mutex
 }
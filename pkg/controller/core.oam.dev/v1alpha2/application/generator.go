@@ -44,10 +44,15 @@ import (
 	"github.com/oam-dev/kubevela/pkg/utils"
 	"github.com/oam-dev/kubevela/pkg/velaql/providers/query"
 	"github.com/oam-dev/kubevela/pkg/workflow/providers"
+	configProvider "github.com/oam-dev/kubevela/pkg/workflow/providers/config"
+	gitProvider "github.com/oam-dev/kubevela/pkg/workflow/providers/git"
 	"github.com/oam-dev/kubevela/pkg/workflow/providers/http"
+	jobProvider "github.com/oam-dev/kubevela/pkg/workflow/providers/job"
 	"github.com/oam-dev/kubevela/pkg/workflow/providers/kube"
 	multiclusterProvider "github.com/oam-dev/kubevela/pkg/workflow/providers/multicluster"
+	notificationProvider "github.com/oam-dev/kubevela/pkg/workflow/providers/notification"
 	oamProvider "github.com/oam-dev/kubevela/pkg/workflow/providers/oam"
+	secretProvider "github.com/oam-dev/kubevela/pkg/workflow/providers/secret"
 	terraformProvider "github.com/oam-dev/kubevela/pkg/workflow/providers/terraform"
 	"github.com/oam-dev/kubevela/pkg/workflow/tasks"
 	wfTypes "github.com/oam-dev/kubevela/pkg/workflow/types"
@@ -71,6 +76,11 @@ func (h *AppHandler) GenerateApplicationSteps(ctx monitorContext.Context,
 	oamProvider.Install(handlerProviders, app, af, h.r.Client, h.applyComponentFunc(
 		appParser, appRev, af), h.renderComponentFunc(appParser, appRev, af))
 	http.Install(handlerProviders, h.r.Client, app.Namespace)
+	gitProvider.Install(handlerProviders, h.r.Client, app)
+	secretProvider.Install(handlerProviders, app, h.r.Client, h.Dispatch)
+	jobProvider.Install(handlerProviders, app, h.r.Client, h.r.readLogs, h.Dispatch)
+	notificationProvider.Install(handlerProviders, app, h.r.Client)
+	configProvider.Install(handlerProviders, app, h.r.Client)
 	pCtx := process.NewContext(generateContextDataFromApp(app, appRev.Name))
 	taskDiscover := tasks.NewTaskDiscoverFromRevision(ctx, handlerProviders, h.r.pd, appRev, h.r.dm, pCtx)
 	multiclusterProvider.Install(handlerProviders, h.r.Client, app, af,
@@ -85,17 +95,47 @@ func (h *AppHandler) GenerateApplicationSteps(ctx monitorContext.Context,
 	})
 	query.Install(handlerProviders, h.r.Client, nil)
 
-	var tasks []wfTypes.TaskRunner
-	for _, step := range af.WorkflowSteps {
-		task, err := generateStep(ctx, app, step, taskDiscover, h.r.pd, pCtx, "")
-		if err != nil {
-			return nil, err
+	generateSteps := func(steps []v1beta1.WorkflowStep) ([]wfTypes.TaskRunner, error) {
+		var tasks []wfTypes.TaskRunner
+		for _, step := range steps {
+			task, err := generateStep(ctx, app, step, taskDiscover, h.r.pd, pCtx, "")
+			if err != nil {
+				return nil, err
+			}
+			tasks = append(tasks, task)
+		}
+		return tasks, nil
+	}
+	tasks, err := generateSteps(af.WorkflowSteps)
+	if err != nil {
+		return nil, err
+	}
+	if wf := app.Spec.Workflow; wf != nil && (len(wf.OnSuccess) > 0 || len(wf.OnFailure) > 0 || len(wf.Finally) > 0) {
+		h.exitHandlers = &wfTypes.ExitHandlers{}
+		if h.exitHandlers.OnSuccess, err = generateSteps(wf.OnSuccess); err != nil {
+			return nil, errors.WithMessage(err, "generate onSuccess steps")
+		}
+		if h.exitHandlers.OnFailure, err = generateSteps(wf.OnFailure); err != nil {
+			return nil, errors.WithMessage(err, "generate onFailure steps")
+		}
+		if h.exitHandlers.Finally, err = generateSteps(wf.Finally); err != nil {
+			return nil, errors.WithMessage(err, "generate finally steps")
 		}
-		tasks = append(tasks, task)
 	}
 	return tasks, nil
 }
 
+// GenerateWorkflowStep generates the task runner of the workflow step out of the application controller,
+// such as in the workflow simulation of dry-run.
+func GenerateWorkflowStep(ctx context.Context,
+	app *v1beta1.Application,
+	step v1beta1.WorkflowStep,
+	taskDiscover wfTypes.TaskDiscover,
+	pd *packages.PackageDiscover,
+	pCtx process.Context) (wfTypes.TaskRunner, error) {
+	return generateStep(ctx, app, step, taskDiscover, pd, pCtx, "")
+}
+
 func generateStep(ctx context.Context,
 	app *v1beta1.Application,
 	step v1beta1.WorkflowStep,
@@ -131,6 +171,8 @@ func generateStep(ctx context.Context,
 				Outputs:    subStep.Outputs,
 				If:         subStep.If,
 				Timeout:    subStep.Timeout,
+				Retry:      subStep.Retry,
+				Cache:      subStep.Cache,
 				Meta:       subStep.Meta,
 			}
 			subTask, err := generateStep(ctx, app, workflowStep, taskDiscover, pd, pCtx, step.Name)
@@ -144,6 +186,10 @@ func generateStep(ctx context.Context,
 		if app.Spec.Workflow != nil && app.Spec.Workflow.Mode != nil {
 			options.ExecuteMode = app.Spec.Workflow.Mode.SubSteps
 		}
+	case generatorName == wfTypes.WorkflowStepTypeForeach:
+		options.SubTaskGenerator = func(subStep v1beta1.WorkflowStep) (wfTypes.TaskRunner, error) {
+			return generateStep(ctx, app, subStep, taskDiscover, pd, pCtx, step.Name)
+		}
 	}
 
 	genTask, err := taskDiscover.GetTaskGenerator(ctx, generatorName)
@@ -419,7 +465,9 @@ func getStepID(stepName string, stepsStatus []common.StepStatus) string {
 func generateStepID(stepName string, workflowStatus *common.WorkflowStatus, parentStepName string) string {
 	var id string
 	if workflowStatus != nil {
-		workflowStepsStatus := workflowStatus.Steps
+		workflowStepsStatus := make([]common.WorkflowStepStatus, 0, len(workflowStatus.Steps)+len(workflowStatus.ExitHandlers))
+		workflowStepsStatus = append(workflowStepsStatus, workflowStatus.Steps...)
+		workflowStepsStatus = append(workflowStepsStatus, workflowStatus.ExitHandlers...)
 		if parentStepName != "" {
 			for _, status := range workflowStepsStatus {
 				if status.Name == parentStepName {
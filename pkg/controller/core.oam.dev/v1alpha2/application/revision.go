@@ -247,6 +247,7 @@ func (h *AppHandler) gatherRevisionSpec(af *appfile.Appfile) (*v1beta1.Applicati
 			WorkflowStepDefinitions: make(map[string]v1beta1.WorkflowStepDefinition),
 			ScopeGVK:                make(map[string]metav1.GroupVersionKind),
 			Policies:                make(map[string]v1alpha1.Policy),
+			SubWorkflows:            make(map[string]v1alpha1.Workflow),
 		},
 	}
 	for _, w := range af.Workloads {
@@ -310,6 +311,9 @@ func (h *AppHandler) gatherRevisionSpec(af *appfile.Appfile) (*v1beta1.Applicati
 		return nil, "", errors.Wrapf(err, "failed to marshal referred object")
 	}
 	appRev.Spec.Workflow = af.ExternalWorkflow
+	for name, wf := range af.ExternalSubWorkflows {
+		appRev.Spec.SubWorkflows[name] = *wf
+	}
 
 	appRevisionHash, err := ComputeAppRevisionHash(appRev)
 	if err != nil {
@@ -420,6 +424,22 @@ func ComputeAppRevisionHash(appRevision *v1beta1.ApplicationRevision) (string, e
 			return "", err
 		}
 	}
+	if len(appRevision.Spec.SubWorkflows) > 0 {
+		// the sub workflows are appended to the workflow hash to keep the hash of the existing revisions unchanged
+		subWorkflowHash := make(map[string]string)
+		for key, wf := range appRevision.Spec.SubWorkflows {
+			hash, err := utils.ComputeSpecHash([]interface{}{wf.Parameters, wf.Steps})
+			if err != nil {
+				return "", err
+			}
+			subWorkflowHash[key] = hash
+		}
+		hash, err := utils.ComputeSpecHash(subWorkflowHash)
+		if err != nil {
+			return "", err
+		}
+		revHash.WorkflowHash += hash
+	}
 	revHash.ReferredObjectsHash, err = utils.ComputeSpecHash(appRevision.Spec.ReferredObjects)
 	if err != nil {
 		return "", err
@@ -522,7 +542,7 @@ func deepEqualPolicy(old, new v1alpha1.Policy) bool {
 }
 
 func deepEqualWorkflow(old, new v1alpha1.Workflow) bool {
-	return apiequality.Semantic.DeepEqual(old.Steps, new.Steps)
+	return apiequality.Semantic.DeepEqual(old.Parameters, new.Parameters) && apiequality.Semantic.DeepEqual(old.Steps, new.Steps)
 }
 
 func deepEqualAppInRevision(old, new *v1beta1.ApplicationRevision) bool {
@@ -542,6 +562,14 @@ func deepEqualAppInRevision(old, new *v1beta1.ApplicationRevision) bool {
 			return false
 		}
 	}
+	if len(old.Spec.SubWorkflows) != len(new.Spec.SubWorkflows) {
+		return false
+	}
+	for key, wf := range new.Spec.SubWorkflows {
+		if !deepEqualWorkflow(old.Spec.SubWorkflows[key], wf) {
+			return false
+		}
+	}
 	return apiequality.Semantic.DeepEqual(filterSkipAffectAppRevTrait(old.Spec.Application.Spec, old.Spec.TraitDefinitions),
 		filterSkipAffectAppRevTrait(new.Spec.Application.Spec, new.Spec.TraitDefinitions))
 }
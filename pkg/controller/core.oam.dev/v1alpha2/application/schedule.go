@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/workflow"
+)
+
+const (
+	// defaultScheduledRunsHistoryLimit is the default number of the scheduled runs kept in the application status
+	defaultScheduledRunsHistoryLimit = 10
+)
+
+// scheduleResult is the result of checking the workflow schedule of the application
+type scheduleResult struct {
+	// restart indicates the workflow should be restarted by the schedule
+	restart bool
+	// statusChanged indicates the scheduled runs in the status are changed
+	statusChanged bool
+	// next is the duration until the next scheduled time
+	next time.Duration
+}
+
+// parseWorkflowSchedule parses the cron schedule of the workflow with its time zone
+func parseWorkflowSchedule(schedule *v1beta1.WorkflowSchedule) (cron.Schedule, error) {
+	spec := schedule.Cron
+	if schedule.TimeZone != "" {
+		if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+			return nil, errors.Wrapf(err, "invalid time zone %s", schedule.TimeZone)
+		}
+		spec = fmt.Sprintf("CRON_TZ=%s %s", schedule.TimeZone, schedule.Cron)
+	}
+	sched, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid cron schedule %s", schedule.Cron)
+	}
+	return sched, nil
+}
+
+// checkWorkflowSchedule records the outcome of the latest scheduled run and checks whether the workflow
+// should be restarted by the schedule. If the previous run has not finished when the schedule is due,
+// the workflow will be restarted once it's finished, and the missed schedules are merged into one run.
+// The workflow suspended since the failed times of the steps have reached the limit is regarded as finished,
+// while the workflow suspended by the suspend steps, the approvals or the users is regarded as running, since
+// restarting it discards the pending operations. The schedule waits until it's resumed or terminated.
+func checkWorkflowSchedule(app *v1beta1.Application, now time.Time) (*scheduleResult, error) {
+	result := &scheduleResult{}
+	if app.Spec.Workflow == nil || app.Spec.Workflow.Schedule == nil {
+		return result, nil
+	}
+	wfStatus := app.Status.Workflow
+	failed := workflow.IsFailedAfterRetry(app)
+	finished := wfStatus != nil && (wfStatus.Finished || wfStatus.Terminated || failed)
+	if n := len(app.Status.ScheduledRuns); n > 0 && finished {
+		if run := &app.Status.ScheduledRuns[n-1]; run.Phase == common.WorkflowScheduledRunRunning {
+			switch {
+			case failed:
+				run.Phase = common.WorkflowScheduledRunFailed
+			case wfStatus.Terminated:
+				run.Phase = common.WorkflowScheduledRunTerminated
+			default:
+				run.Phase = common.WorkflowScheduledRunSucceeded
+			}
+			run.Message = wfStatus.Message
+			endTime := metav1.NewTime(now)
+			run.EndTime = &endTime
+			result.statusChanged = true
+		}
+	}
+
+	schedule := app.Spec.Workflow.Schedule
+	if schedule.Suspend {
+		return result, nil
+	}
+	sched, err := parseWorkflowSchedule(schedule)
+	if err != nil {
+		return nil, err
+	}
+	last := app.CreationTimestamp.Time
+	if n := len(app.Status.ScheduledRuns); n > 0 {
+		last = app.Status.ScheduledRuns[n-1].ScheduleTime.Time
+	}
+	var scheduleTime time.Time
+	for next := sched.Next(last); !next.IsZero() && !next.After(now); next = sched.Next(next) {
+		scheduleTime = next
+	}
+	if scheduleTime.IsZero() {
+		result.next = sched.Next(last).Sub(now)
+		return result, nil
+	}
+	result.next = sched.Next(now).Sub(now)
+	if !finished {
+		// wait for the running workflow to finish
+		return result, nil
+	}
+
+	app.Status.ScheduledRuns = append(app.Status.ScheduledRuns, common.WorkflowScheduledRun{
+		ScheduleTime: metav1.NewTime(scheduleTime),
+		StartTime:    metav1.NewTime(now),
+		Phase:        common.WorkflowScheduledRunRunning,
+	})
+	limit := schedule.HistoryLimit
+	if limit <= 0 {
+		limit = defaultScheduledRunsHistoryLimit
+	}
+	if n := len(app.Status.ScheduledRuns); n > limit {
+		app.Status.ScheduledRuns = app.Status.ScheduledRuns[n-limit:]
+	}
+	result.restart = true
+	result.statusChanged = true
+	return result, nil
+}
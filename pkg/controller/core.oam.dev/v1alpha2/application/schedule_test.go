@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/workflow"
+)
+
+func TestCheckWorkflowSchedule(t *testing.T) {
+	r := require.New(t)
+	created := time.Date(2022, 7, 1, 0, 30, 0, 0, time.UTC)
+	app := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+		Spec: v1beta1.ApplicationSpec{
+			Workflow: &v1beta1.Workflow{
+				Schedule: &v1beta1.WorkflowSchedule{Cron: "0 * * * *", HistoryLimit: 2},
+			},
+		},
+		Status: common.AppStatus{
+			Workflow: &common.WorkflowStatus{Finished: true},
+		},
+	}
+
+	// not due yet
+	result, err := checkWorkflowSchedule(app, created.Add(10*time.Minute))
+	r.NoError(err)
+	r.False(result.restart)
+	r.Equal(20*time.Minute, result.next)
+
+	// the workflow is still running when the schedule is due
+	app.Status.Workflow.Finished = false
+	result, err = checkWorkflowSchedule(app, created.Add(40*time.Minute))
+	r.NoError(err)
+	r.False(result.restart)
+	r.Equal(0, len(app.Status.ScheduledRuns))
+
+	// the missed schedules are merged into one run
+	app.Status.Workflow.Finished = true
+	now := created.Add(150 * time.Minute)
+	result, err = checkWorkflowSchedule(app, now)
+	r.NoError(err)
+	r.True(result.restart)
+	r.Equal(time.Hour, result.next)
+	r.Equal(1, len(app.Status.ScheduledRuns))
+	r.Equal(time.Date(2022, 7, 1, 3, 0, 0, 0, time.UTC), app.Status.ScheduledRuns[0].ScheduleTime.Time)
+	r.Equal(common.WorkflowScheduledRunRunning, app.Status.ScheduledRuns[0].Phase)
+
+	// the outcome of the run is recorded after the workflow is finished
+	app.Status.Workflow = &common.WorkflowStatus{Finished: true, Terminated: true, Message: "Terminated"}
+	result, err = checkWorkflowSchedule(app, now.Add(time.Minute))
+	r.NoError(err)
+	r.False(result.restart)
+	r.True(result.statusChanged)
+	r.Equal(common.WorkflowScheduledRunTerminated, app.Status.ScheduledRuns[0].Phase)
+	r.Equal("Terminated", app.Status.ScheduledRuns[0].Message)
+	r.NotNil(app.Status.ScheduledRuns[0].EndTime)
+
+	// the history is limited
+	for i := 1; i <= 3; i++ {
+		app.Status.Workflow = &common.WorkflowStatus{Finished: true}
+		result, err = checkWorkflowSchedule(app, now.Add(time.Duration(i)*time.Hour))
+		r.NoError(err)
+		r.True(result.restart)
+	}
+	r.Equal(2, len(app.Status.ScheduledRuns))
+	r.Equal(common.WorkflowScheduledRunSucceeded, app.Status.ScheduledRuns[0].Phase)
+	r.Equal(common.WorkflowScheduledRunRunning, app.Status.ScheduledRuns[1].Phase)
+
+	// the workflow suspended by the steps or the users is regarded as running
+	app.Status.Workflow = &common.WorkflowStatus{Suspend: true, Message: string(common.WorkflowStateSuspended)}
+	result, err = checkWorkflowSchedule(app, now.Add(4*time.Hour))
+	r.NoError(err)
+	r.False(result.restart)
+	r.False(result.statusChanged)
+	r.Equal(common.WorkflowScheduledRunRunning, app.Status.ScheduledRuns[1].Phase)
+
+	// the workflow suspended since the steps failed after retries is regarded as finished
+	app.Status.Workflow = &common.WorkflowStatus{Suspend: true, Message: workflow.MessageSuspendFailedAfterRetries}
+	result, err = checkWorkflowSchedule(app, now.Add(4*time.Hour))
+	r.NoError(err)
+	r.True(result.restart)
+	r.Equal(2, len(app.Status.ScheduledRuns))
+	r.Equal(common.WorkflowScheduledRunFailed, app.Status.ScheduledRuns[0].Phase)
+	r.Equal(workflow.MessageSuspendFailedAfterRetries, app.Status.ScheduledRuns[0].Message)
+	r.Equal(common.WorkflowScheduledRunRunning, app.Status.ScheduledRuns[1].Phase)
+
+	// the suspended schedule won't restart the workflow
+	app.Spec.Workflow.Schedule.Suspend = true
+	app.Status.Workflow = &common.WorkflowStatus{Finished: true}
+	result, err = checkWorkflowSchedule(app, now.Add(5*time.Hour))
+	r.NoError(err)
+	r.False(result.restart)
+
+	// invalid schedule
+	app.Spec.Workflow.Schedule = &v1beta1.WorkflowSchedule{Cron: "0 * * * *", TimeZone: "invalid"}
+	_, err = checkWorkflowSchedule(app, now)
+	r.Error(err)
+}
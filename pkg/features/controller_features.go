@@ -47,6 +47,8 @@ const (
 
 	// AuthenticateApplication enable the authentication for application
 	AuthenticateApplication featuregate.Feature = "AuthenticateApplication"
+	// EnableResourceAdmissionPolicy enable the validation of the dispatched resources by the ResourceAdmissionPolicy
+	EnableResourceAdmissionPolicy featuregate.Feature = "EnableResourceAdmissionPolicy"
 )
 
 var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
@@ -59,6 +61,7 @@ var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	LegacyResourceOwnerValidation: {Default: false, PreRelease: featuregate.Alpha},
 	DisableReferObjectsFromURL:    {Default: false, PreRelease: featuregate.Alpha},
 	AuthenticateApplication:       {Default: false, PreRelease: featuregate.Alpha},
+	EnableResourceAdmissionPolicy: {Default: false, PreRelease: featuregate.Alpha},
 }
 
 func init() {
@@ -121,6 +121,18 @@ var (
 		Help: "application phase number",
 	}, []string{"phase"})
 
+	// DriftedResourceGauge report the number of the drifted resources of application
+	DriftedResourceGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "application_drifted_resource_number",
+		Help: "application drifted resource number",
+	}, []string{"namespace", "application"})
+
+	// ResourceDriftCounter report the number of the detected resource drift
+	ResourceDriftCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "resource_drift_detected_num",
+		Help: "detected resource drift times",
+	}, []string{"kind", "mode"})
+
 	// WorkflowStepPhaseGauge report the number of workflow step state
 	WorkflowStepPhaseGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "workflow_step_phase_number",
@@ -49,6 +49,8 @@ var collectorGroup = []prometheus.Collector{
 	WorkflowInitializedCounter,
 	ApplicationPhaseCounter,
 	WorkflowStepPhaseGauge,
+	DriftedResourceGauge,
+	ResourceDriftCounter,
 	ResourceTrackerNumberGauge,
 	ClusterIsConnectedGauge,
 	ClusterWorkerNumberGauge,
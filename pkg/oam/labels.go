@@ -84,6 +84,9 @@ const (
 	// LabelNamespaceOfEnvName records the env name of namespace
 	LabelNamespaceOfEnvName = "namespace.oam.dev/env"
 
+	// LabelNamespaceOfProjectName records the project of the env bound to the namespace
+	LabelNamespaceOfProjectName = "namespace.oam.dev/project"
+
 	// LabelNamespaceOfTargetName records the target name of namespace
 	LabelNamespaceOfTargetName = "namespace.oam.dev/target"
 
@@ -226,6 +229,9 @@ const (
 
 	// AnnotationResourceURL records the source url of the Kubernetes object
 	AnnotationResourceURL = "app.oam.dev/resource-url"
+
+	// AnnotationJobSpecHash records the hash of the spec of the job run by the workflow step
+	AnnotationJobSpecHash = "app.oam.dev/job-spec-hash"
 )
 
 const (
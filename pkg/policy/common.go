@@ -56,6 +56,15 @@ func ParseApplyOncePolicy(app *v1beta1.Application) (*v1alpha1.ApplyOncePolicySp
 	return nil, nil
 }
 
+// ParseServerSideApplyPolicy parse server-side-apply policy
+func ParseServerSideApplyPolicy(app *v1beta1.Application) (*v1alpha1.ServerSideApplyPolicySpec, error) {
+	spec := &v1alpha1.ServerSideApplyPolicySpec{}
+	if exists, err := parsePolicy(app, v1alpha1.ServerSideApplyPolicyType, spec); exists {
+		return spec, err
+	}
+	return nil, nil
+}
+
 // ParseSharedResourcePolicy parse shared-resource policy
 func ParseSharedResourcePolicy(app *v1beta1.Application) (*v1alpha1.SharedResourcePolicySpec, error) {
 	spec := &v1alpha1.SharedResourcePolicySpec{}
@@ -64,3 +73,30 @@ func ParseSharedResourcePolicy(app *v1beta1.Application) (*v1alpha1.SharedResour
 	}
 	return nil, nil
 }
+
+// ParseDriftDetectionPolicy parse drift-detection policy
+func ParseDriftDetectionPolicy(app *v1beta1.Application) (*v1alpha1.DriftDetectionPolicySpec, error) {
+	spec := &v1alpha1.DriftDetectionPolicySpec{}
+	if exists, err := parsePolicy(app, v1alpha1.DriftDetectionPolicyType, spec); exists {
+		return spec, err
+	}
+	return nil, nil
+}
+
+// ParseReadOnlyPolicy parse read-only policy
+func ParseReadOnlyPolicy(app *v1beta1.Application) (*v1alpha1.ReadOnlyPolicySpec, error) {
+	spec := &v1alpha1.ReadOnlyPolicySpec{}
+	if exists, err := parsePolicy(app, v1alpha1.ReadOnlyPolicyType, spec); exists {
+		return spec, err
+	}
+	return nil, nil
+}
+
+// ParseTakeOverPolicy parse take-over policy
+func ParseTakeOverPolicy(app *v1beta1.Application) (*v1alpha1.TakeOverPolicySpec, error) {
+	spec := &v1alpha1.TakeOverPolicySpec{}
+	if exists, err := parsePolicy(app, v1alpha1.TakeOverPolicyType, spec); exists {
+		return spec, err
+	}
+	return nil, nil
+}
@@ -108,6 +108,102 @@ func TestParseSharedResourcePolicy(t *testing.T) {
 	r.Equal(policySpec, spec)
 }
 
+func TestParseServerSideApplyPolicy(t *testing.T) {
+	r := require.New(t)
+	app := &v1beta1.Application{Spec: v1beta1.ApplicationSpec{
+		Policies: []v1beta1.AppPolicy{{Type: "example"}},
+	}}
+	spec, err := ParseServerSideApplyPolicy(app)
+	r.NoError(err)
+	r.Nil(spec)
+	app.Spec.Policies = append(app.Spec.Policies, v1beta1.AppPolicy{
+		Type:       "server-side-apply",
+		Properties: &runtime.RawExtension{Raw: []byte("bad value")},
+	})
+	_, err = ParseServerSideApplyPolicy(app)
+	r.Error(err)
+	policySpec := &v1alpha1.ServerSideApplyPolicySpec{
+		Enable:         true,
+		FieldManager:   "example",
+		ConflictPolicy: v1alpha1.ServerSideApplyConflictFail,
+		Rules: []v1alpha1.ServerSideApplyPolicyRule{{
+			Selector: v1alpha1.ResourcePolicyRuleSelector{ResourceTypes: []string{"Deployment"}},
+		}}}
+	bs, err := json.Marshal(policySpec)
+	r.NoError(err)
+	app.Spec.Policies[1].Properties.Raw = bs
+	spec, err = ParseServerSideApplyPolicy(app)
+	r.NoError(err)
+	r.Equal(policySpec, spec)
+}
+
+func TestParseDriftDetectionPolicy(t *testing.T) {
+	r := require.New(t)
+	app := &v1beta1.Application{Spec: v1beta1.ApplicationSpec{
+		Policies: []v1beta1.AppPolicy{{Type: "example"}},
+	}}
+	spec, err := ParseDriftDetectionPolicy(app)
+	r.NoError(err)
+	r.Nil(spec)
+	app.Spec.Policies = append(app.Spec.Policies, v1beta1.AppPolicy{
+		Type:       "drift-detection",
+		Properties: &runtime.RawExtension{Raw: []byte("bad value")},
+	})
+	_, err = ParseDriftDetectionPolicy(app)
+	r.Error(err)
+	policySpec := &v1alpha1.DriftDetectionPolicySpec{
+		Mode: v1alpha1.DriftDetectionModeReport,
+		Rules: []v1alpha1.DriftDetectionPolicyRule{{
+			Selector: v1alpha1.ResourcePolicyRuleSelector{ResourceTypes: []string{"Deployment"}},
+			Mode:     v1alpha1.DriftDetectionModeAlert,
+		}}}
+	bs, err := json.Marshal(policySpec)
+	r.NoError(err)
+	app.Spec.Policies[1].Properties.Raw = bs
+	spec, err = ParseDriftDetectionPolicy(app)
+	r.NoError(err)
+	r.Equal(policySpec, spec)
+}
+
+func TestParseReadOnlyAndTakeOverPolicy(t *testing.T) {
+	r := require.New(t)
+	app := &v1beta1.Application{Spec: v1beta1.ApplicationSpec{
+		Policies: []v1beta1.AppPolicy{{Type: "example"}},
+	}}
+	readOnly, err := ParseReadOnlyPolicy(app)
+	r.NoError(err)
+	r.Nil(readOnly)
+	takeOver, err := ParseTakeOverPolicy(app)
+	r.NoError(err)
+	r.Nil(takeOver)
+	app.Spec.Policies = append(app.Spec.Policies, v1beta1.AppPolicy{
+		Type:       "read-only",
+		Properties: &runtime.RawExtension{Raw: []byte("bad value")},
+	}, v1beta1.AppPolicy{
+		Type:       "take-over",
+		Properties: &runtime.RawExtension{Raw: []byte("bad value")},
+	})
+	_, err = ParseReadOnlyPolicy(app)
+	r.Error(err)
+	_, err = ParseTakeOverPolicy(app)
+	r.Error(err)
+	selector := v1alpha1.ResourcePolicyRuleSelector{CompNames: []string{"legacy"}}
+	readOnlySpec := &v1alpha1.ReadOnlyPolicySpec{Rules: []v1alpha1.ReadOnlyPolicyRule{{Selector: selector}}}
+	bs, err := json.Marshal(readOnlySpec)
+	r.NoError(err)
+	app.Spec.Policies[1].Properties.Raw = bs
+	readOnly, err = ParseReadOnlyPolicy(app)
+	r.NoError(err)
+	r.Equal(readOnlySpec, readOnly)
+	takeOverSpec := &v1alpha1.TakeOverPolicySpec{Rules: []v1alpha1.TakeOverPolicyRule{{Selector: selector}}}
+	bs, err = json.Marshal(takeOverSpec)
+	r.NoError(err)
+	app.Spec.Policies[2].Properties.Raw = bs
+	takeOver, err = ParseTakeOverPolicy(app)
+	r.NoError(err)
+	r.Equal(takeOverSpec, takeOver)
+}
+
 func TestParsePolicy(t *testing.T) {
 	r := require.New(t)
 	// Test skipping empty policy
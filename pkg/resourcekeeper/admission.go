@@ -36,10 +36,24 @@ var (
 
 // AdmissionCheck check whether resources dispatch/deletion is admitted
 func (h *resourceKeeper) AdmissionCheck(ctx context.Context, manifests []*unstructured.Unstructured) error {
-	for _, handler := range []ResourceAdmissionHandler{
+	return validateAdmission(ctx, manifests,
 		&NamespaceAdmissionHandler{app: h.app},
 		&ResourceTypeAdmissionHandler{},
-	} {
+	)
+}
+
+// DispatchAdmissionCheck check whether resources dispatch is admitted. Besides the AdmissionCheck, the resources are
+// validated by the ResourceAdmissionPolicy, which is not used for deletion.
+func (h *resourceKeeper) DispatchAdmissionCheck(ctx context.Context, manifests []*unstructured.Unstructured) error {
+	return validateAdmission(ctx, manifests,
+		&NamespaceAdmissionHandler{app: h.app},
+		&ResourceTypeAdmissionHandler{},
+		&PolicyAdmissionHandler{Client: h.Client, app: h.app},
+	)
+}
+
+func validateAdmission(ctx context.Context, manifests []*unstructured.Unstructured, handlers ...ResourceAdmissionHandler) error {
+	for _, handler := range handlers {
 		if err := handler.Validate(ctx, manifests); err != nil {
 			return err
 		}
@@ -0,0 +1,220 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcekeeper
+
+import (
+	"context"
+	"sync"
+
+	"cuelang.org/go/cue"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/features"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/oam"
+)
+
+const (
+	// AdmissionRuleRejectField is the field of the admission rule to decide whether the resource is rejected
+	AdmissionRuleRejectField = "reject"
+	// AdmissionRuleMessageField is the field of the admission rule to explain why the resource is rejected
+	AdmissionRuleMessageField = "message"
+)
+
+// PolicyAdmissionHandler defines the handler to validate the resources by the rules of ResourceAdmissionPolicy
+type PolicyAdmissionHandler struct {
+	client.Client
+	app *v1beta1.Application
+}
+
+// Validate check if the resources are admitted by the ResourceAdmissionPolicies applied to the application
+func (h *PolicyAdmissionHandler) Validate(ctx context.Context, manifests []*unstructured.Unstructured) error {
+	if !utilfeature.DefaultMutableFeatureGate.Enabled(features.EnableResourceAdmissionPolicy) {
+		return nil
+	}
+	policies := &v1alpha1.ResourceAdmissionPolicyList{}
+	if err := h.Client.List(multicluster.ContextInLocalCluster(ctx), policies); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to list ResourceAdmissionPolicy")
+	}
+	admissionPolicies.prune(policies.Items)
+	if len(policies.Items) == 0 {
+		return nil
+	}
+	project, err := h.project(ctx)
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies.Items {
+		if !policy.Spec.Match(h.app.GetNamespace(), project) {
+			continue
+		}
+		for _, rule := range admissionPolicies.get(policy) {
+			for _, manifest := range manifests {
+				if manifest == nil {
+					continue
+				}
+				rejected, message, err := rule.validate(h.templateContext(ctx, project, manifest), manifest)
+				if err != nil {
+					return errors.Wrapf(err, "failed to validate %s %s/%s by ResourceAdmissionPolicy %q", manifest.GetKind(), manifest.GetNamespace(), manifest.GetName(), policy.Name)
+				}
+				if rejected {
+					if message != "" {
+						message = ": " + message
+					}
+					return errors.Errorf("forbidden resource: %s %s/%s is rejected by rule %q of ResourceAdmissionPolicy %q%s", manifest.GetKind(), manifest.GetNamespace(), manifest.GetName(), rule.name, policy.Name, message)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// project returns the project of the application. The project is read from the namespace of the application bound to
+// the env of the project, since the labels of the application are set by its author.
+func (h *PolicyAdmissionHandler) project(ctx context.Context) (string, error) {
+	namespace := &corev1.Namespace{}
+	if err := h.Client.Get(multicluster.ContextInLocalCluster(ctx), client.ObjectKey{Name: h.app.GetNamespace()}, namespace); err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get the project of namespace %s", h.app.GetNamespace())
+	}
+	return namespace.Labels[oam.LabelNamespaceOfProjectName], nil
+}
+
+func (h *PolicyAdmissionHandler) templateContext(ctx context.Context, project string, manifest *unstructured.Unstructured) map[string]interface{} {
+	cluster := oam.GetCluster(manifest)
+	if cluster == "" {
+		cluster = multicluster.ClusterNameInContext(ctx)
+	}
+	if cluster == "" {
+		cluster = types.ClusterLocalName
+	}
+	return map[string]interface{}{
+		"appName":   h.app.GetName(),
+		"namespace": h.app.GetNamespace(),
+		"project":   project,
+		"cluster":   cluster,
+		"output":    manifest.Object,
+	}
+}
+
+// admissionRule is the compiled rule of ResourceAdmissionPolicy
+type admissionRule struct {
+	mu       sync.Mutex
+	name     string
+	selector *v1alpha1.ResourcePolicyRuleSelector
+	inst     *cue.Instance
+	err      error
+}
+
+func compileAdmissionRule(rule v1alpha1.ResourceAdmissionRule) *admissionRule {
+	r := &admissionRule{name: rule.Name, selector: rule.Selector}
+	var runtime cue.Runtime
+	r.inst, r.err = runtime.Compile("-", rule.CUE+"\ncontext: _\n")
+	if r.err == nil && !r.inst.Lookup(AdmissionRuleRejectField).Exists() {
+		r.err = errors.Errorf("the field %s is not defined", AdmissionRuleRejectField)
+	}
+	return r
+}
+
+// validate evaluates the rule with the manifest, it returns whether the manifest is rejected and the message
+func (r *admissionRule) validate(templateContext map[string]interface{}, manifest *unstructured.Unstructured) (bool, string, error) {
+	if r.err != nil {
+		return false, "", errors.Wrapf(r.err, "invalid rule %q", r.name)
+	}
+	if r.selector != nil && !r.selector.Match(manifest) {
+		return false, "", nil
+	}
+	// the cue runtime is not safe for concurrent use
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	inst, err := r.inst.Fill(templateContext, "context")
+	if err != nil {
+		return false, "", errors.Wrapf(err, "failed to evaluate rule %q", r.name)
+	}
+	reject := inst.Lookup(AdmissionRuleRejectField)
+	if !reject.IsConcrete() {
+		return false, "", nil
+	}
+	rejected, err := reject.Bool()
+	if err != nil {
+		return false, "", errors.Wrapf(err, "failed to evaluate rule %q", r.name)
+	}
+	if !rejected {
+		return false, "", nil
+	}
+	message, _ := inst.Lookup(AdmissionRuleMessageField).String()
+	return true, message, nil
+}
+
+type compiledAdmissionPolicy struct {
+	uid        kubetypes.UID
+	generation int64
+	rules      []*admissionRule
+}
+
+// admissionPolicyCache caches the compiled rules of ResourceAdmissionPolicy, the rules are recompiled when the
+// policy is updated
+type admissionPolicyCache struct {
+	mu       sync.Mutex
+	policies map[string]*compiledAdmissionPolicy
+}
+
+var admissionPolicies = &admissionPolicyCache{policies: map[string]*compiledAdmissionPolicy{}}
+
+func (c *admissionPolicyCache) get(policy v1alpha1.ResourceAdmissionPolicy) []*admissionRule {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if compiled, ok := c.policies[policy.Name]; ok && compiled.uid == policy.UID && compiled.generation == policy.Generation {
+		return compiled.rules
+	}
+	compiled := &compiledAdmissionPolicy{uid: policy.UID, generation: policy.Generation}
+	for _, rule := range policy.Spec.Rules {
+		compiled.rules = append(compiled.rules, compileAdmissionRule(rule))
+	}
+	c.policies[policy.Name] = compiled
+	return compiled.rules
+}
+
+// prune removes the compiled rules of the deleted policies
+func (c *admissionPolicyCache) prune(policies []v1alpha1.ResourceAdmissionPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	existing := map[string]struct{}{}
+	for _, policy := range policies {
+		existing[policy.Name] = struct{}{}
+	}
+	for name := range c.policies {
+		if _, found := existing[name]; !found {
+			delete(c.policies, name)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcekeeper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/features"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestPolicyAdmissionHandler_Validate(t *testing.T) {
+	r := require.New(t)
+	policy := &v1alpha1.ResourceAdmissionPolicy{
+		ObjectMeta: v1.ObjectMeta{Name: "baseline", Generation: 1},
+		Spec: v1alpha1.ResourceAdmissionPolicySpec{
+			Namespaces: []string{"team-*"},
+			Rules: []v1alpha1.ResourceAdmissionRule{{
+				Name:     "max-replicas",
+				Selector: &v1alpha1.ResourcePolicyRuleSelector{ResourceTypes: []string{"Deployment"}},
+				CUE: `
+reject: context.output.spec.replicas > 20
+message: "replicas \(context.output.spec.replicas) exceeds 20 in project \(context.project)"
+`,
+			}, {
+				Name: "trusted-registry",
+				CUE: `
+import "strings"
+_images: [ for c in context.output.spec.template.spec.containers if !strings.HasPrefix(c.image, "registry.example.com/") { c.image } ]
+reject: len(_images) > 0
+message: "images \(strings.Join(_images, ", ")) are not from registry.example.com"
+`,
+			}},
+		},
+	}
+	namespace := &corev1.Namespace{ObjectMeta: v1.ObjectMeta{Name: "team-a", Labels: map[string]string{oam.LabelNamespaceOfProjectName: "proj"}}}
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(policy, namespace).Build()
+	app := &v1beta1.Application{ObjectMeta: v1.ObjectMeta{Name: "app", Namespace: "team-a", Labels: map[string]string{oam.LabelProject: "spoofed"}}}
+	handler := &PolicyAdmissionHandler{Client: cli, app: app}
+	deploy := func(replicas int64, image string) []*unstructured.Unstructured {
+		return []*unstructured.Unstructured{{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "web", "namespace": "team-a"},
+			"spec": map[string]interface{}{
+				"replicas": replicas,
+				"template": map[string]interface{}{"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"name": "web", "image": image}},
+				}},
+			},
+		}}}
+	}
+	ctx := context.Background()
+
+	// the policy is not used if the feature is disabled
+	r.NoError(handler.Validate(ctx, deploy(30, "nginx")))
+
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnableResourceAdmissionPolicy, true)()
+	r.NoError(handler.Validate(ctx, deploy(3, "registry.example.com/nginx")))
+	err := handler.Validate(ctx, deploy(30, "registry.example.com/nginx"))
+	r.Error(err)
+	r.Equal(`forbidden resource: Deployment team-a/web is rejected by rule "max-replicas" of ResourceAdmissionPolicy "baseline": replicas 30 exceeds 20 in project proj`, err.Error())
+	err = handler.Validate(ctx, deploy(3, "nginx"))
+	r.Error(err)
+	r.Contains(err.Error(), `rejected by rule "trusted-registry" of ResourceAdmissionPolicy "baseline": images nginx are not from registry.example.com`)
+
+	// the rules are skipped if the referred fields are not set
+	cm := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "team-a"},
+	}}
+	r.NoError(handler.Validate(ctx, []*unstructured.Unstructured{cm}))
+
+	// the policy is not applied to the applications in other namespaces
+	handler.app = &v1beta1.Application{ObjectMeta: v1.ObjectMeta{Name: "app", Namespace: "default"}}
+	r.NoError(handler.Validate(ctx, deploy(30, "nginx")))
+	handler.app = app
+
+	// the project is read from the namespace instead of the labels of the application
+	policy.Spec.Projects = []string{"spoofed"}
+	policy.Generation = 2
+	r.NoError(cli.Update(ctx, policy))
+	r.NoError(handler.Validate(ctx, deploy(30, "nginx")))
+	policy.Spec.Projects = []string{"proj"}
+	policy.Generation = 3
+	r.NoError(cli.Update(ctx, policy))
+	r.Error(handler.Validate(ctx, deploy(30, "nginx")))
+
+	// the updated policy is recompiled
+	policy.Spec.Rules = []v1alpha1.ResourceAdmissionRule{{Name: "invalid", CUE: `reject: context.output.spec.replicas >`}}
+	policy.Generation = 4
+	r.NoError(cli.Update(ctx, policy))
+	err = handler.Validate(ctx, deploy(3, "registry.example.com/nginx"))
+	r.Error(err)
+	r.Contains(err.Error(), `invalid rule "invalid"`)
+	r.Contains(err.Error(), `failed to validate Deployment team-a/web by ResourceAdmissionPolicy "baseline"`)
+
+	// the deleted policy is pruned
+	r.NoError(cli.Delete(ctx, policy))
+	r.NoError(handler.Validate(ctx, deploy(30, "nginx")))
+	r.Empty(admissionPolicies.policies)
+}
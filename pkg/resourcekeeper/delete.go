@@ -71,6 +71,7 @@ func (h *resourceKeeper) Delete(ctx context.Context, manifests []*unstructured.U
 }
 
 func (h *resourceKeeper) delete(ctx context.Context, manifest *unstructured.Unstructured, cfg *deleteConfig) (err error) {
+	readOnly := h.isReadOnly(manifest)
 	// 1. mark manifests as deleted in resourcetracker
 	if !cfg.skipRT {
 		var rt *v1beta1.ResourceTracker
@@ -82,11 +83,17 @@ func (h *resourceKeeper) delete(ctx context.Context, manifest *unstructured.Unst
 		if err != nil {
 			return errors.Wrapf(err, "failed to get resourcetracker")
 		}
+		if mr, ok := rt.GetManagedResource(manifest); ok && mr.ReadOnly {
+			readOnly = true
+		}
 		if err = resourcetracker.DeletedManifestInResourceTracker(multicluster.ContextInLocalCluster(ctx), h.Client, rt, manifest, false); err != nil {
 			return errors.Wrapf(err, "failed to delete resources in resourcetracker")
 		}
 	}
 	// 2. delete manifests
+	if readOnly {
+		return nil
+	}
 	deleteCtx := multicluster.ContextWithClusterName(ctx, oam.GetCluster(manifest))
 	deleteCtx = auth.ContextWithUserInfo(deleteCtx, h.app)
 	if err = h.Client.Delete(deleteCtx, manifest); err != nil && !kerrors.IsNotFound(err) {
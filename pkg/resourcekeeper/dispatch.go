@@ -56,12 +56,13 @@ func newDispatchConfig(options ...DispatchOption) *dispatchConfig {
 
 // Dispatch dispatch resources
 func (h *resourceKeeper) Dispatch(ctx context.Context, manifests []*unstructured.Unstructured, applyOpts []apply.ApplyOption, options ...DispatchOption) (err error) {
+	options = append(dispatchOptionsFromContext(ctx), options...)
 	if h.applyOncePolicy != nil && h.applyOncePolicy.Enable && h.applyOncePolicy.Rules == nil {
 		options = append(options, MetaOnlyOption{})
 	}
 	h.ClearNamespaceForClusterScopedResources(manifests)
 	// 0. check admission
-	if err = h.AdmissionCheck(ctx, manifests); err != nil {
+	if err = h.DispatchAdmissionCheck(ctx, manifests); err != nil {
 		return err
 	}
 	// 1. record manifests in resourcetracker
@@ -82,8 +83,10 @@ func (h *resourceKeeper) Dispatch(ctx context.Context, manifests []*unstructured
 func (h *resourceKeeper) record(ctx context.Context, manifests []*unstructured.Unstructured, options ...DispatchOption) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	var rootManifests []*unstructured.Unstructured
-	var versionManifests []*unstructured.Unstructured
+	// the manifests are grouped by whether they are selected by the read-only policy, so the read-only ones are
+	// marked in the resourcetracker and never written or deleted later
+	rootManifests := map[bool][]*unstructured.Unstructured{}
+	versionManifests := map[bool][]*unstructured.Unstructured{}
 
 	for _, manifest := range manifests {
 		if manifest != nil {
@@ -95,10 +98,11 @@ func (h *resourceKeeper) record(ctx context.Context, manifests []*unstructured.U
 			}
 			cfg := newDispatchConfig(_options...)
 			if !cfg.skipRT {
+				readOnly := h.isReadOnly(manifest)
 				if cfg.useRoot {
-					rootManifests = append(rootManifests, manifest)
+					rootManifests[readOnly] = append(rootManifests[readOnly], manifest)
 				} else {
-					versionManifests = append(versionManifests, manifest)
+					versionManifests[readOnly] = append(versionManifests[readOnly], manifest)
 				}
 			}
 		}
@@ -111,8 +115,10 @@ func (h *resourceKeeper) record(ctx context.Context, manifests []*unstructured.U
 		if err != nil {
 			return errors.Wrapf(err, "failed to get resourcetracker")
 		}
-		if err = resourcetracker.RecordManifestsInResourceTracker(multicluster.ContextInLocalCluster(ctx), h.Client, rt, rootManifests, cfg.metaOnly, cfg.creator); err != nil {
-			return errors.Wrapf(err, "failed to record resources in resourcetracker %s", rt.Name)
+		for _, readOnly := range []bool{false, true} {
+			if err = resourcetracker.RecordManifestsInResourceTracker(multicluster.ContextInLocalCluster(ctx), h.Client, rt, rootManifests[readOnly], cfg.metaOnly, readOnly, cfg.creator); err != nil {
+				return errors.Wrapf(err, "failed to record resources in resourcetracker %s", rt.Name)
+			}
 		}
 	}
 
@@ -120,8 +126,10 @@ func (h *resourceKeeper) record(ctx context.Context, manifests []*unstructured.U
 	if err != nil {
 		return errors.Wrapf(err, "failed to get resourcetracker")
 	}
-	if err = resourcetracker.RecordManifestsInResourceTracker(multicluster.ContextInLocalCluster(ctx), h.Client, rt, versionManifests, cfg.metaOnly, cfg.creator); err != nil {
-		return errors.Wrapf(err, "failed to record resources in resourcetracker %s", rt.Name)
+	for _, readOnly := range []bool{false, true} {
+		if err = resourcetracker.RecordManifestsInResourceTracker(multicluster.ContextInLocalCluster(ctx), h.Client, rt, versionManifests[readOnly], cfg.metaOnly, readOnly, cfg.creator); err != nil {
+			return errors.Wrapf(err, "failed to record resources in resourcetracker %s", rt.Name)
+		}
 	}
 	return nil
 }
@@ -130,10 +138,8 @@ func (h *resourceKeeper) dispatch(ctx context.Context, manifests []*unstructured
 	errs := parallel.Run(func(manifest *unstructured.Unstructured) error {
 		applyCtx := multicluster.ContextWithClusterName(ctx, oam.GetCluster(manifest))
 		applyCtx = auth.ContextWithUserInfo(applyCtx, h.app)
-		ao := applyOpts
-		if h.isShared(manifest) {
-			ao = append([]apply.ApplyOption{apply.SharedByApp(h.app)}, ao...)
-		}
+		ao := h.withResourcePolicies(manifest, applyOpts)
+		ao = h.withServerSideApply(manifest, ao)
 		return h.applicator.Apply(applyCtx, manifest, ao...)
 	}, manifests, MaxDispatchConcurrent)
 	return velaerrors.AggregateErrors(errs.([]error))
@@ -22,8 +22,10 @@ import (
 
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
@@ -103,3 +105,131 @@ func TestResourceKeeperAdmissionDispatchAndDelete(t *testing.T) {
 	r.NotNil(err)
 	r.Contains(err.Error(), "forbidden")
 }
+
+func TestResourceKeeperReadOnlyAndTakeOver(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	appLabels := map[string]string{oam.LabelAppName: "app", oam.LabelAppNamespace: "default"}
+	legacy := &v1.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{Name: "legacy", Namespace: "default", Labels: appLabels},
+		Data:       map[string]string{"key": "legacy"},
+	}
+	adopted := &v1.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{Name: "adopted", Namespace: "default", Labels: map[string]string{"team": "a"}},
+		Data:       map[string]string{"key": "legacy"},
+	}
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(legacy, adopted).Build()
+	_rk, err := NewResourceKeeper(ctx, cli, &v1beta1.Application{
+		ObjectMeta: v12.ObjectMeta{Name: "app", Namespace: "default", Generation: 1},
+	})
+	r.NoError(err)
+	rk := _rk.(*resourceKeeper)
+	rk.readOnlyPolicy = &v1alpha1.ReadOnlyPolicySpec{Rules: []v1alpha1.ReadOnlyPolicyRule{{
+		Selector: v1alpha1.ResourcePolicyRuleSelector{ResourceNames: []string{"legacy", "missing"}},
+	}}}
+	rk.takeOverPolicy = &v1alpha1.TakeOverPolicySpec{Rules: []v1alpha1.TakeOverPolicyRule{{
+		Selector: v1alpha1.ResourcePolicyRuleSelector{ResourceNames: []string{"adopted"}},
+	}}}
+	newConfigMap := func(name string) *unstructured.Unstructured {
+		cm := &unstructured.Unstructured{}
+		cm.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("ConfigMap"))
+		cm.SetName(name)
+		cm.SetNamespace("default")
+		cm.SetLabels(appLabels)
+		r.NoError(unstructured.SetNestedStringMap(cm.Object, map[string]string{"key": "desired"}, "data"))
+		return cm
+	}
+	get := func(name string) (*v1.ConfigMap, error) {
+		cm := &v1.ConfigMap{}
+		return cm, cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, cm)
+	}
+
+	// the read-only resources are tracked but never written
+	r.NoError(rk.Dispatch(ctx, []*unstructured.Unstructured{newConfigMap("legacy"), newConfigMap("adopted")}, nil))
+	r.Len(rk._currentRT.Spec.ManagedResources, 2)
+	cm, err := get("legacy")
+	r.NoError(err)
+	r.Equal("legacy", cm.Data["key"])
+	r.Error(rk.Dispatch(ctx, []*unstructured.Unstructured{newConfigMap("missing")}, nil))
+	_, err = get("missing")
+	r.True(kerrors.IsNotFound(err))
+
+	// the resources not managed by any application are taken over
+	cm, err = get("adopted")
+	r.NoError(err)
+	r.Equal("desired", cm.Data["key"])
+	r.Equal("app", cm.Labels[oam.LabelAppName])
+
+	// the read-only resources are never deleted
+	r.NoError(rk.loadResourceTrackers(ctx))
+	r.NoError(rk.StateKeep(ctx))
+	h := &gcHandler{resourceKeeper: rk, cfg: newGCConfig()}
+	h.cache.registerResourceTrackers(rk._currentRT)
+	for _, mr := range rk._currentRT.Spec.ManagedResources {
+		r.NoError(h.deleteManagedResource(ctx, mr, rk._currentRT))
+	}
+	_, err = get("legacy")
+	r.NoError(err)
+	_, err = get("adopted")
+	r.True(kerrors.IsNotFound(err))
+	r.NoError(rk.Delete(ctx, []*unstructured.Unstructured{newConfigMap("legacy")}))
+	_, err = get("legacy")
+	r.NoError(err)
+}
+
+func TestResourceKeeperReadOnlyMetaOnly(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	legacy := &v1.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{Name: "legacy", Namespace: "default"},
+		Data:       map[string]string{"key": "legacy"},
+	}
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(legacy).Build()
+	_rk, err := NewResourceKeeper(ctx, cli, &v1beta1.Application{
+		ObjectMeta: v12.ObjectMeta{Name: "app", Namespace: "default", Generation: 1},
+	})
+	r.NoError(err)
+	rk := _rk.(*resourceKeeper)
+	rk.readOnlyPolicy = &v1alpha1.ReadOnlyPolicySpec{Rules: []v1alpha1.ReadOnlyPolicyRule{{
+		Selector: v1alpha1.ResourcePolicyRuleSelector{CompTypes: []string{"ref-objects"}},
+	}}}
+	cm := &unstructured.Unstructured{}
+	cm.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("ConfigMap"))
+	cm.SetName("legacy")
+	cm.SetNamespace("default")
+	cm.SetLabels(map[string]string{oam.LabelAppName: "app", oam.LabelAppNamespace: "default", oam.WorkloadTypeLabel: "ref-objects"})
+	exists := func() bool {
+		err := cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "legacy"}, &v1.ConfigMap{})
+		if err != nil {
+			r.True(kerrors.IsNotFound(err))
+		}
+		return err == nil
+	}
+
+	// the component type is not recorded without the data, the read-only mark is recorded instead
+	r.NoError(rk.Dispatch(ctx, []*unstructured.Unstructured{cm}, nil, MetaOnlyOption{}))
+	r.Len(rk._currentRT.Spec.ManagedResources, 1)
+	mr := rk._currentRT.Spec.ManagedResources[0]
+	r.Nil(mr.Data)
+	r.True(mr.ReadOnly)
+	r.True(rk.isReadOnlyManagedResource(mr))
+
+	// the mark is kept when the resource is marked as deleted, so the resource is never deleted
+	r.NoError(rk.Delete(ctx, []*unstructured.Unstructured{cm.DeepCopy()}))
+	r.True(rk._currentRT.Spec.ManagedResources[0].Deleted)
+	r.True(rk._currentRT.Spec.ManagedResources[0].ReadOnly)
+	r.NoError(rk.loadResourceTrackers(ctx))
+	r.NoError(rk.StateKeep(ctx))
+	r.True(exists())
+	h := &gcHandler{resourceKeeper: rk, cfg: newGCConfig()}
+	h.cache.registerResourceTrackers(rk._currentRT)
+	r.NoError(h.deleteManagedResource(ctx, rk._currentRT.Spec.ManagedResources[0], rk._currentRT))
+	r.True(exists())
+
+	// the read-only policy is checked for the resources recorded without the mark
+	mr.ReadOnly = false
+	r.False(rk.isReadOnlyManagedResource(mr))
+	mr.Component = "legacy"
+	rk.readOnlyPolicy.Rules[0].Selector = v1alpha1.ResourcePolicyRuleSelector{CompNames: []string{"legacy"}}
+	r.True(rk.isReadOnlyManagedResource(mr))
+}
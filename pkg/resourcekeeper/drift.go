@@ -0,0 +1,295 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcekeeper
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+)
+
+// DriftedField is a field of the live resource that differs from the desired state
+type DriftedField struct {
+	Path    string      `json:"path"`
+	Desired interface{} `json:"desired,omitempty"`
+	Live    interface{} `json:"live,omitempty"`
+}
+
+// DriftedResource records the drift of a managed resource detected by the state keep
+type DriftedResource struct {
+	Resource v1beta1.ManagedResource     `json:"resource"`
+	Mode     v1alpha1.DriftDetectionMode `json:"mode"`
+	// Missing means the live resource is deleted
+	Missing bool           `json:"missing,omitempty"`
+	Fields  []DriftedField `json:"fields,omitempty"`
+	// Corrected means the desired state is re-applied
+	Corrected bool `json:"corrected,omitempty"`
+}
+
+// String returns the summary of the drift
+func (in DriftedResource) String() string {
+	if in.Missing {
+		return fmt.Sprintf("%s is deleted", in.Resource.DisplayName())
+	}
+	paths := make([]string, 0, len(in.Fields))
+	for _, field := range in.Fields {
+		paths = append(paths, field.Path)
+	}
+	return fmt.Sprintf("%s drifted at %s", in.Resource.DisplayName(), strings.Join(paths, ", "))
+}
+
+// ignoredDriftMetadata is the metadata of the desired state that is not compared with the live resource
+var ignoredDriftMetadata = map[string]bool{
+	"creationTimestamp": true,
+	"generation":        true,
+	"managedFields":     true,
+	"resourceVersion":   true,
+	"selfLink":          true,
+	"uid":               true,
+	"ownerReferences":   true,
+}
+
+// ComputeDrift computes the fields of the live resource that differ from the desired state. Only the fields set in
+// the desired state are compared, so the fields defaulted or added by the others are not treated as drift. If the
+// fieldManager is set, the resource is applied by the server-side apply, and only the fields owned by the field
+// manager in the live resource are compared, the fields taken over by the other managers are not treated as drift.
+func ComputeDrift(desired, live *unstructured.Unstructured, fieldManager string) []DriftedField {
+	owned := ownedFields(live, fieldManager)
+	var fields []DriftedField
+	for _, key := range sortedKeys(desired.Object) {
+		switch key {
+		case "status":
+			continue
+		case "metadata":
+			desiredMeta, ok := desired.Object[key].(map[string]interface{})
+			liveMeta, _ := live.Object[key].(map[string]interface{})
+			metaOwned, compare := ownedChild(owned, fieldpath.PathElement{FieldName: &key})
+			if !ok || !compare {
+				continue
+			}
+			for _, k := range sortedKeys(desiredMeta) {
+				if ignoredDriftMetadata[k] {
+					continue
+				}
+				k := k
+				if child, compare := ownedChild(metaOwned, fieldpath.PathElement{FieldName: &k}); compare {
+					fields = append(fields, computeDrift(desiredMeta[k], liveMeta[k], "metadata."+k, child)...)
+				}
+			}
+		default:
+			key := key
+			if child, compare := ownedChild(owned, fieldpath.PathElement{FieldName: &key}); compare {
+				fields = append(fields, computeDrift(desired.Object[key], live.Object[key], key, child)...)
+			}
+		}
+	}
+	return fields
+}
+
+// ownedFields returns the fields applied by the field manager in the live resource, nil means all the fields are
+// compared, which is the case if the field manager is not set or has never applied the resource
+func ownedFields(live *unstructured.Unstructured, fieldManager string) *fieldpath.Set {
+	if fieldManager == "" {
+		return nil
+	}
+	var owned *fieldpath.Set
+	for _, entry := range live.GetManagedFields() {
+		if entry.Manager != fieldManager || entry.Operation != metav1.ManagedFieldsOperationApply || entry.FieldsV1 == nil {
+			continue
+		}
+		set := &fieldpath.Set{}
+		if err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil
+		}
+		if owned == nil {
+			owned = set
+		} else {
+			owned = owned.Union(set)
+		}
+	}
+	return owned
+}
+
+// ownedChild returns whether the element is owned, and the owned fields under it. A nil set means all the fields
+// under the element are compared.
+func ownedChild(owned *fieldpath.Set, pe fieldpath.PathElement) (*fieldpath.Set, bool) {
+	if owned == nil {
+		return nil, true
+	}
+	if child, ok := owned.Children.Get(pe); ok {
+		return child, true
+	}
+	return nil, owned.Members.Has(pe)
+}
+
+func computeDrift(desired, live interface{}, path string, owned *fieldpath.Set) []DriftedField {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return []DriftedField{{Path: path, Desired: desired, Live: live}}
+		}
+		var fields []DriftedField
+		for _, key := range sortedKeys(d) {
+			key := key
+			if child, compare := ownedChild(owned, fieldpath.PathElement{FieldName: &key}); compare {
+				fields = append(fields, computeDrift(d[key], l[key], path+"."+key, child)...)
+			}
+		}
+		return fields
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if owned != nil && ok {
+			return computeOwnedListDrift(d, l, path, owned)
+		}
+		if !ok || len(l) != len(d) {
+			return []DriftedField{{Path: path, Desired: desired, Live: live}}
+		}
+		var fields []DriftedField
+		for i := range d {
+			fields = append(fields, computeDrift(d[i], l[i], fmt.Sprintf("%s[%d]", path, i), nil)...)
+		}
+		return fields
+	case nil:
+		return nil
+	default:
+		if !equalValue(desired, live) {
+			return []DriftedField{{Path: path, Desired: desired, Live: live}}
+		}
+		return nil
+	}
+}
+
+// computeOwnedListDrift compares the owned elements of the desired list with the live ones, the elements of the
+// associative lists are matched by their keys or values as the managed fields do, the others by their indexes
+func computeOwnedListDrift(desired, live []interface{}, path string, owned *fieldpath.Set) []DriftedField {
+	var elements []fieldpath.PathElement
+	owned.Members.Iterate(func(pe fieldpath.PathElement) { elements = append(elements, pe) })
+	owned.Children.Iterate(func(pe fieldpath.PathElement) { elements = append(elements, pe) })
+	var fields []DriftedField
+	for i, d := range desired {
+		elemPath := fmt.Sprintf("%s[%d]", path, i)
+		for _, pe := range elements {
+			if !matchListElement(pe, d, i) {
+				continue
+			}
+			child, _ := ownedChild(owned, pe)
+			var l interface{}
+			for j := range live {
+				if matchListElement(pe, live[j], j) {
+					l = live[j]
+					break
+				}
+			}
+			if l == nil {
+				fields = append(fields, DriftedField{Path: elemPath, Desired: d})
+			} else {
+				fields = append(fields, computeDrift(d, l, elemPath, child)...)
+			}
+			break
+		}
+	}
+	return fields
+}
+
+func matchListElement(pe fieldpath.PathElement, elem interface{}, index int) bool {
+	switch {
+	case pe.Key != nil:
+		m, ok := elem.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for _, field := range *pe.Key {
+			v, ok := m[field.Name]
+			if !ok || !value.Equals(value.NewValueInterface(v), field.Value) {
+				return false
+			}
+		}
+		return true
+	case pe.Value != nil:
+		return value.Equals(value.NewValueInterface(elem), *pe.Value)
+	case pe.Index != nil:
+		return *pe.Index == index
+	default:
+		return false
+	}
+}
+
+// equalValue compares the scalar values, the numbers of different types and the equal quantities in different
+// formats, such as 1 and 1000m, are treated as equal
+func equalValue(desired, live interface{}) bool {
+	if reflect.DeepEqual(normalizeNumber(desired), normalizeNumber(live)) {
+		return true
+	}
+	d, ok := parseQuantity(desired)
+	if !ok {
+		return false
+	}
+	l, ok := parseQuantity(live)
+	return ok && d.Cmp(l) == 0
+}
+
+func parseQuantity(val interface{}) (resource.Quantity, bool) {
+	switch v := normalizeNumber(val).(type) {
+	case string:
+		q, err := resource.ParseQuantity(v)
+		return q, err == nil
+	case float64:
+		q, err := resource.ParseQuantity(strconv.FormatFloat(v, 'f', -1, 64))
+		return q, err == nil
+	default:
+		return resource.Quantity{}, false
+	}
+}
+
+// normalizeNumber converts the numbers to float64, the numbers decoded from the resourcetracker and the live
+// resource may have different types
+func normalizeNumber(val interface{}) interface{} {
+	switch v := val.(type) {
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	default:
+		return val
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
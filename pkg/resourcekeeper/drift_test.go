@@ -0,0 +1,203 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcekeeper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestComputeDrift(t *testing.T) {
+	r := require.New(t)
+	desired := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "labels": map[string]interface{}{"app": "web"}},
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"template": map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{"name": "web", "image": "nginx:1.20"},
+			}}},
+		},
+	}}
+	live := desired.DeepCopy()
+	live.SetResourceVersion("10")
+	live.SetLabels(map[string]string{"app": "web", "team": "a"})
+	r.NoError(unstructured.SetNestedField(live.Object, float64(2), "spec", "replicas"))
+	r.NoError(unstructured.SetNestedField(live.Object, "Available", "status", "phase"))
+	r.Empty(ComputeDrift(desired, live, ""))
+
+	r.NoError(unstructured.SetNestedField(live.Object, int64(3), "spec", "replicas"))
+	r.NoError(unstructured.SetNestedSlice(live.Object, []interface{}{
+		map[string]interface{}{"name": "web", "image": "nginx:latest"},
+	}, "spec", "template", "spec", "containers"))
+	r.Equal([]DriftedField{
+		{Path: "spec.replicas", Desired: int64(2), Live: int64(3)},
+		{Path: "spec.template.spec.containers[0].image", Desired: "nginx:1.20", Live: "nginx:latest"},
+	}, ComputeDrift(desired, live, ""))
+
+	unstructured.RemoveNestedField(live.Object, "spec", "template")
+	live.SetLabels(nil)
+	fields := ComputeDrift(desired, live, "")
+	r.Equal([]string{"metadata.labels", "spec.replicas", "spec.template"}, []string{fields[0].Path, fields[1].Path, fields[2].Path})
+}
+
+func TestComputeDriftQuantity(t *testing.T) {
+	r := require.New(t)
+	resources := func(cpu, memory interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"resources": map[string]interface{}{"cpu": cpu, "memory": memory}},
+		}}
+	}
+	r.Empty(ComputeDrift(resources("1000m", "1Gi"), resources("1", "1024Mi"), ""))
+	r.Empty(ComputeDrift(resources(int64(1), "1Gi"), resources("1", "1Gi"), ""))
+	r.Equal([]DriftedField{{Path: "spec.resources.cpu", Desired: "500m", Live: "1"}}, ComputeDrift(resources("500m", "1Gi"), resources("1", "1Gi"), ""))
+}
+
+func TestComputeDriftServerSideApply(t *testing.T) {
+	r := require.New(t)
+	desired := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "labels": map[string]interface{}{"app": "web"}},
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"template": map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{"name": "web", "image": "nginx:1.20"},
+			}}},
+		},
+	}}
+	live := desired.DeepCopy()
+	// the replicas are taken over by the autoscaler, and the sidecar is injected by the others
+	r.NoError(unstructured.SetNestedField(live.Object, int64(5), "spec", "replicas"))
+	r.NoError(unstructured.SetNestedSlice(live.Object, []interface{}{
+		map[string]interface{}{"name": "sidecar", "image": "envoy"},
+		map[string]interface{}{"name": "web", "image": "nginx:latest"},
+	}, "spec", "template", "spec", "containers"))
+	live.SetManagedFields([]v12.ManagedFieldsEntry{{
+		Manager:   "kubevela",
+		Operation: v12.ManagedFieldsOperationApply,
+		FieldsV1:  &v12.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:app":{}}},"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"web\"}":{".":{},"f:image":{},"f:name":{}}}}}}}`)},
+	}, {
+		Manager:   "autoscaler",
+		Operation: v12.ManagedFieldsOperationUpdate,
+		FieldsV1:  &v12.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)},
+	}})
+	r.Equal([]DriftedField{
+		{Path: "spec.template.spec.containers[0].image", Desired: "nginx:1.20", Live: "nginx:latest"},
+	}, ComputeDrift(desired, live, "kubevela"))
+
+	// all the fields are compared without the field manager
+	r.Len(ComputeDrift(desired, live, ""), 2)
+
+	// the container owned by the manager is removed
+	r.NoError(unstructured.SetNestedSlice(live.Object, []interface{}{
+		map[string]interface{}{"name": "sidecar", "image": "envoy"},
+	}, "spec", "template", "spec", "containers"))
+	r.Equal([]DriftedField{
+		{Path: "spec.template.spec.containers[0]", Desired: map[string]interface{}{"name": "web", "image": "nginx:1.20"}},
+	}, ComputeDrift(desired, live, "kubevela"))
+}
+
+func TestStateKeepDriftDetection(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).Build()
+	_rk, err := NewResourceKeeper(ctx, cli, &v1beta1.Application{
+		ObjectMeta: v12.ObjectMeta{Name: "app", Namespace: "default", Generation: 1},
+	})
+	r.NoError(err)
+	rk := _rk.(*resourceKeeper)
+	cm := &unstructured.Unstructured{}
+	cm.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("ConfigMap"))
+	cm.SetName("cm")
+	cm.SetNamespace("default")
+	cm.SetLabels(map[string]string{oam.LabelAppName: "app", oam.LabelAppNamespace: "default"})
+	r.NoError(unstructured.SetNestedStringMap(cm.Object, map[string]string{"key": "value", "once": "a"}, "data"))
+	r.NoError(rk.Dispatch(ctx, []*unstructured.Unstructured{cm}, nil))
+	r.NoError(rk.loadResourceTrackers(ctx))
+
+	edit := func(data map[string]string) {
+		live := &v1.ConfigMap{}
+		r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cm"}, live))
+		live.Data = data
+		r.NoError(cli.Update(ctx, live))
+		rk.cache = newResourceCache(cli, rk.app)
+	}
+	liveData := func() map[string]string {
+		live := &v1.ConfigMap{}
+		r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cm"}, live))
+		return live.Data
+	}
+
+	// no drift is detected without the policy
+	edit(map[string]string{"key": "edited", "once": "a"})
+	r.NoError(rk.StateKeep(ctx))
+	r.Empty(rk.Drifts())
+	r.Equal("value", liveData()["key"])
+
+	// the drifted resources are not re-applied in the report mode
+	rk.driftPolicy = &v1alpha1.DriftDetectionPolicySpec{Mode: v1alpha1.DriftDetectionModeReport}
+	edit(map[string]string{"key": "edited", "once": "a"})
+	r.NoError(rk.StateKeep(ctx))
+	r.Len(rk.Drifts(), 1)
+	drift := rk.Drifts()[0]
+	r.Equal(v1alpha1.DriftDetectionModeReport, drift.Mode)
+	r.False(drift.Corrected)
+	r.Nil(drift.Resource.Data)
+	r.Equal([]DriftedField{{Path: "data.key", Desired: "value", Live: "edited"}}, drift.Fields)
+	r.Equal("edited", liveData()["key"])
+
+	// the fields of the apply-once paths are not drift
+	rk.applyOncePolicy = &v1alpha1.ApplyOncePolicySpec{Enable: true, Rules: []v1alpha1.ApplyOncePolicyRule{{
+		Selector: v1alpha1.ResourcePolicyRuleSelector{ResourceTypes: []string{"ConfigMap"}},
+		Strategy: &v1alpha1.ApplyOnceStrategy{Path: []string{"data.once"}},
+	}}}
+	rk.driftPolicy.Mode = v1alpha1.DriftDetectionModeCorrect
+	edit(map[string]string{"key": "edited", "once": "b"})
+	r.NoError(rk.StateKeep(ctx))
+	r.Len(rk.Drifts(), 1)
+	r.True(rk.Drifts()[0].Corrected)
+	r.Equal([]DriftedField{{Path: "data.key", Desired: "value", Live: "edited"}}, rk.Drifts()[0].Fields)
+	r.Equal(map[string]string{"key": "value", "once": "b"}, liveData())
+
+	rk.cache = newResourceCache(cli, rk.app)
+	r.NoError(rk.StateKeep(ctx))
+	r.Empty(rk.Drifts())
+
+	// the deleted resources are drift
+	rk.applyOncePolicy = nil
+	rk.driftPolicy.Mode = v1alpha1.DriftDetectionModeAlert
+	r.NoError(cli.Delete(ctx, &v1.ConfigMap{ObjectMeta: v12.ObjectMeta{Namespace: "default", Name: "cm"}}))
+	rk.cache = newResourceCache(cli, rk.app)
+	r.NoError(rk.StateKeep(ctx))
+	r.Len(rk.Drifts(), 1)
+	r.True(rk.Drifts()[0].Missing)
+	r.Equal("ConfigMap cm (Namespace: default) is deleted", rk.Drifts()[0].String())
+}
@@ -67,6 +67,13 @@ type gcConfig struct {
 	disableLegacyGC            bool
 
 	order v1alpha1.GarbageCollectOrder
+
+	// manual indicates the gc is triggered manually, the outdated resourcetrackers are recycled even in passive mode
+	manual bool
+	// keep selects the resources to be left in the cluster when their resourcetrackers are recycled manually
+	keep []v1alpha1.ResourcePolicyRuleSelector
+	// preview indicates the gc is only previewed, the resourcetrackers to mark are decided without the probability
+	preview bool
 }
 
 func newGCConfig(options ...GCOption) *gcConfig {
@@ -101,6 +108,12 @@ func newGCConfig(options ...GCOption) *gcConfig {
 // NOTE: Mark Stage will only work when Workflow succeeds. Check/Finalize Stage will always work.
 //       For one single application, the deletion will follow Mark -> Finalize -> Sweep
 func (h *resourceKeeper) GarbageCollect(ctx context.Context, options ...GCOption) (finished bool, waiting []v1beta1.ManagedResource, err error) {
+	cfg := newGCConfig(h.withGarbageCollectPolicy(options)...)
+	return h.garbageCollect(ctx, cfg)
+}
+
+// withGarbageCollectPolicy add the gc options from the garbage-collect policy
+func (h *resourceKeeper) withGarbageCollectPolicy(options []GCOption) []GCOption {
 	if h.garbageCollectPolicy != nil {
 		if h.garbageCollectPolicy.KeepLegacyResource {
 			options = append(options, PassiveGCOption{})
@@ -111,8 +124,7 @@ func (h *resourceKeeper) GarbageCollect(ctx context.Context, options ...GCOption
 		default:
 		}
 	}
-	cfg := newGCConfig(options...)
-	return h.garbageCollect(ctx, cfg)
+	return options
 }
 
 func (h *resourceKeeper) garbageCollect(ctx context.Context, cfg *gcConfig) (finished bool, waiting []v1beta1.ManagedResource, err error) {
@@ -176,26 +188,11 @@ func (h *gcHandler) scan(ctx context.Context) (inactiveRTs []*v1beta1.ResourceTr
 		inactiveRTs = append(inactiveRTs, h._historyRTs...)
 		inactiveRTs = append(inactiveRTs, h._currentRT, h._rootRT, h._crRT)
 	} else {
-		if h.cfg.passive {
-			inactiveRTs = []*v1beta1.ResourceTracker{}
-			if rand.Float64() > MarkWithProbability { //nolint
-				return inactiveRTs
-			}
-			for _, rt := range h._historyRTs {
-				if rt != nil {
-					inactive := true
-					for _, mr := range rt.Spec.ManagedResources {
-						entry := h.cache.get(auth.ContextWithUserInfo(ctx, h.app), mr)
-						if entry.err == nil && (entry.gcExecutorRT != rt || !entry.exists) {
-							continue
-						}
-						inactive = false
-					}
-					if inactive {
-						inactiveRTs = append(inactiveRTs, rt)
-					}
-				}
+		if h.cfg.passive && !h.cfg.manual {
+			if !h.cfg.preview && rand.Float64() > MarkWithProbability { //nolint
+				return []*v1beta1.ResourceTracker{}
 			}
+			inactiveRTs = h.scanRecycledHistoryRTs(ctx)
 		} else {
 			inactiveRTs = h._historyRTs
 		}
@@ -203,10 +200,41 @@ func (h *gcHandler) scan(ctx context.Context) (inactiveRTs []*v1beta1.ResourceTr
 	return inactiveRTs
 }
 
+// scanRecycledHistoryRTs find the history resourcetrackers whose managed resources are all recycled
+func (h *gcHandler) scanRecycledHistoryRTs(ctx context.Context) (inactiveRTs []*v1beta1.ResourceTracker) {
+	for _, rt := range h._historyRTs {
+		if rt != nil {
+			inactive := true
+			for _, mr := range rt.Spec.ManagedResources {
+				entry := h.cache.get(auth.ContextWithUserInfo(ctx, h.app), mr)
+				if entry.err == nil && (entry.gcExecutorRT != rt || !entry.exists) {
+					continue
+				}
+				inactive = false
+			}
+			if inactive {
+				inactiveRTs = append(inactiveRTs, rt)
+			}
+		}
+	}
+	return inactiveRTs
+}
+
 func (h *gcHandler) Mark(ctx context.Context) error {
 	cb := h.monitor("mark")
 	defer cb()
 	inactiveRTs := h.scan(ctx)
+	// the kept resources must be untracked from all the resourcetrackers before any of them is deleted, otherwise
+	// the controller may recycle them through another resourcetracker sharing them once it sees the deletion
+	if h.cfg.manual && len(h.cfg.keep) > 0 {
+		for _, rt := range inactiveRTs {
+			if rt != nil && rt.GetDeletionTimestamp() == nil {
+				if err := h.untrackKeptResources(ctx, rt); err != nil {
+					return err
+				}
+			}
+		}
+	}
 	for _, rt := range inactiveRTs {
 		if rt != nil && rt.GetDeletionTimestamp() == nil {
 			if err := h.Client.Delete(ctx, rt); err != nil && !kerrors.IsNotFound(err) {
@@ -232,7 +260,7 @@ func (h *gcHandler) checkAndRemoveResourceTrackerFinalizer(ctx context.Context,
 		if entry.err != nil {
 			return false, entry.mr, entry.err
 		}
-		if entry.exists && entry.gcExecutorRT == rt {
+		if entry.exists && entry.gcExecutorRT == rt && !h.isReadOnlyManagedResource(mr) {
 			return false, entry.mr, nil
 		}
 	}
@@ -335,7 +363,7 @@ func (h *gcHandler) deleteManagedResource(ctx context.Context, mr v1beta1.Manage
 	if entry.err != nil {
 		return entry.err
 	}
-	if entry.exists {
+	if entry.exists && !h.isReadOnlyManagedResource(mr) {
 		_ctx := multicluster.ContextWithClusterName(ctx, mr.Cluster)
 		if annotations := entry.obj.GetAnnotations(); annotations != nil && annotations[oam.AnnotationAppSharedBy] != "" {
 			sharedBy := apply.RemoveSharer(annotations[oam.AnnotationAppSharedBy], h.app)
@@ -393,6 +421,41 @@ func (h *gcHandler) Finalize(ctx context.Context) error {
 	return nil
 }
 
+// untrackKeptResources removes the resources selected by the keep selectors from the resourcetracker before it's
+// marked as deleted, so they are left in the cluster when the resourcetracker is recycled. The resources are removed
+// even if the resourcetracker is not the gc executor of them, since the older resourcetrackers sharing them become
+// the gc executor once the newer ones are recycled.
+func (h *gcHandler) untrackKeptResources(ctx context.Context, rt *v1beta1.ResourceTracker) error {
+	var managedResources []v1beta1.ManagedResource
+	for _, mr := range rt.Spec.ManagedResources {
+		entry := h.cache.get(auth.ContextWithUserInfo(ctx, h.app), mr)
+		if entry.err == nil && entry.exists {
+			if kept, _ := h.isKept(entry.obj); kept {
+				continue
+			}
+		}
+		managedResources = append(managedResources, mr)
+	}
+	if len(managedResources) == len(rt.Spec.ManagedResources) {
+		return nil
+	}
+	rt.Spec.ManagedResources = managedResources
+	if err := h.Client.Update(ctx, rt); err != nil {
+		return errors.Wrapf(err, "failed to remove kept resources from resourcetracker %s", rt.Name)
+	}
+	return nil
+}
+
+// isKept check if the resource is selected by the keep selectors, and explain why it's selected
+func (h *gcHandler) isKept(manifest *unstructured.Unstructured) (bool, string) {
+	for i := range h.cfg.keep {
+		if matched, reason := h.cfg.keep[i].MatchWithReason(manifest); matched {
+			return true, reason
+		}
+	}
+	return false, ""
+}
+
 func (h *gcHandler) GarbageCollectComponentRevisionResourceTracker(ctx context.Context) error {
 	cb := h.monitor("comp-rev")
 	defer cb()
@@ -0,0 +1,211 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcekeeper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/auth"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/resourcetracker"
+	"github.com/oam-dev/kubevela/pkg/utils"
+	"github.com/oam-dev/kubevela/pkg/utils/apply"
+)
+
+// GarbageCollectAction is the action taken on the resource by the garbage collection
+type GarbageCollectAction string
+
+const (
+	// GarbageCollectActionDelete deletes the resource
+	GarbageCollectActionDelete GarbageCollectAction = "delete"
+	// GarbageCollectActionUnshare removes the application from the sharers of the resource, the resource is kept for
+	// other sharers
+	GarbageCollectActionUnshare GarbageCollectAction = "unshare"
+	// GarbageCollectActionKeep leaves the resource in the cluster, it's no longer tracked by the application
+	GarbageCollectActionKeep GarbageCollectAction = "keep"
+)
+
+// GarbageCollectPlan is the preview of the garbage collection
+type GarbageCollectPlan struct {
+	// ResourceTrackers are the resourcetrackers to be recycled
+	ResourceTrackers []string `json:"resourceTrackers,omitempty"`
+	// Resources are the resources to be recycled, in the order of deletion
+	Resources []GarbageCollectPlanResource `json:"resources,omitempty"`
+}
+
+// GarbageCollectPlanResource is the resource to be recycled in the garbage collection
+type GarbageCollectPlanResource struct {
+	v1beta1.ManagedResource `json:",inline"`
+	// ResourceTracker is the resourcetracker recycling the resource
+	ResourceTracker string `json:"resourceTracker"`
+	// Action is the action taken on the resource
+	Action GarbageCollectAction `json:"action"`
+	// Reason explains why the resource is recycled
+	Reason string `json:"reason"`
+	// Order is the order of deletion when the resources are recycled in the order of dependency, the resources with
+	// larger order are deleted after the ones with smaller order are gone
+	Order int `json:"order"`
+	// BlockedBy are the dependent components to be recycled before the resource
+	BlockedBy []string `json:"blockedBy,omitempty"`
+}
+
+// PreviewGarbageCollect returns the plan of the garbage collection with the same options as GarbageCollect, without
+// changing anything in the cluster
+func (h *resourceKeeper) PreviewGarbageCollect(ctx context.Context, options ...GCOption) (*GarbageCollectPlan, error) {
+	cfg := newGCConfig(h.withGarbageCollectPolicy(options)...)
+	cfg.preview = true
+	ctx = auth.ContextWithUserInfo(ctx, h.app)
+	// the gc handler with a separated cache, so the cache of the resourcekeeper is not affected by the preview
+	gc := &gcHandler{cfg: cfg, resourceKeeper: &resourceKeeper{
+		Client:         h.Client,
+		app:            h.app,
+		_rootRT:        h._rootRT,
+		_currentRT:     h._currentRT,
+		_historyRTs:    h._historyRTs,
+		_crRT:          h._crRT,
+		readOnlyPolicy: h.readOnlyPolicy,
+		cache:          newResourceCache(h.Client, h.app),
+	}}
+	gc.Init()
+
+	// Mark Stage, simulated on the copies of resourcetrackers
+	reasons := map[*v1beta1.ResourceTracker]string{}
+	if !cfg.disableMark {
+		reason := "the resourcetracker is outdated"
+		switch {
+		case h.app.GetDeletionTimestamp() != nil:
+			reason = "the application is deleted"
+		case cfg.passive && !cfg.manual:
+			reason = "all the resources of the outdated resourcetracker are recycled or taken over"
+		case cfg.manual:
+			reason = "the outdated resourcetracker is recycled manually"
+		}
+		for _, rt := range gc.scan(ctx) {
+			if rt != nil && rt.GetDeletionTimestamp() == nil {
+				reasons[rt] = reason
+			}
+		}
+	}
+	var rts []*v1beta1.ResourceTracker
+	now := metav1.Now()
+	for _, rt := range append(append([]*v1beta1.ResourceTracker{}, h._historyRTs...), h._currentRT, h._rootRT) {
+		if rt == nil {
+			continue
+		}
+		reason, marked := reasons[rt]
+		rt = rt.DeepCopy()
+		if marked {
+			rt.SetDeletionTimestamp(&now)
+		} else {
+			reason = "the resourcetracker is being deleted"
+		}
+		reasons[rt] = reason
+		rts = append(rts, rt)
+	}
+	gc.cache = newResourceCache(h.Client, h.app)
+	gc.cache.registerResourceTrackers(rts...)
+
+	// Finalize Stage
+	plan := &GarbageCollectPlan{}
+	for _, rt := range rts {
+		if rt.GetDeletionTimestamp() == nil || !meta.FinalizerExists(rt, resourcetracker.Finalizer) {
+			continue
+		}
+		plan.ResourceTrackers = append(plan.ResourceTrackers, rt.Name)
+		var resources []GarbageCollectPlanResource
+		for _, mr := range rt.Spec.ManagedResources {
+			entry := gc.cache.get(ctx, mr)
+			if entry.gcExecutorRT != rt {
+				continue
+			}
+			if entry.err != nil {
+				return nil, entry.err
+			}
+			if !entry.exists || gc.isReadOnlyManagedResource(mr) {
+				continue
+			}
+			mr.Data = nil
+			resource := GarbageCollectPlanResource{ManagedResource: mr, ResourceTracker: rt.Name, Action: GarbageCollectActionDelete, Reason: reasons[rt]}
+			if kept, reason := gc.isKept(entry.obj); kept {
+				resource.Action, resource.Reason = GarbageCollectActionKeep, "selected by the keep selector: "+reason
+			} else if annotations := entry.obj.GetAnnotations(); annotations != nil && annotations[oam.AnnotationAppSharedBy] != "" {
+				if sharedBy := apply.RemoveSharer(annotations[oam.AnnotationAppSharedBy], h.app); sharedBy != "" {
+					resource.Action, resource.Reason = GarbageCollectActionUnshare, fmt.Sprintf("%s, still shared by %s", reasons[rt], sharedBy)
+				}
+			}
+			resources = append(resources, resource)
+		}
+		if cfg.order == v1alpha1.OrderDependency {
+			gc.orderByDependency(resources)
+		}
+		plan.Resources = append(plan.Resources, resources...)
+	}
+	return plan, nil
+}
+
+// orderByDependency sorts the resources of one resourcetracker in the order of deletion. The resources of one component
+// are deleted after the resources of its dependent components are gone.
+func (h *gcHandler) orderByDependency(resources []GarbageCollectPlanResource) {
+	recycled := map[string]bool{}
+	for _, resource := range resources {
+		if resource.Action != GarbageCollectActionKeep {
+			recycled[resource.Component] = true
+		}
+	}
+	orders := map[string]int{}
+	visiting := map[string]bool{}
+	var getOrder func(mr v1beta1.ManagedResource) (int, []string)
+	getOrder = func(mr v1beta1.ManagedResource) (int, []string) {
+		var blockedBy []string
+		for _, dependent := range h.checkDependentComponent(mr) {
+			if recycled[dependent] && !utils.StringsContain(blockedBy, dependent) {
+				blockedBy = append(blockedBy, dependent)
+			}
+		}
+		if order, found := orders[mr.Component]; found {
+			return order, blockedBy
+		}
+		order := 0
+		// the circular dependency is not expected, break it to avoid infinite recursion
+		visiting[mr.Component] = true
+		for _, dependent := range blockedBy {
+			if !visiting[dependent] {
+				if o, _ := getOrder(v1beta1.ManagedResource{OAMObjectReference: common.OAMObjectReference{Component: dependent}}); o+1 > order {
+					order = o + 1
+				}
+			}
+		}
+		visiting[mr.Component] = false
+		orders[mr.Component] = order
+		return order, blockedBy
+	}
+	for i := range resources {
+		if resources[i].Action == GarbageCollectActionKeep {
+			continue
+		}
+		resources[i].Order, resources[i].BlockedBy = getOrder(resources[i].ManagedResource)
+	}
+	sort.SliceStable(resources, func(i, j int) bool { return resources[i].Order < resources[j].Order })
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcekeeper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	utilscommon "github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestPreviewGarbageCollect(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(utilscommon.Scheme).Build()
+	app := &v1beta1.Application{
+		ObjectMeta: v12.ObjectMeta{Name: "app", Namespace: "default", Generation: 1},
+		Spec: v1beta1.ApplicationSpec{Components: []common.ApplicationComponent{
+			{Name: "a"},
+			{Name: "b", DependsOn: []string{"a"}},
+			{Name: "c", DependsOn: []string{"b"}},
+		}},
+	}
+	configMap := func(name string) *unstructured.Unstructured {
+		cm := &unstructured.Unstructured{}
+		cm.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("ConfigMap"))
+		cm.SetName(name)
+		cm.SetNamespace("default")
+		cm.SetLabels(map[string]string{oam.LabelAppName: "app", oam.LabelAppNamespace: "default", oam.LabelAppComponent: name})
+		return cm
+	}
+	exists := func(name string) bool {
+		err := cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, &v1.ConfigMap{})
+		if err != nil {
+			r.True(kerrors.IsNotFound(err))
+		}
+		return err == nil
+	}
+
+	rk, err := NewResourceKeeper(ctx, cli, app)
+	r.NoError(err)
+	r.NoError(rk.Dispatch(ctx, []*unstructured.Unstructured{configMap("a"), configMap("b"), configMap("c")}, nil))
+	app.SetGeneration(2)
+	_rk, err := NewResourceKeeper(ctx, cli, app)
+	r.NoError(err)
+	r.NoError(_rk.Dispatch(ctx, []*unstructured.Unstructured{configMap("d")}, nil))
+	_rk, err = NewResourceKeeper(ctx, cli, app)
+	r.NoError(err)
+	rk = _rk
+	h := rk.(*resourceKeeper)
+	r.Len(h._historyRTs, 1)
+	historyRT := h._historyRTs[0].Name
+
+	components := func(plan *GarbageCollectPlan) (comps []string) {
+		for _, resource := range plan.Resources {
+			comps = append(comps, resource.Component)
+		}
+		return comps
+	}
+
+	// the outdated resourcetracker is recycled in the order of dependency
+	h.garbageCollectPolicy = &v1alpha1.GarbageCollectPolicySpec{Order: v1alpha1.OrderDependency}
+	plan, err := rk.PreviewGarbageCollect(ctx)
+	r.NoError(err)
+	r.Equal([]string{historyRT}, plan.ResourceTrackers)
+	r.Equal([]string{"c", "b", "a"}, components(plan))
+	r.Equal(0, plan.Resources[0].Order)
+	r.Empty(plan.Resources[0].BlockedBy)
+	r.Equal(1, plan.Resources[1].Order)
+	r.Equal([]string{"c"}, plan.Resources[1].BlockedBy)
+	r.Equal(2, plan.Resources[2].Order)
+	r.Equal([]string{"b"}, plan.Resources[2].BlockedBy)
+	r.Equal(GarbageCollectActionDelete, plan.Resources[0].Action)
+	r.Equal("the resourcetracker is outdated", plan.Resources[0].Reason)
+	r.Nil(plan.Resources[0].Data)
+
+	// nothing is changed by the preview
+	r.True(exists("a") && exists("b") && exists("c") && exists("d"))
+	rt := &v1beta1.ResourceTracker{}
+	r.NoError(cli.Get(ctx, client.ObjectKey{Name: historyRT}, rt))
+	r.Nil(rt.GetDeletionTimestamp())
+
+	// the outdated resourcetracker is not recycled in the passive mode until its resources are recycled
+	h.garbageCollectPolicy.KeepLegacyResource = true
+	plan, err = rk.PreviewGarbageCollect(ctx)
+	r.NoError(err)
+	r.Empty(plan.ResourceTrackers)
+	r.Empty(plan.Resources)
+
+	// the manual gc recycles the outdated resourcetracker and keeps the selected resources
+	keep := ManualGCOption{Keep: []v1alpha1.ResourcePolicyRuleSelector{{CompNames: []string{"c"}}}}
+	plan, err = rk.PreviewGarbageCollect(ctx, keep)
+	r.NoError(err)
+	r.Len(plan.Resources, 3)
+	resources := map[string]GarbageCollectPlanResource{}
+	for _, resource := range plan.Resources {
+		resources[resource.Component] = resource
+	}
+	r.Equal(1, resources["a"].Order)
+	r.Equal(0, resources["b"].Order)
+	r.Empty(resources["b"].BlockedBy)
+	r.Equal("the outdated resourcetracker is recycled manually", resources["b"].Reason)
+	r.Equal(GarbageCollectActionKeep, resources["c"].Action)
+	r.Equal(`selected by the keep selector: componentNames "c" matches "c"`, resources["c"].Reason)
+
+	// the kept resources are untracked before the resourcetracker is marked as deleted
+	gc := &gcHandler{resourceKeeper: h, cfg: newGCConfig(keep)}
+	gc.Init()
+	r.NoError(gc.Mark(ctx))
+	r.NoError(cli.Get(ctx, client.ObjectKey{Name: historyRT}, rt))
+	r.NotNil(rt.GetDeletionTimestamp())
+	r.Len(rt.Spec.ManagedResources, 2)
+	r.True(exists("a") && exists("b") && exists("c"))
+
+	_, _, err = rk.GarbageCollect(ctx, keep)
+	r.NoError(err)
+	r.True(exists("a"))
+	r.False(exists("b"))
+	r.True(exists("c"))
+	r.NoError(cli.Get(ctx, client.ObjectKey{Name: historyRT}, rt))
+	r.NotNil(rt.GetDeletionTimestamp())
+	r.Len(rt.Spec.ManagedResources, 2)
+}
+
+func TestManualGarbageCollectKeepSharedResource(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(utilscommon.Scheme).Build()
+	app := &v1beta1.Application{ObjectMeta: v12.ObjectMeta{Name: "app", Namespace: "default", Generation: 1}}
+	configMap := func(name string) *unstructured.Unstructured {
+		cm := &unstructured.Unstructured{}
+		cm.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("ConfigMap"))
+		cm.SetName(name)
+		cm.SetNamespace("default")
+		cm.SetLabels(map[string]string{oam.LabelAppName: "app", oam.LabelAppNamespace: "default", oam.LabelAppComponent: name})
+		return cm
+	}
+	exists := func(name string) bool {
+		err := cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, &v1.ConfigMap{})
+		if err != nil {
+			r.True(kerrors.IsNotFound(err))
+		}
+		return err == nil
+	}
+	dispatch := func(generation int64, names ...string) {
+		app.SetGeneration(generation)
+		rk, err := NewResourceKeeper(ctx, cli, app)
+		r.NoError(err)
+		var manifests []*unstructured.Unstructured
+		for _, name := range names {
+			manifests = append(manifests, configMap(name))
+		}
+		r.NoError(rk.Dispatch(ctx, manifests, nil))
+	}
+
+	// the resource shared is tracked by both of the outdated resourcetrackers
+	dispatch(1, "shared", "a")
+	dispatch(2, "shared", "b")
+	dispatch(3, "c")
+	rk, err := NewResourceKeeper(ctx, cli, app)
+	r.NoError(err)
+	r.Len(rk.(*resourceKeeper)._historyRTs, 2)
+
+	keep := ManualGCOption{Keep: []v1alpha1.ResourcePolicyRuleSelector{{CompNames: []string{"shared"}}}}
+	for i := 0; i < 3; i++ {
+		rk, err = NewResourceKeeper(ctx, cli, app)
+		r.NoError(err)
+		_, _, err = rk.GarbageCollect(ctx, keep)
+		r.NoError(err)
+	}
+	r.True(exists("shared"))
+	r.False(exists("a"))
+	r.False(exists("b"))
+	r.True(exists("c"))
+	rts := &v1beta1.ResourceTrackerList{}
+	r.NoError(cli.List(ctx, rts))
+	for _, rt := range rts.Items {
+		for _, mr := range rt.Spec.ManagedResources {
+			r.NotEqual("shared", mr.Name, rt.Name)
+		}
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package resourcekeeper
 
 import (
+	"context"
+
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 )
@@ -32,6 +34,22 @@ type MetaOnlyOption struct{}
 // ApplyToDispatchConfig apply change to dispatch config
 func (option MetaOnlyOption) ApplyToDispatchConfig(cfg *dispatchConfig) { cfg.metaOnly = true }
 
+type dispatchOptionsKey struct{}
+
+// ContextWithDispatchOptions attach the dispatch options to the context, they are applied by the Dispatch receiving
+// the context. It allows the workflow providers, which dispatch resources through kube.Dispatcher, to set options like
+// MetaOnlyOption.
+func ContextWithDispatchOptions(ctx context.Context, options ...DispatchOption) context.Context {
+	return context.WithValue(ctx, dispatchOptionsKey{}, append(dispatchOptionsFromContext(ctx), options...))
+}
+
+func dispatchOptionsFromContext(ctx context.Context) []DispatchOption {
+	if options, ok := ctx.Value(dispatchOptionsKey{}).([]DispatchOption); ok {
+		return append([]DispatchOption{}, options...)
+	}
+	return nil
+}
+
 // CreatorOption set the creator of the resource
 type CreatorOption struct {
 	Creator common.ResourceCreatorRole
@@ -100,6 +118,18 @@ func (option DisableLegacyGCOption) ApplyToGCConfig(cfg *gcConfig) {
 	cfg.disableLegacyGC = true
 }
 
+// ManualGCOption trigger the gc manually. The outdated versioned resourcetrackers are recycled even if the gc is
+// passive, and the resources selected by Keep are removed from the recycled resourcetrackers and left in the cluster.
+type ManualGCOption struct {
+	Keep []v1alpha1.ResourcePolicyRuleSelector
+}
+
+// ApplyToGCConfig apply change to gc config
+func (option ManualGCOption) ApplyToGCConfig(cfg *gcConfig) {
+	cfg.manual = true
+	cfg.keep = option.Keep
+}
+
 // GarbageCollectStrategyOption apply garbage collect strategy to resourcetracker recording
 type GarbageCollectStrategyOption v1alpha1.GarbageCollectStrategy
 
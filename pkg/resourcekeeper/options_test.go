@@ -17,6 +17,7 @@ limitations under the License.
 package resourcekeeper
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -47,6 +48,15 @@ func TestDispatchOptions(t *testing.T) {
 	}
 }
 
+func TestContextWithDispatchOptions(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	r.Empty(dispatchOptionsFromContext(ctx))
+	ctx = ContextWithDispatchOptions(ctx, MetaOnlyOption{})
+	ctx = ContextWithDispatchOptions(ctx, UseRootOption{})
+	r.Equal(dispatchConfig{metaOnly: true, rtConfig: rtConfig{useRoot: true}}, *newDispatchConfig(dispatchOptionsFromContext(ctx)...))
+}
+
 func TestDeleteOptions(t *testing.T) {
 	testCases := map[string]struct {
 		option DeleteOption
@@ -40,7 +40,9 @@ type ResourceKeeper interface {
 	Dispatch(context.Context, []*unstructured.Unstructured, []apply.ApplyOption, ...DispatchOption) error
 	Delete(context.Context, []*unstructured.Unstructured, ...DeleteOption) error
 	GarbageCollect(context.Context, ...GCOption) (bool, []v1beta1.ManagedResource, error)
+	PreviewGarbageCollect(context.Context, ...GCOption) (*GarbageCollectPlan, error)
 	StateKeep(context.Context) error
+	Drifts() []DriftedResource
 	ContainsResources([]*unstructured.Unstructured) bool
 
 	DispatchComponentRevision(context.Context, *appsv1.ControllerRevision) error
@@ -61,8 +63,13 @@ type resourceKeeper struct {
 	applyOncePolicy      *v1alpha1.ApplyOncePolicySpec
 	garbageCollectPolicy *v1alpha1.GarbageCollectPolicySpec
 	sharedResourcePolicy *v1alpha1.SharedResourcePolicySpec
+	ssaPolicy            *v1alpha1.ServerSideApplyPolicySpec
+	driftPolicy          *v1alpha1.DriftDetectionPolicySpec
+	readOnlyPolicy       *v1alpha1.ReadOnlyPolicySpec
+	takeOverPolicy       *v1alpha1.TakeOverPolicySpec
 
-	cache *resourceCache
+	cache  *resourceCache
+	drifts []DriftedResource
 }
 
 func (h *resourceKeeper) getRootRT(ctx context.Context) (rootRT *v1beta1.ResourceTracker, err error) {
@@ -105,6 +112,18 @@ func (h *resourceKeeper) parseApplicationResourcePolicy() (err error) {
 	if h.sharedResourcePolicy, err = policy.ParseSharedResourcePolicy(h.app); err != nil {
 		return errors.Wrapf(err, "failed to parse shared-resource policy")
 	}
+	if h.ssaPolicy, err = policy.ParseServerSideApplyPolicy(h.app); err != nil {
+		return errors.Wrapf(err, "failed to parse server-side-apply policy")
+	}
+	if h.driftPolicy, err = policy.ParseDriftDetectionPolicy(h.app); err != nil {
+		return errors.Wrapf(err, "failed to parse drift-detection policy")
+	}
+	if h.readOnlyPolicy, err = policy.ParseReadOnlyPolicy(h.app); err != nil {
+		return errors.Wrapf(err, "failed to parse read-only policy")
+	}
+	if h.takeOverPolicy, err = policy.ParseTakeOverPolicy(h.app); err != nil {
+		return errors.Wrapf(err, "failed to parse take-over policy")
+	}
 	return nil
 }
 
@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/pkg/auth"
 	"github.com/oam-dev/kubevela/pkg/multicluster"
@@ -31,7 +32,10 @@ import (
 )
 
 // StateKeep run this function to keep resources up-to-date
+// If the drift-detection policy is set, the drift of the resources is detected before re-applying them, and the
+// drifted resources are only re-applied in the correct mode. The detected drift is returned by Drifts.
 func (h *resourceKeeper) StateKeep(ctx context.Context) error {
+	h.drifts = nil
 	if h.applyOncePolicy != nil && h.applyOncePolicy.Enable && h.applyOncePolicy.Rules == nil {
 		return nil
 	}
@@ -43,6 +47,10 @@ func (h *resourceKeeper) StateKeep(ctx context.Context) error {
 				if entry.err != nil {
 					return entry.err
 				}
+				if h.isReadOnlyManagedResource(mr) {
+					// the read-only resources are never written or deleted
+					continue
+				}
 				if mr.Deleted {
 					if entry.exists && entry.obj != nil && entry.obj.GetDeletionTimestamp() == nil {
 						deleteCtx := multicluster.ContextWithClusterName(ctx, mr.Cluster)
@@ -64,10 +72,18 @@ func (h *resourceKeeper) StateKeep(ctx context.Context) error {
 					if err != nil {
 						return errors.Wrapf(err, "failed to apply once resource %s from resourcetracker %s", mr.ResourceKey(), rt.Name)
 					}
-					ao := []apply.ApplyOption{apply.MustBeControlledByApp(h.app)}
-					if h.isShared(manifest) {
-						ao = append([]apply.ApplyOption{apply.SharedByApp(h.app)}, ao...)
+					if h.driftPolicy != nil {
+						mode := h.driftPolicy.FindMode(manifest)
+						if drift := h.detectDrift(mr, entry, manifest, mode); drift != nil {
+							drift.Corrected = mode == v1alpha1.DriftDetectionModeCorrect
+							h.drifts = append(h.drifts, *drift)
+						}
+						if mode != v1alpha1.DriftDetectionModeCorrect {
+							continue
+						}
 					}
+					ao := h.withResourcePolicies(manifest, []apply.ApplyOption{apply.MustBeControlledByApp(h.app)})
+					ao = h.withServerSideApply(manifest, ao)
 					if err = h.applicator.Apply(applyCtx, manifest, ao...); err != nil {
 						return errors.Wrapf(err, "failed to re-apply resource %s from resourcetracker %s", mr.ResourceKey(), rt.Name)
 					}
@@ -78,6 +94,39 @@ func (h *resourceKeeper) StateKeep(ctx context.Context) error {
 	return nil
 }
 
+// detectDrift compares the desired manifest with the live resource, the fields of the apply-once paths are already
+// synced from the live resource by ApplyStrategies, so they are not treated as drift
+func (h *resourceKeeper) detectDrift(mr v1beta1.ManagedResource, entry *resourceCacheEntry, manifest *unstructured.Unstructured, mode v1alpha1.DriftDetectionMode) *DriftedResource {
+	mr.Data = nil
+	if !entry.exists {
+		// the resources owned by the others are not drift
+		if entry.obj != nil && entry.obj.GetResourceVersion() != "" {
+			return nil
+		}
+		return &DriftedResource{Resource: mr, Mode: mode, Missing: true}
+	}
+	if fields := ComputeDrift(manifest, entry.obj, h.driftFieldManager(manifest)); len(fields) > 0 {
+		return &DriftedResource{Resource: mr, Mode: mode, Fields: fields}
+	}
+	return nil
+}
+
+// driftFieldManager returns the field manager of the server-side apply if the resource is applied by it
+func (h *resourceKeeper) driftFieldManager(manifest *unstructured.Unstructured) string {
+	if h.ssaPolicy == nil || !h.ssaPolicy.FindStrategy(manifest) {
+		return ""
+	}
+	if h.ssaPolicy.FieldManager == "" {
+		return apply.DefaultFieldManager
+	}
+	return h.ssaPolicy.FieldManager
+}
+
+// Drifts return the drift detected by the last StateKeep
+func (h *resourceKeeper) Drifts() []DriftedResource {
+	return h.drifts
+}
+
 // ApplyStrategies will generate manifest with applyOnceStrategy
 func ApplyStrategies(ctx context.Context, h *resourceKeeper, manifest *unstructured.Unstructured) (*unstructured.Unstructured, error) {
 	if h.applyOncePolicy == nil {
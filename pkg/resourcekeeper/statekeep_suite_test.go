@@ -289,6 +289,7 @@ var _ = Describe("Test ResourceKeeper StateKeep", func() {
 		// state-keep add this resource
 		replicas := int32(2)
 		deploy := createDeployment("fourierapp03-comp-01", &replicas)
+		deploy.SetLabels(map[string]string{oam.LabelAppComponent: "fourierapp03-comp-01"})
 		deployRaw, err := json.Marshal(deploy)
 		Expect(err).Should(Succeed())
 
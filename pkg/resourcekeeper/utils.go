@@ -19,6 +19,11 @@ package resourcekeeper
 import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/utils/apply"
 )
 
 // ClearNamespaceForClusterScopedResources clear namespace for cluster scoped resources
@@ -40,3 +45,67 @@ func (h *resourceKeeper) isShared(manifest *unstructured.Unstructured) bool {
 	}
 	return h.sharedResourcePolicy.FindStrategy(manifest)
 }
+
+func (h *resourceKeeper) isReadOnly(manifest *unstructured.Unstructured) bool {
+	if h.readOnlyPolicy == nil {
+		return false
+	}
+	return h.readOnlyPolicy.FindStrategy(manifest)
+}
+
+func (h *resourceKeeper) canTakeOver(manifest *unstructured.Unstructured) bool {
+	if h.takeOverPolicy == nil {
+		return false
+	}
+	return h.takeOverPolicy.FindStrategy(manifest)
+}
+
+// isReadOnlyManagedResource checks if the managed resource is marked as read-only when it's dispatched. The resources
+// recorded before the mark is introduced are checked by the read-only policy, the recorded data is used for matching
+// if exists since the live read-only resources don't have the labels of the application
+func (h *resourceKeeper) isReadOnlyManagedResource(mr v1beta1.ManagedResource) bool {
+	if mr.ReadOnly {
+		return true
+	}
+	if h.readOnlyPolicy == nil {
+		return false
+	}
+	if mr.Data != nil && mr.Data.Raw != nil {
+		if manifest, err := mr.ToUnstructuredWithData(); err == nil {
+			return h.isReadOnly(manifest)
+		}
+	}
+	manifest := mr.ToUnstructured()
+	labels := map[string]string{}
+	if mr.Component != "" {
+		labels[oam.LabelAppComponent] = mr.Component
+	}
+	if mr.Trait != "" {
+		labels[oam.TraitTypeLabel] = mr.Trait
+	}
+	manifest.SetLabels(labels)
+	return h.isReadOnly(manifest)
+}
+
+// withResourcePolicies prepends the apply options of the shared-resource, read-only and take-over policies
+func (h *resourceKeeper) withResourcePolicies(manifest *unstructured.Unstructured, ao []apply.ApplyOption) []apply.ApplyOption {
+	if h.isShared(manifest) {
+		ao = append([]apply.ApplyOption{apply.SharedByApp(h.app)}, ao...)
+	}
+	if h.isReadOnly(manifest) {
+		ao = append([]apply.ApplyOption{apply.ReadOnly()}, ao...)
+	}
+	if h.canTakeOver(manifest) {
+		ao = append([]apply.ApplyOption{apply.TakeOver()}, ao...)
+	}
+	return ao
+}
+
+// withServerSideApply prepends the server-side apply option if the resource is selected by the server-side-apply policy
+func (h *resourceKeeper) withServerSideApply(manifest *unstructured.Unstructured, ao []apply.ApplyOption) []apply.ApplyOption {
+	if h.ssaPolicy == nil || !h.ssaPolicy.FindStrategy(manifest) {
+		return ao
+	}
+	force := h.ssaPolicy.ConflictPolicy != v1alpha1.ServerSideApplyConflictFail
+	return append([]apply.ApplyOption{apply.ServerSideApply(h.ssaPolicy.FieldManager, force)}, ao...)
+}
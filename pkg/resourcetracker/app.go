@@ -206,17 +206,23 @@ func ListApplicationResourceTrackers(ctx context.Context, cli client.Client, app
 	return rootRT, currentRT, historyRTs, crRT, nil
 }
 
-// RecordManifestsInResourceTracker records resources in ResourceTracker
+// RecordManifestsInResourceTracker records resources in ResourceTracker, the resources selected by the read-only policy
+// are marked as read-only
 func RecordManifestsInResourceTracker(
 	ctx context.Context,
 	cli client.Client,
 	rt *v1beta1.ResourceTracker,
 	manifests []*unstructured.Unstructured,
 	metaOnly bool,
+	readOnly bool,
 	creator common.ResourceCreatorRole) error {
 	if len(manifests) != 0 {
 		for _, manifest := range manifests {
-			rt.AddManagedResource(manifest, metaOnly, creator)
+			if readOnly {
+				rt.AddReadOnlyManagedResource(manifest, metaOnly, creator)
+			} else {
+				rt.AddManagedResource(manifest, metaOnly, creator)
+			}
 		}
 		return cli.Update(ctx, rt)
 	}
@@ -30,6 +30,8 @@ import (
 
 #Delete: kube.#Delete
 
+#WaitFor: kube.#WaitFor
+
 #Deploy: multicluster.#Deploy
 
 #ApplyApplication: #Steps & {
@@ -143,6 +145,40 @@ import (
 	}
 }
 
+#Teams: #Steps & {
+	message:  teams.#TeamsMessage
+	teamsUrl: string
+	do:       http.#Do & {
+		method: "POST"
+		url:    teamsUrl
+		request: {
+			body: json.Marshal(message)
+			header: "Content-Type": "application/json"
+		}
+	}
+}
+
+#SignedWebhook: #Steps & {
+	message:    _
+	webhookUrl: string
+	signingKey: string
+	sign:       notification.#Sign & {
+		payload: json.Marshal(message)
+		key:     signingKey
+	}
+	do: http.#Do & {
+		method: "POST"
+		url:    webhookUrl
+		request: {
+			body: sign.payload
+			header: {
+				"Content-Type":     "application/json"
+				"X-Vela-Signature": "sha256=\(sign.signature)"
+			}
+		}
+	}
+}
+
 #ApplyEnvBindApp: multicluster.#ApplyEnvBindApp
 
 #DeployCloudResource: terraform.#DeployCloudResource
@@ -157,6 +193,8 @@ import (
 
 #PatchApplication: multicluster.#PatchApplication
 
+#HTTPDo: http.#Do
+
 #HTTPGet: http.#Do & {method: "GET"}
 
 #HTTPPost: http.#Do & {method: "POST"}
@@ -169,12 +207,58 @@ import (
 
 #Log: util.#Log
 
+#JSONPath: util.#JSONPath
+
+#JMESPath: util.#JMESPath
+
+#RenderTemplate: util.#Template
+
+#Encode: util.#Encode
+
+#Decode: util.#Decode
+
+#Hash: util.#Hash
+
+#Marshal: util.#Marshal
+
+#Unmarshal: util.#Unmarshal
+
+#CompareVersion: util.#CompareVersion
+
+#BumpVersion: util.#BumpVersion
+
 #DateToTimestamp: time.#DateToTimestamp
 
 #TimestampToDate: time.#TimestampToDate
 
 #SendEmail: email.#Send
 
+#QueryMetrics: metrics.#Query
+
+#AnalyzeMetrics: metrics.#Analyze
+
+#GitClone: git.#Clone
+
+#GitWriteFiles: git.#WriteFiles
+
+#GitCommit: git.#Commit
+
+#GitPush: git.#Push
+
+#GenerateSecret: secret.#Generate
+
+#RunJob: job.#Run
+
+#ApprovalLinks: notification.#ApprovalLinks
+
+#SignPayload: notification.#Sign
+
+#ReadConfig: config.#Read
+
+#ListConfigs: config.#List
+
+#WriteConfig: config.#Write
+
 #Load: oam.#LoadComponets
 
 #LoadInOrder: oam.#LoadComponetsInOrder
@@ -0,0 +1,47 @@
+#Config: {
+	name:         string
+	type:         string
+	project:      string
+	alias?:       string
+	description?: string
+	// +usage=The properties of the config, which are the parameters of the config type
+	properties: {...}
+	// +usage=The data of the secret of the config
+	data: [string]: string
+}
+
+#Read: {
+	#do:       "read"
+	#provider: "config"
+
+	name: string
+	// +usage=The type of the config, the step fails if the config is not of the type
+	type?: string
+
+	config?: #Config
+	...
+}
+
+#List: {
+	#do:       "list"
+	#provider: "config"
+
+	type: string
+
+	// +usage=The configs of the type that can be used in the project of the application
+	configs?: [...#Config]
+	...
+}
+
+#Write: {
+	#do:       "write"
+	#provider: "config"
+
+	name: string
+	// +usage=The type of the config, the properties are validated with the parameters of the type
+	type:         string
+	properties: {...}
+	alias?:       string
+	description?: string
+	...
+}
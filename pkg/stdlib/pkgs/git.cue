@@ -0,0 +1,81 @@
+#Clone: {
+	#do:       "clone"
+	#provider: "git"
+
+	// +usage=The url of the git repository
+	url: string
+	// +usage=The branch to checkout, it's created if not exists in the repository
+	branch: *"main" | string
+	// +usage=The secret that contains the credentials (username/password or ssh-privatekey) of the repository
+	secretRef?: {
+		name:       string
+		namespace?: string
+	}
+
+	// +usage=The local path of the repository
+	path?: string
+	// +usage=The sha of the latest commit of the branch
+	sha?: string
+	...
+}
+
+#WriteFiles: {
+	#do:       "write"
+	#provider: "git"
+
+	// +usage=The local path of the repository
+	path: string
+	// +usage=The directory in the repository to write the files
+	dir: *"" | string
+	// +usage=Whether to remove the existing files in the directory before writing, the dir must be set explicitly, use "." for the whole repository
+	clean: *false | bool
+	files: [...{
+		// +usage=The file name relative to the directory
+		name: string
+		// +usage=The raw content of the file
+		content?: string
+		// +usage=The values to write as yaml documents
+		values?: [...]
+	}]
+	...
+}
+
+#Commit: {
+	#do:       "commit"
+	#provider: "git"
+
+	// +usage=The local path of the repository
+	path: string
+	// +usage=The template of the commit message, such as "Update {{.AppName}}", the fields are AppName, Namespace, Files and Data
+	message: string
+	author: {
+		name:  *"KubeVela" | string
+		email: *"kubevela@kubevela.io" | string
+	}
+	// +usage=The extra data to render the message template
+	data?: {...}
+
+	// +usage=Whether there are changes committed
+	changed?: bool
+	// +usage=The sha of the latest commit
+	sha?: string
+	...
+}
+
+#Push: {
+	#do:       "push"
+	#provider: "git"
+
+	// +usage=The local path of the repository
+	path: string
+	// +usage=The branch to push to
+	branch: *"main" | string
+	secretRef?: {
+		name:       string
+		namespace?: string
+	}
+
+	// +usage=The sha of the pushed commit
+	sha?: string
+	...
+}
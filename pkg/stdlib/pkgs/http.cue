@@ -2,16 +2,47 @@
 	#do:       "do"
 	#provider: "http"
 
-	method: *"GET" | "POST" | "PUT" | "DELETE"
+	method: *"GET" | "POST" | "PUT" | "DELETE" | "PATCH"
 	url:    string
 	request?: {
-		body: string
+		body?: string
 		header: [string]:  string
 		trailer: [string]: string
 	}
+	// +usage=The timeout of the request, such as "10s"
+	timeout?: string
 	tls_config?: secret: string
+	// +usage=Authenticate the request with the credentials in the secret, the secret is referred by "name" or "namespace/name"
+	auth?: {
+		bearer?: {
+			secret: string
+			key:    *"token" | string
+		}
+		basic?: {
+			secret:      string
+			usernameKey: *"username" | string
+			passwordKey: *"password" | string
+		}
+	}
+	// +usage=Retry the request with exponential backoff if it fails to connect or responds the status codes, at most 5 attempts and 30s of waiting in total
+	retry?: {
+		attempts:    *3 | int
+		statusCodes: *[429, 502, 503, 504] | [...int]
+		backoff:     *"1s" | string
+		maxBackoff:  *"30s" | string
+	}
+	// +usage=The assertions on the response, the step fails if they are not satisfied
+	expect?: {
+		// +usage=The expected status code or the list of them
+		statusCode?: int | [...int]
+		// +usage=The regular expression that the body must match
+		body?: string
+	}
 	response: {
-		body: string
+		body:        string
+		statusCode?: int
+		// +usage=The decoded body if the response is in json format
+		json?: _
 		header?: [string]: [...string]
 		trailer?: [string]: [...string]
 	}
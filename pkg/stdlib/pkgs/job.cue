@@ -0,0 +1,41 @@
+#Run: {
+	#do:       "run"
+	#provider: "job"
+
+	// +usage=The cluster to run the job, the local cluster by default
+	cluster: *"" | string
+	// +usage=The name of the job, the job is recreated if the spec is changed
+	name: string
+	// +usage=The namespace of the job, the namespace of the application by default
+	namespace?: string
+	image:      string
+	command?: [...string]
+	args?: [...string]
+	env?: [...{
+		name:   string
+		value?: string
+		valueFrom?: secretKeyRef?: {
+			name: string
+			key:  string
+		}
+	}]
+	// +usage=The secrets to load as the environment variables
+	secrets?: [...string]
+	serviceAccountName?: string
+	// +usage=The number of retries before the job is failed
+	backoffLimit: *0 | int
+	// +usage=The duration in seconds the job may run before it is failed
+	activeDeadlineSeconds?: int
+	// +usage=The job is deleted after it has finished for the seconds
+	ttlSecondsAfterFinished: *600 | int
+	// +usage=The number of lines of the logs to collect
+	logTailLines: *100 | int
+
+	// +usage=The status of the job, running, succeeded or failed
+	status?: "running" | "succeeded" | "failed"
+	// +usage=The exit code of the container of the last attempt
+	exitCode?: int
+	// +usage=The tail lines of the logs of the last attempt
+	logs?: string
+	...
+}
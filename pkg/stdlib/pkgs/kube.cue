@@ -52,3 +52,35 @@
 	}
 	...
 }
+
+#WaitFor: {
+	#do:       "wait-for"
+	#provider: "kube"
+	cluster:   *"" | string
+	value: {
+		apiVersion: string
+		kind:       string
+		metadata: {
+			name:      string
+			namespace: *"default" | string
+			...
+		}
+		...
+	}
+	// +usage=The JSONPath of the observed field, such as {.status.phase}
+	jsonPath?: string
+	// +usage=The expected value of the JSONPath, any non-empty value is expected if not set
+	expected?: string
+	// +usage=The CUE expression of the condition, the object is referred as object, such as object.status.readyReplicas == object.spec.replicas
+	predicate?: string
+	// +usage=The standard condition in status.conditions of the object
+	condition?: {
+		type:   string
+		status: *"True" | string
+	}
+	// +usage=The step fails if the condition is not satisfied within the timeout
+	timeout:    *"5m" | string
+	observed?:  string
+	satisfied?: bool
+	...
+}
@@ -0,0 +1,52 @@
+#Query: {
+	#do:       "query"
+	#provider: "metrics"
+
+	// +usage=The address of the prometheus compatible server, such as http://prometheus-server.o11y-system:9090
+	endpoint: string
+	// +usage=The PromQL query, it must return at most one series
+	query: string
+	// +usage=The time window of the range query until now
+	window: *"5m" | string
+	// +usage=The interval between the samples
+	step: *"1m" | string
+	header?: [string]: string
+
+	result?: {
+		values: [...number]
+		series: [...{
+			metric: [string]: string
+			values: [...number]
+		}]
+	}
+	...
+}
+
+#Analyze: {
+	#do:       "analyze"
+	#provider: "metrics"
+
+	endpoint: string
+	query:    string
+	// +usage=The time window of the range query until now, it starts after the analysis starts
+	window: *"5m" | string
+	step:   *"1m" | string
+	header?: [string]: string
+	// +usage=The condition of a succeeded sample, such as ">= 0.99"
+	successCondition?: string
+	// +usage=The condition of a failed sample, such as "> 0.05"
+	failureCondition?: string
+	// +usage=The number of the latest samples to evaluate, the step waits until there are enough samples
+	count: *3 | int
+	// +usage=The max number of the failed samples tolerated
+	failureLimit: *0 | int
+
+	result?: {
+		values: [...number]
+		successCount: int
+		failureCount: int
+		verdict:      "Succeeded" | "Failed" | "Inconclusive"
+		message:      string
+	}
+	...
+}
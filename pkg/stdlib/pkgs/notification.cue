@@ -0,0 +1,29 @@
+#ApprovalLinks: {
+	#do:       "approval-links"
+	#provider: "notification"
+
+	// +usage=The external address of the apiserver that receives the callbacks
+	callbackURL: string
+	// +usage=The session ID of the step that sends the links, usually context.stepSessionID
+	stepID: string
+	// +usage=The duration the links are valid for, the links never expire if empty
+	expiration: *"24h" | string
+
+	links?: {
+		approve: string
+		reject:  string
+	}
+	...
+}
+
+#Sign: {
+	#do:       "sign"
+	#provider: "notification"
+
+	payload: string
+	key:     string
+
+	// +usage=The hex encoded HMAC-SHA256 signature of the payload
+	signature?: string
+	...
+}
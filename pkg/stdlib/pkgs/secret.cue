@@ -0,0 +1,64 @@
+#Generate: {
+	#do:       "generate"
+	#provider: "secret"
+
+	// +usage=The cluster to apply the secret, the local cluster by default
+	cluster: *"" | string
+	// +usage=The name of the secret
+	name: string
+	// +usage=The namespace of the secret, the namespace of the application by default
+	namespace?: string
+	// +usage=The type of the secret, it's immutable once the secret is created
+	type: *"Opaque" | string
+
+	// +usage=The random strings to generate, such as passwords, the key is the data key in the secret
+	strings?: [string]: {
+		length:        *32 | int
+		lowercase:     *true | bool
+		uppercase:     *true | bool
+		digits:        *true | bool
+		symbols:       *false | bool
+		symbolCharset: *"!#%*+-=?@^_~" | string
+	}
+	// +usage=The key pairs to generate, the private and public keys are stored in <name>.pem and <name>.pub in PEM format
+	keyPairs?: [string]: #Key
+	// +usage=The certificates to generate, the certificate and the private key are stored in <name>.crt and <name>.key in PEM format
+	certificates?: [string]: #Key & {
+		commonName: string
+		organization?: [...string]
+		dnsNames?: [...string]
+		ipAddresses?: [...string]
+		// +usage=The validity duration of the certificate, such as 8760h
+		validity: *"8760h" | string
+		isCA:     *false | bool
+		// +usage=The name of another certificate in the same secret that signs this one, self-signed if neither issuer nor issuerRef is set
+		issuer?: string
+		// +usage=The secret of the CA certificate and key that signs this one, the secret must be in the namespace of the application
+		issuerRef?: {
+			name: string
+			// +usage=The namespace of the CA secret, only the namespace of the application is allowed
+			namespace?: string
+			certKey:    *"tls.crt" | string
+			keyKey:     *"tls.key" | string
+		}
+	}
+
+	result?: {
+		// +usage=The keys in the secret
+		keys: [...string]
+		// +usage=The keys generated in this run, the existing keys are never regenerated
+		generated: [...string]
+		// +usage=The public keys and certificates, which are safe to pass to the other steps
+		public: [string]: string
+	}
+	...
+}
+
+#Key: {
+	algorithm: *"RSA" | "ECDSA"
+	// +usage=The size of the RSA key, 2048 by default
+	bits?: 2048 | 3072 | 4096
+	// +usage=The curve of the ECDSA key, P256 by default
+	curve?: "P256" | "P384" | "P521"
+	...
+}
@@ -0,0 +1,26 @@
+#TeamsMessage: {
+	"@type":     *"MessageCard" | string
+	"@context":  *"https://schema.org/extensions" | string
+	summary?:    string
+	title?:      string
+	text:        string
+	themeColor?: string
+	sections?: [...{
+		activityTitle?:    string
+		activitySubtitle?: string
+		text?:             string
+		facts?: [...{
+			name:  string
+			value: string
+		}]
+		markdown?: bool
+	}]
+	potentialAction?: [...{
+		"@type": *"OpenUri" | string
+		name:    string
+		targets: [...{
+			os:  *"default" | string
+			uri: string
+		}]
+	}]
+}
@@ -22,3 +22,115 @@
 
 	data: {...}
 }
+
+#JSONPath: {
+	#do:       "jsonpath"
+	#provider: "util"
+
+	value: _
+	// +usage=The kubectl style jsonpath, such as {.items[*].metadata.name}
+	path: string
+	// +usage=The result if only one value is matched
+	result?: _
+	// +usage=All the matched values
+	results?: [..._]
+	...
+}
+
+#JMESPath: {
+	#do:       "jmespath"
+	#provider: "util"
+
+	value: _
+	// +usage=The jmespath expression, such as items[?status=='ready'].name
+	expression: string
+	result?:    _
+	...
+}
+
+#Template: {
+	#do:       "template"
+	#provider: "util"
+
+	// +usage=The go template, the functions of sprig are available except env, expandenv and getHostByName
+	template: string
+	data?:    _
+	// +usage=Whether to fail on the missing keys
+	strict:  *false | bool
+	result?: string
+	...
+}
+
+#Encode: {
+	#do:       "encode"
+	#provider: "util"
+
+	input:    string
+	encoding: *"base64" | "base64url" | "hex"
+	output?:  string
+	...
+}
+
+#Decode: {
+	#do:       "decode"
+	#provider: "util"
+
+	input:    string
+	encoding: *"base64" | "base64url" | "hex"
+	output?:  string
+	...
+}
+
+#Hash: {
+	#do:       "hash"
+	#provider: "util"
+
+	input:     string
+	algorithm: *"sha256" | "sha512" | "sha1"
+	// +usage=The hex encoded digest
+	output?: string
+	...
+}
+
+#Marshal: {
+	#do:       "marshal"
+	#provider: "util"
+
+	value:   _
+	format:  *"json" | "yaml" | "toml"
+	output?: string
+	...
+}
+
+#Unmarshal: {
+	#do:       "unmarshal"
+	#provider: "util"
+
+	input:  string
+	format: *"json" | "yaml" | "toml"
+	value?: _
+	...
+}
+
+#CompareVersion: {
+	#do:       "compare-version"
+	#provider: "util"
+
+	version: string
+	target:  string
+	// +usage=-1, 0 or 1 if the version is less than, equal to or greater than the target
+	result?: int
+	...
+}
+
+#BumpVersion: {
+	#do:       "bump-version"
+	#provider: "util"
+
+	version: string
+	bump:    *"patch" | "minor" | "major"
+	// +usage=The prerelease of the bumped version, such as rc.1
+	prerelease?: string
+	result?:     string
+	...
+}
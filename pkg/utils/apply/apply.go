@@ -17,9 +17,12 @@ limitations under the License.
 package apply
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -31,6 +34,7 @@ import (
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/pkg/controller/utils"
@@ -42,6 +46,8 @@ import (
 const (
 	// LabelRenderHash is the label that record the hash value of the rendering resource.
 	LabelRenderHash = "oam.dev/render-hash"
+	// DefaultFieldManager is the default field manager of the server-side apply
+	DefaultFieldManager = "kubevela"
 )
 
 // Applicator applies new state to an object or create it if not exist.
@@ -49,6 +55,7 @@ const (
 // computing a three-way diff merge in client side based on its current state, modified stated,
 // and last-applied-state which is tracked through an specific annotation.
 // If the resource doesn't exist before, Apply will create it.
+// The ServerSideApply option switches the mechanism to the server-side apply.
 type Applicator interface {
 	Apply(context.Context, client.Object, ...ApplyOption) error
 }
@@ -58,6 +65,13 @@ type applyAction struct {
 	skipUpdate       bool
 	updateAnnotation bool
 	dryRun           bool
+
+	serverSideApply bool
+	fieldManager    string
+	forceConflicts  bool
+
+	readOnly bool
+	takeOver bool
 }
 
 // ApplyOption is called before applying state to the object.
@@ -170,6 +184,23 @@ func (a *APIApplicator) Apply(ctx context.Context, desired client.Object, ao ...
 		return nil
 	}
 
+	if applyAct.readOnly {
+		loggingApply("skip read-only object", desired)
+		return nil
+	}
+
+	// the shared resource only mutates the shared-by annotation, so it's patched by the three way diff
+	// to avoid taking the ownership of all the fields of the existing object.
+	if applyAct.serverSideApply && !applyAct.isShared {
+		if !applyAct.dryRun {
+			if err := upgradeClientSideApplyFields(ctx, a.c, existing, applyAct.fieldManagerOrDefault()); err != nil {
+				return errors.Wrapf(err, "cannot upgrade the managed fields of client-side apply")
+			}
+		}
+		loggingApply("server-side applying object", desired)
+		return errors.Wrapf(serverSideApply(ctx, a.c, desired, applyAct), "cannot server-side apply object")
+	}
+
 	loggingApply("patching object", desired)
 	patch, err := a.patcher.patch(existing, desired, applyAct)
 	if err != nil {
@@ -211,11 +242,18 @@ func createOrGetExisting(ctx context.Context, act *applyAction, c client.Client,
 		if err := executeApplyOptions(act, nil, desired, ao); err != nil {
 			return nil, err
 		}
-		if act.updateAnnotation {
+		if act.readOnly {
+			return nil, fmt.Errorf("%s %s/%s is read-only but does not exist", desired.GetObjectKind().GroupVersionKind().Kind, desired.GetNamespace(), desired.GetName())
+		}
+		if act.updateAnnotation && !act.serverSideApply {
 			if err := addLastAppliedConfigAnnotation(desired); err != nil {
 				return nil, err
 			}
 		}
+		if act.serverSideApply && desired.GetName() != "" {
+			loggingApply("server-side applying object", desired)
+			return nil, errors.Wrap(serverSideApply(ctx, c, desired, act), "cannot server-side apply object")
+		}
 		loggingApply("creating object", desired)
 		if act.dryRun {
 			return nil, errors.Wrap(c.Create(ctx, desired, client.DryRunAll), "cannot create object")
@@ -240,6 +278,91 @@ func createOrGetExisting(ctx context.Context, act *applyAction, c client.Client,
 	return existing, nil
 }
 
+// serverSideApply applies the desired object by the server-side apply, the fields that are not in the
+// desired object any more are removed if they are owned by the field manager.
+func serverSideApply(ctx context.Context, c client.Client, desired client.Object, act *applyAction) error {
+	desired.SetManagedFields(nil)
+	desired.SetResourceVersion("")
+	opts := []client.PatchOption{client.FieldOwner(act.fieldManagerOrDefault())}
+	if act.forceConflicts {
+		opts = append(opts, client.ForceOwnership)
+	}
+	if act.dryRun {
+		opts = append(opts, client.DryRunAll)
+	}
+	return c.Patch(ctx, desired, client.Apply, opts...)
+}
+
+func (act *applyAction) fieldManagerOrDefault() string {
+	if act.fieldManager == "" {
+		return DefaultFieldManager
+	}
+	return act.fieldManager
+}
+
+// upgradeClientSideApplyFields hands over the fields applied by the client-side apply to the field manager of the
+// server-side apply, in the same way as csaupgrade of client-go. Otherwise, the fields removed from the desired
+// object are left in the live object after switching to the server-side apply, since they're still owned by the
+// client-side manager. The client-side managers are recognized by the ownership of the last-applied-configuration
+// annotation, which is removed as it's not refreshed by the server-side apply any more.
+func upgradeClientSideApplyFields(ctx context.Context, c client.Client, existing client.Object, fieldManager string) error {
+	annotationPath := fieldpath.MakePathOrDie("metadata", "annotations", oam.AnnotationLastAppliedConfig)
+	_, hasAnnotation := existing.GetAnnotations()[oam.AnnotationLastAppliedConfig]
+	entries := existing.GetManagedFields()
+	owned, apiVersion, upgraded := &fieldpath.Set{}, "", false
+	var kept []metav1.ManagedFieldsEntry
+	for _, entry := range entries {
+		if entry.Subresource != "" || entry.FieldsV1 == nil {
+			kept = append(kept, entry)
+			continue
+		}
+		set := &fieldpath.Set{}
+		if err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return err
+		}
+		switch {
+		case entry.Manager == fieldManager && entry.Operation == metav1.ManagedFieldsOperationApply:
+			owned = owned.Union(set)
+		case entry.Operation == metav1.ManagedFieldsOperationUpdate && set.Has(annotationPath):
+			owned = owned.Union(set)
+			apiVersion = entry.APIVersion
+			upgraded = true
+		default:
+			kept = append(kept, entry)
+		}
+	}
+	if !upgraded && !hasAnnotation {
+		return nil
+	}
+	var patch []map[string]interface{}
+	if upgraded {
+		raw, err := owned.Difference(fieldpath.NewSet(annotationPath)).ToJSON()
+		if err != nil {
+			return err
+		}
+		kept = append(kept, metav1.ManagedFieldsEntry{
+			Manager:    fieldManager,
+			Operation:  metav1.ManagedFieldsOperationApply,
+			APIVersion: apiVersion,
+			Time:       &metav1.Time{Time: time.Now()},
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: raw},
+		})
+		patch = append(patch, map[string]interface{}{"op": "replace", "path": "/metadata/managedFields", "value": kept})
+	}
+	if hasAnnotation {
+		patch = append(patch, map[string]interface{}{"op": "remove", "path": "/metadata/annotations/" + strings.ReplaceAll(oam.AnnotationLastAppliedConfig, "/", "~1")})
+	}
+	// the resource version fails the patch if the object is changed since read
+	patch = append(patch, map[string]interface{}{"op": "replace", "path": "/metadata/resourceVersion", "value": existing.GetResourceVersion()})
+	bs, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	loggingApply("upgrading the managed fields of client-side apply", existing)
+	return c.Patch(ctx, existing, client.RawPatch(types.JSONPatchType, bs))
+}
+
 func executeApplyOptions(act *applyAction, existing, desired client.Object, aos []ApplyOption) error {
 	// if existing is nil, it means the object is going to be created.
 	// ApplyOption function should handle this situation carefully by itself.
@@ -295,11 +418,11 @@ func MustBeControllableBy(u types.UID) ApplyOption {
 // MustBeControlledByApp requires that the new object is controllable by versioned resourcetracker
 func MustBeControlledByApp(app *v1beta1.Application) ApplyOption {
 	return func(act *applyAction, existing, _ client.Object) error {
-		if existing == nil || act.isShared {
+		if existing == nil || act.isShared || act.readOnly {
 			return nil
 		}
 		appKey, controlledBy := GetAppKey(app), GetControlledBy(existing)
-		if controlledBy == "" && !utilfeature.DefaultMutableFeatureGate.Enabled(features.LegacyResourceOwnerValidation) {
+		if controlledBy == "" && !act.takeOver && !utilfeature.DefaultMutableFeatureGate.Enabled(features.LegacyResourceOwnerValidation) {
 			return fmt.Errorf("%s %s/%s exists but not managed by any application now", existing.GetObjectKind().GroupVersionKind().Kind, existing.GetNamespace(), existing.GetName())
 		}
 		if controlledBy != "" && controlledBy != appKey {
@@ -387,6 +510,37 @@ func SharedByApp(app *v1beta1.Application) ApplyOption {
 	}
 }
 
+// ServerSideApply applies the object by the server-side apply with the field manager instead of the client-side
+// three way merge, and the last-applied annotation is not recorded. If force is true, the conflicts with other
+// field managers are resolved by taking the ownership of the conflicting fields, otherwise the apply fails.
+func ServerSideApply(fieldManager string, force bool) ApplyOption {
+	return func(a *applyAction, _, _ client.Object) error {
+		a.serverSideApply = true
+		a.fieldManager = fieldManager
+		a.forceConflicts = force
+		a.updateAnnotation = false
+		return nil
+	}
+}
+
+// ReadOnly skips writing the object, the object must exist.
+// The ApplyOptions checking the owner of the existing object are skipped since the object is never changed.
+func ReadOnly() ApplyOption {
+	return func(a *applyAction, _, _ client.Object) error {
+		a.readOnly = true
+		return nil
+	}
+}
+
+// TakeOver allows to adopt the existing object that is not managed by any application,
+// the owner labels of the object are rewritten by the desired object.
+func TakeOver() ApplyOption {
+	return func(a *applyAction, _, _ client.Object) error {
+		a.takeOver = true
+		return nil
+	}
+}
+
 // DryRunAll executing all validation, etc without persisting the change to storage.
 func DryRunAll() ApplyOption {
 	return func(a *applyAction, existing, _ client.Object) error {
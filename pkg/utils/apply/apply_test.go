@@ -18,6 +18,7 @@ package apply
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -555,6 +556,86 @@ func TestSharedByApp(t *testing.T) {
 	}
 }
 
+func TestServerSideApply(t *testing.T) {
+	r := require.New(t)
+	var patchType types.PatchType
+	var patchOpts *client.PatchOptions
+	cli := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil),
+		MockPatch: func(_ context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			patchType = patch.Type()
+			patchOpts = (&client.PatchOptions{}).ApplyOptions(opts)
+			return nil
+		},
+	}
+	a := NewAPIApplicator(cli)
+	desired := &unstructured.Unstructured{}
+	desired.SetAPIVersion("v1")
+	desired.SetKind("ConfigMap")
+	desired.SetName("example")
+	desired.SetResourceVersion("1")
+
+	r.NoError(a.Apply(ctx, desired, ServerSideApply("", true)))
+	r.Equal(types.ApplyPatchType, patchType)
+	r.Equal(DefaultFieldManager, patchOpts.FieldManager)
+	r.NotNil(patchOpts.Force)
+	r.True(*patchOpts.Force)
+	r.Equal("", desired.GetResourceVersion())
+	r.NotContains(desired.GetAnnotations(), oam.AnnotationLastAppliedConfig)
+
+	r.NoError(a.Apply(ctx, desired, ServerSideApply("example", false), DryRunAll()))
+	r.Equal(types.ApplyPatchType, patchType)
+	r.Equal("example", patchOpts.FieldManager)
+	r.Nil(patchOpts.Force)
+	r.Equal([]string{metav1.DryRunAll}, patchOpts.DryRun)
+
+	cli.MockGet = test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "example"))
+	cli.MockCreate = test.NewMockCreateFn(errFake)
+	patchType = ""
+	r.NoError(a.Apply(ctx, desired, ServerSideApply("", false)))
+	r.Equal(types.ApplyPatchType, patchType)
+}
+
+func TestReadOnlyAndTakeOver(t *testing.T) {
+	r := require.New(t)
+	app := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	patched, created := false, false
+	cli := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil),
+		MockPatch: func(_ context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			patched = true
+			return nil
+		},
+		MockCreate: func(_ context.Context, obj client.Object, opts ...client.CreateOption) error {
+			created = true
+			return nil
+		},
+	}
+	a := NewAPIApplicator(cli)
+	desired := &unstructured.Unstructured{}
+	desired.SetAPIVersion("v1")
+	desired.SetKind("ConfigMap")
+	desired.SetName("example")
+
+	// the existing object not managed by any application is never written
+	r.NoError(a.Apply(ctx, desired.DeepCopy(), ReadOnly(), MustBeControlledByApp(app)))
+	r.False(patched)
+	r.Error(a.Apply(ctx, desired.DeepCopy(), MustBeControlledByApp(app)))
+	cli.MockGet = test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "example"))
+	r.Error(a.Apply(ctx, desired.DeepCopy(), ReadOnly(), MustBeControlledByApp(app)))
+	r.False(created)
+
+	// the existing object is adopted only if it's not managed by other applications
+	ao := MustBeControlledByApp(app)
+	r.NoError(ao(&applyAction{takeOver: true}, &appsv1.Deployment{}, nil))
+	r.Error(ao(&applyAction{takeOver: true}, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Labels: map[string]string{oam.LabelAppName: "other", oam.LabelAppNamespace: "default"},
+	}}, nil))
+	cli.MockGet = test.NewMockGetFn(nil)
+	r.NoError(a.Apply(ctx, desired.DeepCopy(), TakeOver(), MustBeControlledByApp(app)))
+	r.True(patched)
+}
+
 func TestFilterSpecialAnn(t *testing.T) {
 	var cm = &corev1.ConfigMap{}
 	var sc = &corev1.Secret{}
@@ -572,3 +653,78 @@ func TestFilterSpecialAnn(t *testing.T) {
 	dp.Annotations = map[string]string{oam.AnnotationLastAppliedConfig: "xxx"}
 	assert.Equal(t, true, filterRecordForSpecial(dp))
 }
+
+func TestServerSideApplyUpgradeClientSideApply(t *testing.T) {
+	r := require.New(t)
+	existing := &unstructured.Unstructured{}
+	existing.SetAPIVersion("v1")
+	existing.SetKind("ConfigMap")
+	existing.SetName("example")
+	existing.SetResourceVersion("10")
+	existing.SetAnnotations(map[string]string{oam.AnnotationLastAppliedConfig: "{}"})
+	existing.SetManagedFields([]metav1.ManagedFieldsEntry{{
+		Manager:    "vela-core",
+		Operation:  metav1.ManagedFieldsOperationUpdate,
+		APIVersion: "v1",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:removed":{}},"f:metadata":{"f:annotations":{".":{},"f:app.oam.dev/last-applied-configuration":{}}}}`)},
+	}, {
+		Manager:    "kubectl-edit",
+		Operation:  metav1.ManagedFieldsOperationUpdate,
+		APIVersion: "v1",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:edited":{}}}`)},
+	}})
+	var patches []client.Patch
+	cli := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+			existing.DeepCopyInto(obj.(*unstructured.Unstructured))
+			return nil
+		}),
+		MockPatch: func(_ context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			patches = append(patches, patch)
+			return nil
+		},
+	}
+	desired := &unstructured.Unstructured{}
+	desired.SetAPIVersion("v1")
+	desired.SetKind("ConfigMap")
+	desired.SetName("example")
+	r.NoError(NewAPIApplicator(cli).Apply(ctx, desired, ServerSideApply("", false)))
+	r.Len(patches, 2)
+	r.Equal(types.JSONPatchType, patches[0].Type())
+	r.Equal(types.ApplyPatchType, patches[1].Type())
+	data, err := patches[0].Data(nil)
+	r.NoError(err)
+	var ops []struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		Value json.RawMessage `json:"value"`
+	}
+	r.NoError(json.Unmarshal(data, &ops))
+	r.Len(ops, 3)
+	r.Equal("/metadata/managedFields", ops[0].Path)
+	var entries []metav1.ManagedFieldsEntry
+	r.NoError(json.Unmarshal(ops[0].Value, &entries))
+	r.Len(entries, 2)
+	r.Equal("kubectl-edit", entries[0].Manager)
+	r.Equal(DefaultFieldManager, entries[1].Manager)
+	r.Equal(metav1.ManagedFieldsOperationApply, entries[1].Operation)
+	r.Equal(`{"f:data":{"f:removed":{}},"f:metadata":{"f:annotations":{}}}`, string(entries[1].FieldsV1.Raw))
+	r.Equal("remove", ops[1].Op)
+	r.Equal("/metadata/annotations/app.oam.dev~1last-applied-configuration", ops[1].Path)
+	r.Equal("/metadata/resourceVersion", ops[2].Path)
+	r.Equal(`"10"`, string(ops[2].Value))
+
+	// nothing to upgrade after switched to the server-side apply
+	existing.SetAnnotations(nil)
+	existing.SetManagedFields(append(entries[:1:1], metav1.ManagedFieldsEntry{
+		Manager:   DefaultFieldManager,
+		Operation: metav1.ManagedFieldsOperationApply,
+		FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:removed":{}}}`)},
+	}))
+	patches = nil
+	r.NoError(NewAPIApplicator(cli).Apply(ctx, desired, ServerSideApply("", false)))
+	r.Len(patches, 1)
+	r.Equal(types.ApplyPatchType, patches[0].Type())
+}
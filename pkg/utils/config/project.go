@@ -24,7 +24,13 @@ import (
 
 // ProjectMatched will check whether a config secret can be used in a given project
 func ProjectMatched(s *v1.Secret, project string) bool {
-	if s.Labels[types.LabelConfigProject] == "" || s.Labels[types.LabelConfigProject] == project {
+	return LabelsProjectMatched(s.Labels, project)
+}
+
+// LabelsProjectMatched will check whether a config with the labels, such as the config application, can be used
+// in a given project
+func LabelsProjectMatched(labels map[string]string, project string) bool {
+	if labels[types.LabelConfigProject] == "" || labels[types.LabelConfigProject] == project {
 		return true
 	}
 	return false
@@ -95,3 +95,10 @@ func TestMatchProject(t *testing.T) {
 		})
 	}
 }
+
+func TestLabelsProjectMatched(t *testing.T) {
+	assert.True(t, LabelsProjectMatched(nil, "p1"))
+	assert.True(t, LabelsProjectMatched(map[string]string{types.LabelConfigProject: "p1"}, "p1"))
+	assert.False(t, LabelsProjectMatched(map[string]string{types.LabelConfigProject: "p1"}, "p2"))
+	assert.False(t, LabelsProjectMatched(map[string]string{types.LabelConfigProject: "p1"}, ""))
+}
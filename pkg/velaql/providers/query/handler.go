@@ -240,28 +240,11 @@ func (h *provider) CollectLogsInPod(ctx wfContext.Context, v *value.Value, act t
 	if err != nil {
 		errMsg = fmt.Sprintf("failed to get pod:%s", err.Error())
 	}
-	req := clientSet.CoreV1().Pods(namespace).GetLogs(pod, opts)
-	readCloser, err := req.Stream(cliCtx)
+	buffer, err := ReadPodLogs(cliCtx, clientSet, namespace, pod, opts)
 	if err != nil {
-		errMsg = fmt.Sprintf("failed to get stream logs %s", err.Error())
-	}
-	if readCloser != nil && podInst != nil {
-		r := bufio.NewReader(readCloser)
-		buffer := bytes.NewBuffer(nil)
-		var readErr error
-		defer func() {
-			_ = readCloser.Close()
-		}()
-		for {
-			s, err := r.ReadString('\n')
-			buffer.WriteString(s)
-			if err != nil {
-				if !errors.Is(err, io.EOF) {
-					readErr = err
-				}
-				break
-			}
-		}
+		errMsg = err.Error()
+	}
+	if buffer != nil && podInst != nil {
 		toDate := v1.Now()
 		var fromDate v1.Time
 		// nolint
@@ -281,9 +264,6 @@ func (h *provider) CollectLogsInPod(ctx wfContext.Context, v *value.Value, act t
 				"toDate":   toDate,
 			},
 		}
-		if readErr != nil {
-			errMsg = readErr.Error()
-		}
 	}
 	if errMsg != "" {
 		klog.Warningf(errMsg)
@@ -292,6 +272,31 @@ func (h *provider) CollectLogsInPod(ctx wfContext.Context, v *value.Value, act t
 	return v.FillObject(defaultOutputs, "outputs")
 }
 
+// ReadPodLogs reads the logs of the pod until the end. The buffer is nil if the log stream cannot be opened,
+// otherwise it contains the logs read even if the reading is interrupted by an error.
+func ReadPodLogs(ctx context.Context, clientSet kubernetes.Interface, namespace, pod string, opts *corev1.PodLogOptions) (*bytes.Buffer, error) {
+	req := clientSet.CoreV1().Pods(namespace).GetLogs(pod, opts)
+	readCloser, err := req.Stream(ctx)
+	if err != nil {
+		return nil, errors.Errorf("failed to get stream logs %s", err.Error())
+	}
+	defer func() {
+		_ = readCloser.Close()
+	}()
+	r := bufio.NewReader(readCloser)
+	buffer := bytes.NewBuffer(nil)
+	for {
+		s, err := r.ReadString('\n')
+		buffer.WriteString(s)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				return buffer, err
+			}
+			return buffer, nil
+		}
+	}
+}
+
 // Install register handlers to provider discover.
 func Install(p providers.Providers, cli client.Client, cfg *rest.Config) {
 	prd := &provider{
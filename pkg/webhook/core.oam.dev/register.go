@@ -33,6 +33,7 @@ func Register(mgr manager.Manager, args controller.Args) {
 	switch args.OAMSpecVer {
 	case "all":
 		application.RegisterValidatingHandler(mgr, args)
+		application.RegisterApprovalMutatingHandler(mgr)
 		componentdefinition.RegisterMutatingHandler(mgr, args)
 		componentdefinition.RegisterValidatingHandler(mgr, args)
 		traitdefinition.RegisterValidatingHandler(mgr, args)
@@ -42,12 +43,14 @@ func Register(mgr manager.Manager, args controller.Args) {
 		component.RegisterValidatingHandler(mgr)
 	case "minimal":
 		application.RegisterValidatingHandler(mgr, args)
+		application.RegisterApprovalMutatingHandler(mgr)
 		componentdefinition.RegisterMutatingHandler(mgr, args)
 		componentdefinition.RegisterValidatingHandler(mgr, args)
 		traitdefinition.RegisterValidatingHandler(mgr, args)
 	case "v0.3":
 		application.RegisterValidatingHandler(mgr, args)
 		application.RegisterMutatingHandler(mgr)
+		application.RegisterApprovalMutatingHandler(mgr)
 		componentdefinition.RegisterMutatingHandler(mgr, args)
 		componentdefinition.RegisterValidatingHandler(mgr, args)
 		traitdefinition.RegisterValidatingHandler(mgr, args)
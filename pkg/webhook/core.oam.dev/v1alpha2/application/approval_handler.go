@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/strings/slices"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/utils"
+	wfTypes "github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+// ApprovalMutatingHandler records the identity of the request in the approvals of the workflow steps, so the
+// approvers can't be forged by the clients updating the application status
+type ApprovalMutatingHandler struct {
+	trustedUsers []string
+	recorders    []string
+	Decoder      *admission.Decoder
+}
+
+var _ admission.Handler = &ApprovalMutatingHandler{}
+
+// Handle mutate the approvals in application status
+func (h *ApprovalMutatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.SubResource != "status" || slices.Contains(h.trustedUsers, req.UserInfo.Username) {
+		return admission.Patched("")
+	}
+
+	app := &v1beta1.Application{}
+	if err := h.Decoder.Decode(req, app); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	old := &v1beta1.Application{}
+	if len(req.OldObject.Raw) > 0 {
+		if err := h.Decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+	recorder := slices.Contains(h.recorders, req.UserInfo.Username)
+	changed, err := recordApprovers(app, old, req.UserInfo.Username, req.UserInfo.Groups, recorder)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+	if !changed {
+		return admission.Patched("")
+	}
+	klog.Infof("[ApprovalMutatingHandler] Recording approvals of %s in Application %s/%s", req.UserInfo.Username, app.GetNamespace(), app.GetName())
+
+	bs, err := json.Marshal(app)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.AdmissionRequest.Object.Raw, bs)
+}
+
+// recordApprovers only allows appending approvals to the steps. The approvals recorders are trusted to append the
+// approvals on behalf of others, the other users can only append their own approvals, which are stamped with the
+// groups of the request. It returns true if the approvals are changed.
+func recordApprovers(app, old *v1beta1.Application, username string, groups []string, recorder bool) (bool, error) {
+	if app.Status.Workflow == nil {
+		return false, nil
+	}
+	oldApprovals := map[string][]common.WorkflowStepApproval{}
+	if old.Status.Workflow != nil {
+		for _, step := range old.Status.Workflow.Steps {
+			oldApprovals[step.ID] = step.Approvals
+			for _, sub := range step.SubStepsStatus {
+				oldApprovals[sub.ID] = sub.Approvals
+			}
+		}
+	}
+	changed := false
+	record := func(status *common.StepStatus) error {
+		recorded := oldApprovals[status.ID]
+		if len(status.Approvals) < len(recorded) {
+			return fmt.Errorf("the approvals of step %s can't be removed", status.Name)
+		}
+		for i := range status.Approvals {
+			approval := &status.Approvals[i]
+			if i < len(recorded) {
+				if !equality.Semantic.DeepEqual(*approval, recorded[i]) {
+					return fmt.Errorf("the recorded approvals of step %s can't be changed", status.Name)
+				}
+				continue
+			}
+			if recorder {
+				continue
+			}
+			if approval.Approver != username {
+				return fmt.Errorf("%s can't record the approval of %s in step %s, only the approval recorders can record the approvals on behalf of others", username, approval.Approver, status.Name)
+			}
+			approval.Groups = groups
+			approval.Time = metav1.Now()
+			changed = true
+		}
+		return nil
+	}
+	steps := app.Status.Workflow.Steps
+	for i := range steps {
+		if err := record(&steps[i].StepStatus); err != nil {
+			return false, err
+		}
+		for j := range steps[i].SubStepsStatus {
+			if err := record(&steps[i].SubStepsStatus[j].StepStatus); err != nil {
+				return false, err
+			}
+		}
+	}
+	return changed, nil
+}
+
+var _ admission.DecoderInjector = &ApprovalMutatingHandler{}
+
+// InjectDecoder .
+func (h *ApprovalMutatingHandler) InjectDecoder(d *admission.Decoder) error {
+	h.Decoder = d
+	return nil
+}
+
+// RegisterApprovalMutatingHandler will register the approval mutation handler of application status to the webhook
+func RegisterApprovalMutatingHandler(mgr manager.Manager) {
+	server := mgr.GetWebhookServer()
+	handler := &ApprovalMutatingHandler{}
+	if userInfo := utils.GetUserInfoFromConfig(mgr.GetConfig()); userInfo != nil {
+		handler.trustedUsers = append(handler.trustedUsers, userInfo.Username)
+	}
+	for _, user := range strings.Split(wfTypes.ApprovalRecorders, ",") {
+		if user = strings.TrimSpace(user); user != "" {
+			handler.recorders = append(handler.recorders, user)
+		}
+	}
+	klog.Infof("[ApprovalMutatingHandler] trusted approval recorders: %v", handler.recorders)
+	server.Register("/mutating-core-oam-dev-v1beta1-applications-status", &webhook.Admission{Handler: handler})
+}
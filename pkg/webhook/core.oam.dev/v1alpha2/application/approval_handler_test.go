@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+)
+
+func TestApprovalMutatingHandler(t *testing.T) {
+	r := require.New(t)
+	s := runtime.NewScheme()
+	r.NoError(v1beta1.AddToScheme(s))
+	d, err := admission.NewDecoder(s)
+	r.NoError(err)
+	h := &ApprovalMutatingHandler{trustedUsers: []string{types.VelaCoreName}, recorders: []string{"apiserver"}}
+	r.NoError(h.InjectDecoder(d))
+
+	recorded := common.WorkflowStepApproval{Approver: "alice", Action: common.WorkflowStepApprovalApprove, Time: metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))}
+	newApp := func(approvals ...common.WorkflowStepApproval) []byte {
+		app := &v1beta1.Application{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1beta1.SchemeGroupVersion.String(), Kind: v1beta1.ApplicationKind},
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Status: common.AppStatus{Workflow: &common.WorkflowStatus{Steps: []common.WorkflowStepStatus{{
+				StepStatus: common.StepStatus{ID: "s1", Name: "approve", Type: "approval", Approvals: approvals},
+			}}}},
+		}
+		bs, err := json.Marshal(app)
+		r.NoError(err)
+		return bs
+	}
+	handle := func(username string, subResource string, obj, old []byte) (*v1beta1.Application, admission.Response) {
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation:   admissionv1.Update,
+			Resource:    metav1.GroupVersionResource{Group: v1beta1.Group, Version: v1beta1.Version, Resource: "applications"},
+			SubResource: subResource,
+			UserInfo:    authv1.UserInfo{Username: username, Groups: []string{"sre"}},
+			Object:      runtime.RawExtension{Raw: obj},
+			OldObject:   runtime.RawExtension{Raw: old},
+		}}
+		resp := h.Handle(context.Background(), req)
+		if !resp.Allowed {
+			return nil, resp
+		}
+		patched := obj
+		if len(resp.Patches) > 0 {
+			patch, err := json.Marshal(resp.Patches)
+			r.NoError(err)
+			decoded, err := jsonpatch.DecodePatch(patch)
+			r.NoError(err)
+			patched, err = decoded.Apply(obj)
+			r.NoError(err)
+		}
+		app := &v1beta1.Application{}
+		r.NoError(json.Unmarshal(patched, app))
+		return app, resp
+	}
+
+	// the own approval is stamped with the groups of the request
+	own := common.WorkflowStepApproval{Approver: "bob", Groups: []string{"admin"}, Action: common.WorkflowStepApprovalApprove}
+	app, resp := handle("bob", "status", newApp(recorded, own), newApp(recorded))
+	r.True(resp.Allowed)
+	approvals := app.Status.Workflow.Steps[0].Approvals
+	r.Len(approvals, 2)
+	r.Equal("alice", approvals[0].Approver)
+	r.Equal("bob", approvals[1].Approver)
+	r.Equal([]string{"sre"}, approvals[1].Groups)
+	r.False(approvals[1].Time.IsZero())
+
+	// the approvals on behalf of others are rejected unless the requester is a recorder
+	forged := common.WorkflowStepApproval{Approver: "admin", Groups: []string{"admin"}, Action: common.WorkflowStepApprovalApprove, Time: metav1.Now()}
+	_, resp = handle("bob", "status", newApp(recorded, forged), newApp(recorded))
+	r.False(resp.Allowed)
+	_, resp = handle("apiserver", "status", newApp(recorded, forged), newApp(recorded))
+	r.True(resp.Allowed)
+	r.Empty(resp.Patches)
+
+	// the recorded approvals can't be changed or removed, even by the recorders
+	tampered := recorded
+	tampered.Approver = "admin"
+	_, resp = handle("bob", "status", newApp(tampered), newApp(recorded))
+	r.False(resp.Allowed)
+	_, resp = handle("apiserver", "status", newApp(), newApp(recorded))
+	r.False(resp.Allowed)
+
+	// the trusted users and the other requests are not changed
+	_, resp = handle(types.VelaCoreName, "status", newApp(), newApp(recorded))
+	r.True(resp.Allowed)
+	r.Empty(resp.Patches)
+	_, resp = handle("bob", "", newApp(recorded, forged), newApp(recorded))
+	r.True(resp.Allowed)
+	r.Empty(resp.Patches)
+}
@@ -21,15 +21,20 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/robfig/cron/v3"
 	appsv1 "k8s.io/api/apps/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/pkg/appfile"
 	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/oam/util"
+	wfStep "github.com/oam-dev/kubevela/pkg/workflow/step"
+	wfTypes "github.com/oam-dev/kubevela/pkg/workflow/types"
 )
 
 // ValidateWorkflow validates the Application workflow
@@ -37,7 +42,12 @@ func (h *ValidatingHandler) ValidateWorkflow(ctx context.Context, app *v1beta1.A
 	var errs field.ErrorList
 	if app.Spec.Workflow != nil {
 		stepName := make(map[string]interface{})
-		for _, step := range app.Spec.Workflow.Steps {
+		var steps []v1beta1.WorkflowStep
+		steps = append(steps, app.Spec.Workflow.Steps...)
+		steps = append(steps, app.Spec.Workflow.OnSuccess...)
+		steps = append(steps, app.Spec.Workflow.OnFailure...)
+		steps = append(steps, app.Spec.Workflow.Finally...)
+		for _, step := range steps {
 			if _, ok := stepName[step.Name]; ok {
 				errs = append(errs, field.Invalid(field.NewPath("spec", "workflow", "steps"), step.Name, "duplicated step name"))
 			}
@@ -45,6 +55,12 @@ func (h *ValidatingHandler) ValidateWorkflow(ctx context.Context, app *v1beta1.A
 			if step.Timeout != "" {
 				errs = append(errs, h.ValidateTimeout(step.Name, step.Timeout)...)
 			}
+			if step.Retry != nil {
+				errs = append(errs, h.ValidateRetry(step.Name, step.Retry)...)
+			}
+			if step.Type == wfTypes.WorkflowStepTypeSubWorkflow {
+				errs = append(errs, h.ValidateSubWorkflow(ctx, app.Namespace, step, steps)...)
+			}
 			for _, sub := range step.SubSteps {
 				if _, ok := stepName[sub.Name]; ok {
 					errs = append(errs, field.Invalid(field.NewPath("spec", "workflow", "steps", "subSteps"), sub.Name, "duplicated step name"))
@@ -53,18 +69,141 @@ func (h *ValidatingHandler) ValidateWorkflow(ctx context.Context, app *v1beta1.A
 				if step.Timeout != "" {
 					errs = append(errs, h.ValidateTimeout(step.Name, step.Timeout)...)
 				}
+				if sub.Retry != nil {
+					errs = append(errs, h.ValidateRetry(sub.Name, sub.Retry)...)
+				}
+			}
+		}
+		errs = append(errs, validateForeachGeneratedNames(steps)...)
+		for _, step := range steps[len(app.Spec.Workflow.Steps):] {
+			if step.Type == wfTypes.WorkflowStepTypeSubWorkflow {
+				errs = append(errs, field.Invalid(field.NewPath("spec", "workflow"), step.Name, "sub-workflow step can only be used in steps"))
+			}
+		}
+		if app.Spec.Workflow.Schedule != nil {
+			errs = append(errs, h.ValidateSchedule(app.Spec.Workflow.Schedule)...)
+		}
+	}
+	return errs
+}
+
+// validateForeachGeneratedNames rejects the names of the steps and outputs that collide with the ones generated by
+// the foreach steps at runtime
+func validateForeachGeneratedNames(steps []v1beta1.WorkflowStep) field.ErrorList {
+	var errs field.ErrorList
+	var stepNames, outputNames []string
+	for _, step := range steps {
+		stepNames = append(stepNames, step.Name)
+		for _, output := range step.Outputs {
+			outputNames = append(outputNames, output.Name)
+		}
+		for _, sub := range step.SubSteps {
+			stepNames = append(stepNames, sub.Name)
+			for _, output := range sub.Outputs {
+				outputNames = append(outputNames, output.Name)
+			}
+		}
+	}
+	prefixes := map[string]string{}
+	for _, step := range steps {
+		if step.Type != wfTypes.WorkflowStepTypeForeach || len(step.SubSteps) != 1 {
+			continue
+		}
+		template := step.SubSteps[0]
+		prefix := step.Name + "-" + template.Name
+		if other, ok := prefixes[prefix]; ok {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "workflow", "steps"), step.Name, fmt.Sprintf("the steps generated by foreach collide with the ones generated by foreach %s", other)))
+		}
+		prefixes[prefix] = step.Name
+		for _, name := range stepNames {
+			if wfTypes.IsForeachGeneratedName(step.Name, template.Name, name) {
+				errs = append(errs, field.Invalid(field.NewPath("spec", "workflow", "steps"), name, fmt.Sprintf("step name collides with the steps generated by foreach %s", step.Name)))
+			}
+		}
+		for _, output := range template.Outputs {
+			for _, name := range outputNames {
+				if wfTypes.IsForeachGeneratedName(step.Name, output.Name, name) {
+					errs = append(errs, field.Invalid(field.NewPath("spec", "workflow", "steps", "outputs"), name, fmt.Sprintf("output name collides with the outputs generated by foreach %s", step.Name)))
+				}
 			}
 		}
 	}
 	return errs
 }
 
+// ValidateSubWorkflow validates the parameters of the sub-workflow step against the referred workflow, and the names
+// of the expanded steps against the other steps
+func (h *ValidatingHandler) ValidateSubWorkflow(ctx context.Context, namespace string, step v1beta1.WorkflowStep, steps []v1beta1.WorkflowStep) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec", "workflow", "steps", "properties")
+	spec, err := wfStep.ParseSubWorkflowStepSpec(step)
+	if err != nil {
+		return append(errs, field.Invalid(path, step.Name, err.Error()))
+	}
+	wf := &v1alpha1.Workflow{}
+	if err := h.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: spec.Ref}, wf); err != nil {
+		if apierrors.IsNotFound(err) {
+			return append(errs, field.Invalid(path.Child("ref"), step.Name, fmt.Sprintf("workflow %s not found", spec.Ref)))
+		}
+		return append(errs, field.Invalid(path.Child("ref"), step.Name, err.Error()))
+	}
+	group, err := wfStep.ExpandSubWorkflow(step, wf, spec.Parameters)
+	if err != nil {
+		return append(errs, field.Invalid(path.Child("parameters"), step.Name, err.Error()))
+	}
+	if err := wfStep.ValidateExpandedStepNames(group, steps); err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "workflow", "steps"), step.Name, err.Error()))
+	}
+	return errs
+}
+
+// ValidateSchedule validates the schedule of workflow
+func (h *ValidatingHandler) ValidateSchedule(schedule *v1beta1.WorkflowSchedule) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec", "workflow", "schedule")
+	if _, err := cron.ParseStandard(schedule.Cron); err != nil {
+		errs = append(errs, field.Invalid(path.Child("cron"), schedule.Cron, fmt.Sprintf("invalid cron schedule: %s", err.Error())))
+	}
+	if schedule.TimeZone != "" {
+		if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+			errs = append(errs, field.Invalid(path.Child("timeZone"), schedule.TimeZone, "invalid time zone"))
+		}
+	}
+	if schedule.HistoryLimit < 0 {
+		errs = append(errs, field.Invalid(path.Child("historyLimit"), schedule.HistoryLimit, "invalid history limit, it must not be negative"))
+	}
+	return errs
+}
+
 // ValidateTimeout validates the timeout of steps
 func (h *ValidatingHandler) ValidateTimeout(name, timeout string) field.ErrorList {
 	var errs field.ErrorList
-	_, err := time.ParseDuration(timeout)
+	_, err := wfTypes.GetStepDeadline(timeout, time.Now())
 	if err != nil {
-		errs = append(errs, field.Invalid(field.NewPath("spec", "workflow", "steps", "timeout"), name, "invalid timeout, please use the format of timeout like 1s, 1m, 1h or 1d"))
+		errs = append(errs, field.Invalid(field.NewPath("spec", "workflow", "steps", "timeout"), name, "invalid timeout, please use the format of timeout like 1s, 1m, 1h or 1d, or an absolute time in RFC3339 format"))
+	}
+	return errs
+}
+
+// ValidateRetry validates the retry policy of steps
+func (h *ValidatingHandler) ValidateRetry(name string, retry *common.WorkflowStepRetry) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec", "workflow", "steps", "retry")
+	if retry.Attempts < 0 {
+		errs = append(errs, field.Invalid(path.Child("attempts"), name, "invalid attempts, it must not be negative"))
+	}
+	if retry.Backoff != "" && retry.Backoff != common.WorkflowStepBackoffFixed && retry.Backoff != common.WorkflowStepBackoffExponential {
+		errs = append(errs, field.Invalid(path.Child("backoff"), name, "invalid backoff, please use fixed or exponential"))
+	}
+	if retry.BaseDelay != "" {
+		if _, err := time.ParseDuration(retry.BaseDelay); err != nil {
+			errs = append(errs, field.Invalid(path.Child("baseDelay"), name, "invalid baseDelay, please use the format of delay like 1s, 1m or 1h"))
+		}
+	}
+	if retry.MaxDelay != "" {
+		if _, err := time.ParseDuration(retry.MaxDelay); err != nil {
+			errs = append(errs, field.Invalid(path.Child("maxDelay"), name, "invalid maxDelay, please use the format of delay like 1s, 1m or 1h"))
+		}
 	}
 	return errs
 }
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	wfTypes "github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+func TestValidateForeachGeneratedNames(t *testing.T) {
+	r := require.New(t)
+	foreach := func(name, template string) v1beta1.WorkflowStep {
+		return v1beta1.WorkflowStep{
+			Name:     name,
+			Type:     wfTypes.WorkflowStepTypeForeach,
+			SubSteps: []common.WorkflowSubStep{{Name: template, Type: "deploy", Outputs: common.StepOutputs{{Name: "result"}}}},
+		}
+	}
+	r.Empty(validateForeachGeneratedNames([]v1beta1.WorkflowStep{
+		foreach("deploy-each", "region"),
+		{Name: "deploy-each-region", Type: "suspend"},
+		{Name: "deploy-each-region-x", Type: "suspend"},
+	}))
+
+	errs := validateForeachGeneratedNames([]v1beta1.WorkflowStep{
+		foreach("deploy-each", "region"),
+		{Name: "deploy-each-region-0", Type: "suspend"},
+	})
+	r.Len(errs, 1)
+	r.Contains(errs[0].Error(), "step name collides with the steps generated by foreach deploy-each")
+
+	errs = validateForeachGeneratedNames([]v1beta1.WorkflowStep{
+		foreach("deploy", "each-region"),
+		foreach("deploy-each", "region"),
+	})
+	r.Len(errs, 1)
+	r.Contains(errs[0].Error(), "collide with the ones generated by foreach deploy")
+
+	errs = validateForeachGeneratedNames([]v1beta1.WorkflowStep{
+		foreach("deploy-each", "region"),
+		{Name: "notify", Type: "notification", Outputs: common.StepOutputs{{Name: "deploy-each-result-1"}}},
+	})
+	r.Len(errs, 1)
+	r.Contains(errs[0].Error(), "output name collides with the outputs generated by foreach deploy-each")
+}
+
+func TestValidateWorkflowStepCache(t *testing.T) {
+	r := require.New(t)
+	h := &ValidatingHandler{}
+	app := &v1beta1.Application{Spec: v1beta1.ApplicationSpec{Workflow: &v1beta1.Workflow{Steps: []v1beta1.WorkflowStep{
+		{Name: "migrate", Type: "run-job", Cache: true},
+		{Name: "deploy", Type: "deploy", Cache: true},
+		{Name: "group", Type: wfTypes.WorkflowStepTypeStepGroup, SubSteps: []common.WorkflowSubStep{
+			{Name: "notify", Type: "notification", Cache: true},
+			{Name: "apply", Type: wfTypes.WorkflowStepTypeApplyComponent, Cache: true},
+		}},
+	}}}}
+	r.Len(h.ValidateWorkflow(context.Background(), app), 0)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package callback
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	wfTypes "github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+const (
+	// KeySecretName is the name of the secret in the vela system namespace that stores the key to sign the callbacks
+	KeySecretName = "vela-workflow-callback-key"
+	// KeySecretDataKey is the key of the signing key in the secret
+	KeySecretDataKey = "key"
+	// Path is the path of the callback endpoint in the apiserver, the token is appended to it
+	Path = "/api/v1/webhook/workflow-callbacks/"
+	// Approver is the approver recorded for the callbacks, the approval steps accept it only if it's listed in the
+	// approvers explicitly
+	Approver = wfTypes.ApproverWorkflowCallback
+)
+
+// Action is the action of the callback
+type Action string
+
+const (
+	// ActionApprove resumes the suspended workflow
+	ActionApprove Action = "approve"
+	// ActionReject rejects the approval steps or terminates the suspended workflow
+	ActionReject Action = "reject"
+)
+
+var (
+	// ErrInvalidToken means the token is malformed or the signature doesn't match
+	ErrInvalidToken = errors.New("invalid callback token")
+	// ErrExpired means the token is expired
+	ErrExpired = errors.New("callback token is expired")
+	// ErrOutdated means the workflow is restarted after the token is issued
+	ErrOutdated = errors.New("callback is outdated, the workflow has been restarted")
+)
+
+// Claims are the signed contents of the callback token. The StepID is the session ID of the step that sends the
+// callback, it binds the callback to the current execution of the workflow. The Nonce identifies the token, the
+// receivers of the callbacks record the consumed nonces so that every token is used only once.
+type Claims struct {
+	Namespace string `json:"ns"`
+	Name      string `json:"app"`
+	StepID    string `json:"step"`
+	Action    Action `json:"act"`
+	ExpiresAt int64  `json:"exp"`
+	Nonce     string `json:"nonce"`
+}
+
+// Sign signs the claims into a token
+func Sign(key []byte, claims Claims) (string, error) {
+	if claims.Nonce == "" {
+		nonce := make([]byte, 8)
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		claims.Nonce = hex.EncodeToString(nonce)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(mac(key, []byte(encoded))), nil
+}
+
+// Verify verifies the signature and the expiration of the token, and returns the claims
+func Verify(key []byte, token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, mac(key, []byte(parts[0]))) {
+		return nil, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	claims := &Claims{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.ExpiresAt > 0 && now.Unix() > claims.ExpiresAt {
+		return nil, ErrExpired
+	}
+	return claims, nil
+}
+
+// SignPayload returns the hex encoded HMAC-SHA256 signature of the payload
+func SignPayload(key []byte, payload []byte) string {
+	return hex.EncodeToString(mac(key, payload))
+}
+
+func mac(key []byte, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// URL returns the callback url of the token, the baseURL is the external address of the apiserver
+func URL(baseURL, token string) string {
+	return strings.TrimSuffix(baseURL, "/") + Path + token
+}
+
+// GetKey reads the signing key from the secret
+func GetKey(ctx context.Context, cli client.Client) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: types.DefaultKubeVelaNS, Name: KeySecretName}, secret); err != nil {
+		return nil, err
+	}
+	key := secret.Data[KeySecretDataKey]
+	if len(key) == 0 {
+		return nil, fmt.Errorf("the key of secret %s is empty", KeySecretName)
+	}
+	return key, nil
+}
+
+// GetOrCreateKey reads the signing key from the secret, the secret is created with a random key if not exists
+func GetOrCreateKey(ctx context.Context, cli client.Client) ([]byte, error) {
+	key, err := GetKey(ctx, cli)
+	if err == nil || !kerrors.IsNotFound(err) {
+		return key, err
+	}
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: types.DefaultKubeVelaNS, Name: KeySecretName},
+		Data:       map[string][]byte{KeySecretDataKey: key},
+	}
+	if err := cli.Create(ctx, secret); err != nil {
+		if kerrors.IsAlreadyExists(err) {
+			return GetKey(ctx, cli)
+		}
+		return nil, errors.Wrapf(err, "failed to create secret %s", KeySecretName)
+	}
+	return key, nil
+}
+
+// CheckStep checks whether the step that sends the callback belongs to the current execution of the workflow
+func CheckStep(app *v1beta1.Application, claims *Claims) error {
+	if app.Namespace != claims.Namespace || app.Name != claims.Name {
+		return ErrInvalidToken
+	}
+	if app.Status.Workflow == nil {
+		return ErrOutdated
+	}
+	for _, step := range app.Status.Workflow.Steps {
+		if step.ID == claims.StepID {
+			return nil
+		}
+		for _, sub := range step.SubStepsStatus {
+			if sub.ID == claims.StepID {
+				return nil
+			}
+		}
+	}
+	return ErrOutdated
+}
+
+// IsSuspended checks whether the workflow of the application is suspended
+func IsSuspended(app *v1beta1.Application) bool {
+	return app.Status.Workflow != nil && app.Status.Workflow.Suspend && !app.Status.Workflow.Terminated &&
+		app.Status.Phase != common.ApplicationRunning
+}
+
+// Expiration returns the expiration time of the duration since now, zero duration never expires
+func Expiration(duration string, now time.Time) (int64, error) {
+	if duration == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid expiration %s", duration)
+	}
+	if d <= 0 {
+		return 0, nil
+	}
+	return now.Add(d).Unix(), nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package callback
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	r := require.New(t)
+	key := []byte("key")
+	now := time.Now()
+	claims := Claims{Namespace: "default", Name: "app", StepID: "step", Action: ActionApprove, ExpiresAt: now.Add(time.Hour).Unix()}
+	token, err := Sign(key, claims)
+	r.NoError(err)
+	verified, err := Verify(key, token, now)
+	r.NoError(err)
+	r.NotEmpty(verified.Nonce)
+	verified.Nonce = ""
+	r.Equal(claims, *verified)
+
+	another, err := Sign(key, claims)
+	r.NoError(err)
+	r.NotEqual(token, another)
+
+	_, err = Verify([]byte("other"), token, now)
+	r.Equal(ErrInvalidToken, err)
+	_, err = Verify(key, "a"+token, now)
+	r.Equal(ErrInvalidToken, err)
+	_, err = Verify(key, strings.Split(token, ".")[0], now)
+	r.Equal(ErrInvalidToken, err)
+	_, err = Verify(key, token, now.Add(2*time.Hour))
+	r.Equal(ErrExpired, err)
+
+	claims.ExpiresAt = 0
+	token, err = Sign(key, claims)
+	r.NoError(err)
+	_, err = Verify(key, token, now.Add(1000*time.Hour))
+	r.NoError(err)
+}
+
+func TestGetOrCreateKey(t *testing.T) {
+	r := require.New(t)
+	cli := fake.NewClientBuilder().Build()
+	key, err := GetOrCreateKey(context.Background(), cli)
+	r.NoError(err)
+	r.Len(key, 32)
+	again, err := GetOrCreateKey(context.Background(), cli)
+	r.NoError(err)
+	r.Equal(key, again)
+}
+
+func TestCheckStep(t *testing.T) {
+	r := require.New(t)
+	app := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	claims := &Claims{Namespace: "default", Name: "app", StepID: "sub"}
+	r.Equal(ErrOutdated, CheckStep(app, claims))
+	app.Status.Workflow = &common.WorkflowStatus{Steps: []common.WorkflowStepStatus{{
+		StepStatus:     common.StepStatus{ID: "group"},
+		SubStepsStatus: []common.WorkflowSubStepStatus{{StepStatus: common.StepStatus{ID: "sub"}}},
+	}}}
+	r.NoError(CheckStep(app, claims))
+	claims.StepID = "old"
+	r.Equal(ErrOutdated, CheckStep(app, claims))
+	claims.Name = "other"
+	r.Equal(ErrInvalidToken, CheckStep(app, claims))
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// StorageBackendConfigMap stores the workflow context in a single ConfigMap
+	StorageBackendConfigMap = "configmap"
+	// StorageBackendChunkedConfigMap compresses the workflow context and spreads it across multiple ConfigMaps
+	StorageBackendChunkedConfigMap = "chunked-configmap"
+
+	// AnnotationContextChunks is the annotation key of the number of chunks that store the workflow context
+	AnnotationContextChunks = "workflow.oam.dev/context-chunks"
+	// AnnotationContextGeneration is the annotation key of the generation of the chunks, which is the digest of the
+	// compressed workflow context. The chunks of each generation are written under their own names, so the index
+	// never refers to chunks that are partially overwritten.
+	AnnotationContextGeneration = "workflow.oam.dev/context-generation"
+	// LabelContextStore is the label key of the chunks, its value identifies the store the chunks belong to
+	LabelContextStore = "workflow.oam.dev/context-store"
+	// ConfigMapKeyChunk is the key in ConfigMap BinaryData field for containing the compressed data of the chunk
+	ConfigMapKeyChunk = "chunk"
+)
+
+var (
+	// StorageBackend is the backend used to persist the workflow context
+	StorageBackend = StorageBackendConfigMap
+	// ChunkSize is the max bytes of the compressed workflow context stored in one chunk
+	ChunkSize = 512 * 1024
+)
+
+// Storage is the backend to persist the workflow context store. The store is always loaded
+// into a ConfigMap with plain data in memory, no matter how it's persisted by the backend.
+type Storage interface {
+	// Load fills the store by its name and namespace, it returns a NotFound error if the store doesn't exist.
+	Load(ctx context.Context, cli client.Client, store *corev1.ConfigMap) error
+	// Save creates or updates the store.
+	Save(ctx context.Context, cli client.Client, store *corev1.ConfigMap) error
+}
+
+// IsValidStorageBackend checks whether the storage backend is supported
+func IsValidStorageBackend(backend string) bool {
+	return backend == StorageBackendConfigMap || backend == StorageBackendChunkedConfigMap
+}
+
+// getStorage returns the storage of the workflow context
+func getStorage() Storage {
+	if EnableInMemoryContext {
+		return MemStore
+	}
+	if StorageBackend == StorageBackendChunkedConfigMap {
+		return &chunkedConfigMapStorage{chunkSize: ChunkSize}
+	}
+	return &configMapStorage{}
+}
+
+// loadStore loads the store from the cluster. The store written by any backend can be recognized,
+// so the running workflows can be migrated to another backend transparently, the store will be
+// converted to the format of the current backend in the next save.
+func loadStore(ctx context.Context, cli client.Client, store *corev1.ConfigMap) error {
+	if err := cli.Get(ctx, client.ObjectKey{Name: store.Name, Namespace: store.Namespace}, store); err != nil {
+		return err
+	}
+	if _, ok := store.Annotations[AnnotationContextChunks]; !ok {
+		return nil
+	}
+	count, err := getChunkCount(store)
+	if err != nil {
+		return err
+	}
+	generation := store.Annotations[AnnotationContextGeneration]
+	var buf bytes.Buffer
+	for i := 0; i < count; i++ {
+		chunk := &corev1.ConfigMap{}
+		if err := cli.Get(ctx, client.ObjectKey{Name: generateChunkName(store.Name, generation, i), Namespace: store.Namespace}, chunk); err != nil {
+			return errors.WithMessagef(err, "get chunk %d of the workflow context", i)
+		}
+		buf.Write(chunk.BinaryData[ConfigMapKeyChunk])
+	}
+	data, err := decompressData(buf.Bytes())
+	if err != nil {
+		return errors.WithMessage(err, "decode chunks of the workflow context")
+	}
+	store.Data = data
+	return nil
+}
+
+// saveStore creates or updates the store object.
+func saveStore(ctx context.Context, cli client.Client, store *corev1.ConfigMap) error {
+	if err := cli.Update(ctx, store); err != nil {
+		if kerrors.IsNotFound(err) {
+			return cli.Create(ctx, store)
+		}
+		return err
+	}
+	return nil
+}
+
+// deleteChunks deletes the chunks of the store except the first `keep` ones of the given generation. The chunks are
+// found by the label instead of the index, so the chunks are cleaned up even if the index is lost.
+func deleteChunks(ctx context.Context, cli client.Client, store *corev1.ConfigMap, generation string, keep int) error {
+	kept := map[string]bool{}
+	for i := 0; i < keep; i++ {
+		kept[generateChunkName(store.Name, generation, i)] = true
+	}
+	chunks := &corev1.ConfigMapList{}
+	if err := cli.List(ctx, chunks, client.InNamespace(store.Namespace), client.MatchingLabels{LabelContextStore: generateStoreLabelValue(store.Name)}); err != nil {
+		return errors.WithMessage(err, "list chunks of the workflow context")
+	}
+	for i := range chunks.Items {
+		chunk := &chunks.Items[i]
+		if kept[chunk.Name] || !strings.HasPrefix(chunk.Name, store.Name+"-chunk-") {
+			continue
+		}
+		if err := cli.Delete(ctx, chunk); err != nil && !kerrors.IsNotFound(err) {
+			return errors.WithMessagef(err, "delete chunk %s of the workflow context", chunk.Name)
+		}
+	}
+	return nil
+}
+
+type configMapStorage struct{}
+
+// Load loads the store from the ConfigMap.
+func (s *configMapStorage) Load(ctx context.Context, cli client.Client, store *corev1.ConfigMap) error {
+	return loadStore(ctx, cli, store)
+}
+
+// Save saves the store into the ConfigMap, the chunks written by the chunked backend will be removed.
+func (s *configMapStorage) Save(ctx context.Context, cli client.Client, store *corev1.ConfigMap) error {
+	if _, ok := store.Annotations[AnnotationContextChunks]; !ok {
+		return saveStore(ctx, cli, store)
+	}
+	delete(store.Annotations, AnnotationContextChunks)
+	delete(store.Annotations, AnnotationContextGeneration)
+	if err := saveStore(ctx, cli, store); err != nil {
+		return err
+	}
+	return deleteChunks(ctx, cli, store, "", 0)
+}
+
+type chunkedConfigMapStorage struct {
+	chunkSize int
+}
+
+// Load loads the store from the chunks.
+func (s *chunkedConfigMapStorage) Load(ctx context.Context, cli client.Client, store *corev1.ConfigMap) error {
+	return loadStore(ctx, cli, store)
+}
+
+// Save compresses the data of the store and saves it into chunks. The store itself only keeps the index of the chunks.
+// The chunks of a new generation are written first, then the index is switched to them, and the chunks of the old
+// generations are deleted at last, so the store can always be loaded even if the save is interrupted. Nothing is
+// written if the data is not changed since the last save.
+func (s *chunkedConfigMapStorage) Save(ctx context.Context, cli client.Client, store *corev1.ConfigMap) error {
+	compressed, err := compressData(store.Data)
+	if err != nil {
+		return errors.WithMessage(err, "encode the workflow context")
+	}
+	sum := sha256.Sum256(compressed)
+	generation := hex.EncodeToString(sum[:])[:10]
+	if _, ok := store.Annotations[AnnotationContextChunks]; ok && store.Annotations[AnnotationContextGeneration] == generation && store.ResourceVersion != "" {
+		return nil
+	}
+
+	chunkSize := s.chunkSize
+	if chunkSize <= 0 {
+		chunkSize = len(compressed) + 1
+	}
+	count := 0
+	for ; count*chunkSize < len(compressed); count++ {
+		end := (count + 1) * chunkSize
+		if end > len(compressed) {
+			end = len(compressed)
+		}
+		chunk := &corev1.ConfigMap{}
+		chunk.Name = generateChunkName(store.Name, generation, count)
+		chunk.Namespace = store.Namespace
+		chunk.SetLabels(map[string]string{LabelContextStore: generateStoreLabelValue(store.Name)})
+		chunk.SetOwnerReferences(store.GetOwnerReferences())
+		chunk.BinaryData = map[string][]byte{ConfigMapKeyChunk: compressed[count*chunkSize : end]}
+		if err := saveStore(ctx, cli, chunk); err != nil {
+			return errors.WithMessagef(err, "save chunk %d of the workflow context", count)
+		}
+	}
+
+	index := store.DeepCopy()
+	index.Data = nil
+	if index.Annotations == nil {
+		index.Annotations = map[string]string{}
+	}
+	index.Annotations[AnnotationContextChunks] = strconv.Itoa(count)
+	index.Annotations[AnnotationContextGeneration] = generation
+	if err := saveStore(ctx, cli, index); err != nil {
+		return err
+	}
+	// keep the plain data in memory for the following operations on the context
+	index.Data = store.Data
+	*store = *index
+	return deleteChunks(ctx, cli, store, generation, count)
+}
+
+func getChunkCount(store *corev1.ConfigMap) (int, error) {
+	count, err := strconv.Atoi(store.Annotations[AnnotationContextChunks])
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid chunk count of the workflow context %s", store.Name)
+	}
+	return count, nil
+}
+
+// generateChunkName returns the name of the chunk, the chunks written before the generation is introduced have no
+// generation in their names
+func generateChunkName(storeName string, generation string, index int) string {
+	if generation == "" {
+		return fmt.Sprintf("%s-chunk-%d", storeName, index)
+	}
+	return fmt.Sprintf("%s-chunk-%s-%d", storeName, generation, index)
+}
+
+// generateStoreLabelValue returns the value of the label of the chunks, the long store name is truncated and suffixed
+// by its hash to fit the length limit of the label value
+func generateStoreLabelValue(storeName string) string {
+	if len(storeName) <= validation.LabelValueMaxLength {
+		return storeName
+	}
+	sum := sha256.Sum256([]byte(storeName))
+	suffix := hex.EncodeToString(sum[:])[:16]
+	return strings.TrimRight(storeName[:validation.LabelValueMaxLength-len(suffix)-1], "-._") + "-" + suffix
+}
+
+func compressData(data map[string]string) ([]byte, error) {
+	js, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(js); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompressData(compressed []byte) (map[string]string, error) {
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	js, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]string{}
+	if err := json.Unmarshal(js, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+)
+
+func TestChunkedStorage(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().Build()
+	defer func() {
+		StorageBackend = StorageBackendConfigMap
+		ChunkSize = 512 * 1024
+	}()
+	StorageBackend = StorageBackendChunkedConfigMap
+	ChunkSize = 64
+
+	wfCtx, err := NewContext(cli, "default", "app", "uid")
+	r.NoError(err)
+	v, err := value.NewValue(`{"message": "`+strings.Repeat("hello", 100)+`"}`, nil, "")
+	r.NoError(err)
+	r.NoError(wfCtx.SetVar(v, "output"))
+	r.NoError(wfCtx.Commit())
+
+	index := &corev1.ConfigMap{}
+	r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: generateStoreName("app")}, index))
+	r.Equal(0, len(index.Data))
+	count, err := getChunkCount(index)
+	r.NoError(err)
+	r.True(count > 1)
+	generation := index.Annotations[AnnotationContextGeneration]
+	r.NotEmpty(generation)
+	for i := 0; i < count; i++ {
+		chunk := &corev1.ConfigMap{}
+		r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: generateChunkName(index.Name, generation, i)}, chunk))
+		r.Equal("uid", string(chunk.OwnerReferences[0].UID))
+	}
+
+	// nothing is written if the data is not changed
+	r.NoError(wfCtx.Commit())
+	unchanged := &corev1.ConfigMap{}
+	r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: generateStoreName("app")}, unchanged))
+	r.Equal(index.ResourceVersion, unchanged.ResourceVersion)
+
+	// the changed data is written into the chunks of a new generation, and the chunks of the old one are deleted
+	wfCtx.SetMutableValue("value", "key")
+	r.NoError(wfCtx.Commit())
+	changed := &corev1.ConfigMap{}
+	r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: generateStoreName("app")}, changed))
+	r.NotEqual(generation, changed.Annotations[AnnotationContextGeneration])
+	err = cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: generateChunkName(index.Name, generation, 0)}, &corev1.ConfigMap{})
+	r.True(kerrors.IsNotFound(err))
+
+	loaded, err := LoadContext(cli, "default", "app")
+	r.NoError(err)
+	output, err := loaded.GetVar("output", "message")
+	r.NoError(err)
+	msg, err := output.CueValue().String()
+	r.NoError(err)
+	r.Equal(strings.Repeat("hello", 100), msg)
+
+	// migrate the running workflow back to the configmap backend
+	StorageBackend = StorageBackendConfigMap
+	loaded, err = LoadContext(cli, "default", "app")
+	r.NoError(err)
+	loaded.SetMutableValue("value", "key")
+	r.NoError(loaded.Commit())
+	index = &corev1.ConfigMap{}
+	r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: generateStoreName("app")}, index))
+	r.NotEmpty(index.Data[ConfigMapKeyVars])
+	r.NotContains(index.Annotations, AnnotationContextChunks)
+	r.NotContains(index.Annotations, AnnotationContextGeneration)
+	chunks := &corev1.ConfigMapList{}
+	r.NoError(cli.List(ctx, chunks, client.MatchingLabels{LabelContextStore: generateStoreName("app")}))
+	r.Equal(0, len(chunks.Items))
+
+	// migrate the running workflow to the chunked backend
+	StorageBackend = StorageBackendChunkedConfigMap
+	ChunkSize = 1024 * 1024
+	loaded, err = LoadContext(cli, "default", "app")
+	r.NoError(err)
+	r.Equal("value", loaded.GetMutableValue("key"))
+	loaded.SetMutableValue("new-value", "key")
+	r.NoError(loaded.Commit())
+	loaded, err = LoadContext(cli, "default", "app")
+	r.NoError(err)
+	r.Equal("new-value", loaded.GetMutableValue("key"))
+	r.Equal("1", loaded.GetStore().Annotations[AnnotationContextChunks])
+	generation = loaded.GetStore().Annotations[AnnotationContextGeneration]
+	chunks = &corev1.ConfigMapList{}
+	r.NoError(cli.List(ctx, chunks, client.MatchingLabels{LabelContextStore: generateStoreName("app")}))
+	r.Equal(1, len(chunks.Items))
+	r.Equal(generateChunkName(index.Name, generation, 0), chunks.Items[0].Name)
+
+	// the outdated chunks are cleaned up even if the index is lost
+	ChunkSize = 64
+	loaded.SetMutableValue(strings.Repeat("hello", 100), "key")
+	r.NoError(loaded.Commit())
+	r.NoError(cli.Delete(ctx, loaded.GetStore()))
+	ChunkSize = 1024 * 1024
+	wfCtx, err = NewContext(cli, "default", "app", "uid")
+	r.NoError(err)
+	r.NoError(wfCtx.Commit())
+	chunks = &corev1.ConfigMapList{}
+	r.NoError(cli.List(ctx, chunks, client.MatchingLabels{LabelContextStore: generateStoreName("app")}))
+	r.Equal(1, len(chunks.Items))
+	r.Equal(generateChunkName(generateStoreName("app"), wfCtx.GetStore().Annotations[AnnotationContextGeneration], 0), chunks.Items[0].Name)
+}
+
+func TestLoadLegacyChunks(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	compressed, err := compressData(map[string]string{"key": "value"})
+	r.NoError(err)
+	index := &corev1.ConfigMap{}
+	index.Name, index.Namespace = generateStoreName("app"), "default"
+	index.Annotations = map[string]string{AnnotationContextChunks: "1"}
+	chunk := &corev1.ConfigMap{}
+	chunk.Name, chunk.Namespace = generateChunkName(index.Name, "", 0), "default"
+	chunk.Labels = map[string]string{LabelContextStore: generateStoreLabelValue(index.Name)}
+	chunk.BinaryData = map[string][]byte{ConfigMapKeyChunk: compressed}
+	cli := fake.NewClientBuilder().WithObjects(index, chunk).Build()
+
+	store := &corev1.ConfigMap{}
+	store.Name, store.Namespace = index.Name, index.Namespace
+	s := &chunkedConfigMapStorage{chunkSize: 64}
+	r.NoError(s.Load(ctx, cli, store))
+	r.Equal("value", store.Data["key"])
+
+	// the chunks without generation are replaced by the chunks of the generation in the next save
+	r.NoError(s.Save(ctx, cli, store))
+	generation := store.Annotations[AnnotationContextGeneration]
+	r.NotEmpty(generation)
+	err = cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: chunk.Name}, &corev1.ConfigMap{})
+	r.True(kerrors.IsNotFound(err))
+	r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: generateChunkName(index.Name, generation, 0)}, &corev1.ConfigMap{}))
+}
+
+func TestGenerateStoreLabelValue(t *testing.T) {
+	r := require.New(t)
+	r.Equal("workflow-app-context", generateStoreLabelValue("workflow-app-context"))
+	long := generateStoreLabelValue(generateStoreName(strings.Repeat("a", 100)))
+	r.Equal(63, len(long))
+	r.NotEqual(long, generateStoreLabelValue(generateStoreName(strings.Repeat("a", 101))))
+}
+
+func TestIsValidStorageBackend(t *testing.T) {
+	r := require.New(t)
+	r.True(IsValidStorageBackend(StorageBackendConfigMap))
+	r.True(IsValidStorageBackend(StorageBackendChunkedConfigMap))
+	r.False(IsValidStorageBackend("secret"))
+}
@@ -219,16 +219,7 @@ func (wf *WorkflowContext) writeToStore() error {
 }
 
 func (wf *WorkflowContext) sync() error {
-	ctx := context.Background()
-	if EnableInMemoryContext {
-		MemStore.UpdateInMemoryContext(wf.store)
-	} else if err := wf.cli.Update(ctx, wf.store); err != nil {
-		if kerrors.IsNotFound(err) {
-			return wf.cli.Create(ctx, wf.store)
-		}
-		return err
-	}
-	return nil
+	return getStorage().Save(context.Background(), wf.cli, wf.store)
 }
 
 // LoadFromConfigMap recover workflow context from configMap.
@@ -359,20 +350,19 @@ func newContext(cli client.Client, ns, app string, appUID types.UID) (*WorkflowC
 			Controller: pointer.BoolPtr(true),
 		},
 	})
-	if EnableInMemoryContext {
-		MemStore.GetOrCreateInMemoryContext(&store)
-	} else if err := cli.Get(ctx, client.ObjectKey{Name: store.Name, Namespace: store.Namespace}, &store); err != nil {
-		if kerrors.IsNotFound(err) {
-			if err := cli.Create(ctx, &store); err != nil {
-				return nil, err
-			}
-		} else {
+	storage := getStorage()
+	if err := storage.Load(ctx, cli, &store); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return nil, err
+		}
+		if err := storage.Save(ctx, cli, &store); err != nil {
 			return nil, err
 		}
 	}
-	store.Annotations = map[string]string{
-		AnnotationStartTimestamp: time.Now().String(),
+	if store.Annotations == nil {
+		store.Annotations = map[string]string{}
 	}
+	store.Annotations[AnnotationStartTimestamp] = time.Now().String()
 	memCache := getMemoryStore(fmt.Sprintf("%s-%s", app, ns))
 	wfCtx := &WorkflowContext{
 		cli:         cli,
@@ -406,12 +396,7 @@ func LoadContext(cli client.Client, ns, app string) (Context, error) {
 	var store corev1.ConfigMap
 	store.Name = generateStoreName(app)
 	store.Namespace = ns
-	if EnableInMemoryContext {
-		MemStore.GetOrCreateInMemoryContext(&store)
-	} else if err := cli.Get(context.Background(), client.ObjectKey{
-		Namespace: ns,
-		Name:      generateStoreName(app),
-	}, &store); err != nil {
+	if err := getStorage().Load(context.Background(), cli, &store); err != nil {
 		return nil, err
 	}
 	memCache := getMemoryStore(fmt.Sprintf("%s-%s", app, ns))
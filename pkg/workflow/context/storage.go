@@ -17,10 +17,12 @@ limitations under the License.
 package context
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
 	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var (
@@ -38,6 +40,18 @@ var MemStore = &inMemoryContextStorage{
 	contexts: map[string]*v1.ConfigMap{},
 }
 
+// Load loads the context from memory, the context will be created if not exists.
+func (o *inMemoryContextStorage) Load(_ context.Context, _ client.Client, cm *v1.ConfigMap) error {
+	o.GetOrCreateInMemoryContext(cm)
+	return nil
+}
+
+// Save saves the context into memory.
+func (o *inMemoryContextStorage) Save(_ context.Context, _ client.Client, cm *v1.ConfigMap) error {
+	o.UpdateInMemoryContext(cm)
+	return nil
+}
+
 func (o *inMemoryContextStorage) getKey(cm *v1.ConfigMap) string {
 	ns := cm.GetNamespace()
 	if ns == "" {
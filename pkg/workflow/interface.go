@@ -30,6 +30,9 @@ type Workflow interface {
 	// It returns done=true only if all steps are executed and succeeded.
 	ExecuteSteps(ctx monitorContext.Context, appRev *v1beta1.ApplicationRevision, taskRunners []types.TaskRunner) (state common.WorkflowState, err error)
 
+	// SetExitHandlers sets the exit handlers executed after the steps reach the terminal state.
+	SetExitHandlers(handlers *types.ExitHandlers)
+
 	// Trace record workflow state in controllerRevision.
 	Trace() error
 
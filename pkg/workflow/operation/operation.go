@@ -27,6 +27,7 @@ import (
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/pkg/apiserver/domain/service"
+	"github.com/oam-dev/kubevela/pkg/auth"
 	"github.com/oam-dev/kubevela/pkg/controller/core.oam.dev/v1alpha2/application"
 	"github.com/oam-dev/kubevela/pkg/controller/utils"
 	"github.com/oam-dev/kubevela/pkg/oam"
@@ -40,7 +41,8 @@ import (
 // WorkflowOperator is opratior handler for workflow's resume/rollback/restart
 type WorkflowOperator interface {
 	Suspend(ctx context.Context, app *v1beta1.Application) error
-	Resume(ctx context.Context, app *v1beta1.Application) error
+	Resume(ctx context.Context, app *v1beta1.Application, approver *auth.Identity) error
+	Reject(ctx context.Context, app *v1beta1.Application, approver *auth.Identity, message string) error
 	Rollback(ctx context.Context, app *v1beta1.Application) error
 	Restart(ctx context.Context, app *v1beta1.Application) error
 	Terminate(ctx context.Context, app *v1beta1.Application) error
@@ -80,8 +82,8 @@ func (wo wfOperator) Suspend(ctx context.Context, app *v1beta1.Application) erro
 	return wo.writeOutputF("Successfully suspend workflow: %s\n", app.Name)
 }
 
-// Resume a suspending workflow
-func (wo wfOperator) Resume(ctx context.Context, app *v1beta1.Application) error {
+// Resume a suspending workflow, the approver is recorded as an approval of the running approval steps
+func (wo wfOperator) Resume(ctx context.Context, app *v1beta1.Application, approver *auth.Identity) error {
 	if app.Status.Workflow == nil {
 		return fmt.Errorf("the workflow in application is not running")
 	}
@@ -100,13 +102,27 @@ func (wo wfOperator) Resume(ctx context.Context, app *v1beta1.Application) error
 	}
 
 	if app.Status.Workflow.Suspend {
-		if err = service.ResumeWorkflow(ctx, wo.cli, app); err != nil {
+		if err = service.ResumeWorkflow(ctx, wo.cli, app, approver); err != nil {
 			return err
 		}
 	}
 	return wo.writeOutputF("Successfully resume workflow: %s\n", app.Name)
 }
 
+// Reject the running approval steps of a suspending workflow
+func (wo wfOperator) Reject(ctx context.Context, app *v1beta1.Application, approver *auth.Identity, message string) error {
+	if app.Status.Workflow == nil {
+		return fmt.Errorf("the workflow in application is not running")
+	}
+	if app.Status.Workflow.Terminated {
+		return fmt.Errorf("can not reject a terminated workflow")
+	}
+	if err := service.RejectWorkflow(ctx, wo.cli, app, approver, message); err != nil {
+		return err
+	}
+	return wo.writeOutputF("Successfully reject workflow: %s\n", app.Name)
+}
+
 // Rollback a running in middle state workflow.
 //nolint
 func (wo wfOperator) Rollback(ctx context.Context, app *v1beta1.Application) error {
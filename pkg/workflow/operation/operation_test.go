@@ -58,7 +58,7 @@ var _ = Describe("Kruise rollout test", func() {
 		checkApp := v1beta1.Application{}
 		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "opt-app"}, &checkApp)).Should(BeNil())
 		operator := NewWorkflowOperator(k8sClient, nil)
-		Expect(operator.Resume(ctx, &checkApp)).Should(BeNil())
+		Expect(operator.Resume(ctx, &checkApp, nil)).Should(BeNil())
 		checkApp = v1beta1.Application{}
 		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "opt-app"}, &checkApp)).Should(BeNil())
 		Expect(checkApp.Status.Workflow.Suspend).Should(BeEquivalentTo(false))
@@ -0,0 +1,336 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"cuelang.org/go/cue"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	"github.com/oam-dev/kubevela/pkg/definition"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/utils/config"
+	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers"
+	wfTypes "github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+const (
+	// ProviderName is provider name for install.
+	ProviderName = "config"
+
+	// definitionCatalog is the label of the config type definitions
+	definitionCatalog = "catalog.config.oam.dev"
+)
+
+type provider struct {
+	app *v1beta1.Application
+	cli client.Client
+}
+
+// ReadParams is the parameters of reading the config
+type ReadParams struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// ListParams is the parameters of listing the configs
+type ListParams struct {
+	Type string `json:"type"`
+}
+
+// WriteParams is the parameters of writing the config
+type WriteParams struct {
+	Name        string                 `json:"name"`
+	Type        string                 `json:"type"`
+	Properties  map[string]interface{} `json:"properties"`
+	Alias       string                 `json:"alias,omitempty"`
+	Description string                 `json:"description,omitempty"`
+}
+
+// Config is the config read by the workflow steps
+type Config struct {
+	Name        string                 `json:"name"`
+	Type        string                 `json:"type"`
+	Project     string                 `json:"project"`
+	Alias       string                 `json:"alias,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Properties  map[string]interface{} `json:"properties"`
+	Data        map[string]string      `json:"data"`
+}
+
+// project returns the project of the application, the configs of other projects are invisible to the workflow.
+// The project is read from the namespace of the application bound to the env of the project by the apiserver, since
+// the labels of the application are set by its author.
+func (p *provider) project(ctx context.Context) (string, error) {
+	namespace := &corev1.Namespace{}
+	if err := p.cli.Get(ctx, client.ObjectKey{Name: p.app.Namespace}, namespace); err != nil {
+		return "", errors.Wrapf(err, "failed to get the project of namespace %s", p.app.Namespace)
+	}
+	return namespace.Labels[oam.LabelNamespaceOfProjectName], nil
+}
+
+// Read reads the config by name, the step waits until the secret of the config is rendered.
+func (p *provider) Read(ctx wfContext.Context, v *value.Value, act wfTypes.Action) error {
+	params := &ReadParams{}
+	if err := v.UnmarshalTo(params); err != nil {
+		return err
+	}
+	if params.Name == "" {
+		return errors.New("name of the config must be set")
+	}
+	project, err := p.project(context.Background())
+	if err != nil {
+		return err
+	}
+	c, err := p.load(context.Background(), params.Name, project)
+	if err != nil {
+		return err
+	}
+	if c == nil || (params.Type != "" && c.Type != params.Type) {
+		return fmt.Errorf("config %s is not found", params.Name)
+	}
+	if c.Data == nil {
+		act.Wait(fmt.Sprintf("waiting for the secret of config %s", params.Name))
+		return nil
+	}
+	return fillConfig(v, c, "config")
+}
+
+// List lists the configs of the type that can be used in the project of the application.
+func (p *provider) List(ctx wfContext.Context, v *value.Value, act wfTypes.Action) error {
+	params := &ListParams{}
+	if err := v.UnmarshalTo(params); err != nil {
+		return err
+	}
+	if params.Type == "" {
+		return errors.New("type of the configs must be set")
+	}
+	project, err := p.project(context.Background())
+	if err != nil {
+		return err
+	}
+	apps := &v1beta1.ApplicationList{}
+	if err := p.cli.List(context.Background(), apps, client.InNamespace(types.DefaultKubeVelaNS), client.MatchingLabels{
+		types.LabelConfigCatalog: types.VelaCoreConfig,
+		types.LabelConfigType:    params.Type,
+	}); err != nil {
+		return err
+	}
+	sort.Slice(apps.Items, func(i, j int) bool { return apps.Items[i].Name < apps.Items[j].Name })
+	configs := []*Config{}
+	for _, app := range apps.Items {
+		c, err := p.load(context.Background(), app.Name, project)
+		if err != nil {
+			return err
+		}
+		if c != nil && c.Data != nil {
+			configs = append(configs, c)
+		}
+	}
+	return fillConfig(v, configs, "configs")
+}
+
+// Write creates or updates the config of the type with the properties, the properties are validated with the
+// parameters of the config type. The config belongs to the project of the application, the application out of any
+// project can't write the configs.
+func (p *provider) Write(ctx wfContext.Context, v *value.Value, act wfTypes.Action) error {
+	params := &WriteParams{}
+	if err := v.UnmarshalTo(params); err != nil {
+		return err
+	}
+	if params.Name == "" || params.Type == "" {
+		return errors.New("name and type of the config must be set")
+	}
+	project, err := p.project(context.Background())
+	if err != nil {
+		return err
+	}
+	if project == "" {
+		return fmt.Errorf("namespace %s is not bound to any project, the configs can't be written", p.app.Namespace)
+	}
+	if err := p.validate(context.Background(), params); err != nil {
+		return err
+	}
+	properties, err := json.Marshal(params.Properties)
+	if err != nil {
+		return err
+	}
+
+	app := &v1beta1.Application{}
+	err = p.cli.Get(context.Background(), client.ObjectKey{Namespace: types.DefaultKubeVelaNS, Name: params.Name}, app)
+	switch {
+	case kerrors.IsNotFound(err):
+		// the secret of the config created without the application, such as the ones created by the addons
+		secret := &corev1.Secret{}
+		if err := p.cli.Get(context.Background(), client.ObjectKey{Namespace: types.DefaultKubeVelaNS, Name: params.Name}, secret); err == nil {
+			return fmt.Errorf("secret %s exists, it can't be written by the workflow", params.Name)
+		} else if !kerrors.IsNotFound(err) {
+			return err
+		}
+		app = &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: params.Name, Namespace: types.DefaultKubeVelaNS}}
+	case err != nil:
+		return err
+	case app.Labels[types.LabelConfigCatalog] != types.VelaCoreConfig:
+		return fmt.Errorf("application %s is not a config", params.Name)
+	case app.Labels[types.LabelConfigType] != params.Type:
+		return fmt.Errorf("config %s exists with the type %s", params.Name, app.Labels[types.LabelConfigType])
+	case app.Labels[types.LabelConfigProject] == "":
+		return fmt.Errorf("config %s is shared by all the projects, it can't be written by the workflow", params.Name)
+	case app.Labels[types.LabelConfigProject] != project:
+		return fmt.Errorf("config %s belongs to another project", params.Name)
+	}
+	if app.Labels == nil {
+		app.Labels = map[string]string{}
+	}
+	app.Labels[model.LabelSourceOfTruth] = model.FromInner
+	app.Labels[types.LabelConfigCatalog] = types.VelaCoreConfig
+	app.Labels[types.LabelConfigType] = params.Type
+	app.Labels[types.LabelConfigProject] = project
+	if app.Annotations == nil {
+		app.Annotations = map[string]string{}
+	}
+	app.Annotations[types.AnnotationConfigAlias] = params.Alias
+	app.Annotations[types.AnnotationConfigDescription] = params.Description
+	app.Spec.Components = []common.ApplicationComponent{{
+		Name:       params.Name,
+		Type:       params.Type,
+		Properties: &runtime.RawExtension{Raw: properties},
+	}}
+	if app.ResourceVersion == "" {
+		return p.cli.Create(context.Background(), app)
+	}
+	return p.cli.Update(context.Background(), app)
+}
+
+// load loads the config by name, it returns nil if the config doesn't exist or can't be used in the project.
+// The data of the config is nil if the secret is not rendered yet.
+func (p *provider) load(ctx context.Context, name string, project string) (*Config, error) {
+	key := client.ObjectKey{Namespace: types.DefaultKubeVelaNS, Name: name}
+	app := &v1beta1.Application{}
+	if err := p.cli.Get(ctx, key, app); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return nil, err
+		}
+		app = nil
+	}
+	secret := &corev1.Secret{}
+	if err := p.cli.Get(ctx, key, secret); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return nil, err
+		}
+		secret = nil
+	}
+
+	c := &Config{Name: name, Properties: map[string]interface{}{}}
+	switch {
+	case app != nil && app.Labels[types.LabelConfigCatalog] == types.VelaCoreConfig:
+		if !config.LabelsProjectMatched(app.Labels, project) {
+			return nil, nil
+		}
+		c.Type = app.Labels[types.LabelConfigType]
+		c.Project = app.Labels[types.LabelConfigProject]
+		c.Alias = app.Annotations[types.AnnotationConfigAlias]
+		c.Description = app.Annotations[types.AnnotationConfigDescription]
+		if len(app.Spec.Components) > 0 && app.Spec.Components[0].Properties != nil {
+			if err := json.Unmarshal(app.Spec.Components[0].Properties.Raw, &c.Properties); err != nil {
+				return nil, errors.Wrapf(err, "failed to decode the properties of config %s", name)
+			}
+		}
+	case secret != nil && secret.Labels[types.LabelConfigCatalog] == types.VelaCoreConfig:
+		// the configs created without the applications, such as the ones created by the addons
+		c.Type = secret.Labels[types.LabelConfigType]
+		c.Project = secret.Labels[types.LabelConfigProject]
+	default:
+		return nil, nil
+	}
+	if secret != nil {
+		if !config.ProjectMatched(secret, project) {
+			return nil, nil
+		}
+		c.Data = map[string]string{}
+		for k, v := range secret.Data {
+			c.Data[k] = string(v)
+		}
+	}
+	return c, nil
+}
+
+// validate checks the config type and the properties with the parameters of the config type
+func (p *provider) validate(ctx context.Context, params *WriteParams) error {
+	def := &v1beta1.ComponentDefinition{}
+	if err := p.cli.Get(ctx, client.ObjectKey{Namespace: types.DefaultKubeVelaNS, Name: params.Type}, def); err != nil {
+		if kerrors.IsNotFound(err) {
+			return fmt.Errorf("config type %s is not found", params.Type)
+		}
+		return err
+	}
+	if def.Labels[definitionCatalog] != types.VelaCoreConfig && def.Labels[definition.UserPrefix+definitionCatalog] != types.VelaCoreConfig {
+		return fmt.Errorf("%s is not a config type", params.Type)
+	}
+	if def.Spec.Schematic == nil || def.Spec.Schematic.CUE == nil {
+		return nil
+	}
+	properties, err := json.Marshal(params.Properties)
+	if err != nil {
+		return err
+	}
+	template, err := value.NewValue(def.Spec.Schematic.CUE.Template+"\nparameter: "+string(properties), nil, "")
+	if err != nil {
+		return errors.Wrapf(err, "invalid properties of config type %s", params.Type)
+	}
+	parameter, err := template.LookupValue("parameter")
+	if err != nil {
+		return err
+	}
+	if err := parameter.CueValue().Validate(cue.Concrete(true)); err != nil {
+		return errors.Wrapf(err, "invalid properties of config type %s", params.Type)
+	}
+	return nil
+}
+
+func fillConfig(v *value.Value, x interface{}, path string) error {
+	b, err := json.Marshal(x)
+	if err != nil {
+		return err
+	}
+	return v.FillObject(json.RawMessage(b), path)
+}
+
+// Install register handlers to provider discover.
+func Install(p providers.Providers, app *v1beta1.Application, cli client.Client) {
+	prd := &provider{app: app, cli: cli}
+	p.Register(ProviderName, map[string]providers.Handler{
+		"read":  prd.Read,
+		"list":  prd.List,
+		"write": prd.Write,
+	})
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers/mock"
+)
+
+func newConfig(name, configType, project string, properties string, data map[string]string) []client.Object {
+	labels := map[string]string{
+		types.LabelConfigCatalog: types.VelaCoreConfig,
+		types.LabelConfigType:    configType,
+		types.LabelConfigProject: project,
+	}
+	app := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: types.DefaultKubeVelaNS, Labels: labels},
+		Spec: v1beta1.ApplicationSpec{Components: []common.ApplicationComponent{{
+			Name:       name,
+			Type:       configType,
+			Properties: &runtime.RawExtension{Raw: []byte(properties)},
+		}}},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: types.DefaultKubeVelaNS},
+		StringData: data,
+		Data:       map[string][]byte{},
+	}
+	for k, v := range data {
+		secret.Data[k] = []byte(v)
+	}
+	return []client.Object{app, secret}
+}
+
+func newProvider(objs ...client.Object) *provider {
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = v1beta1.AddToScheme(s)
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name: "default", Labels: map[string]string{oam.LabelNamespaceOfProjectName: "p1"},
+	}}
+	return &provider{
+		app: &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{
+			Name: "app", Namespace: "default", Labels: map[string]string{oam.LabelProject: "p2"},
+		}},
+		cli: fake.NewClientBuilder().WithScheme(s).WithObjects(append(objs, ns)...).Build(),
+	}
+}
+
+func TestRead(t *testing.T) {
+	r := require.New(t)
+	var objs []client.Object
+	objs = append(objs, newConfig("registry", "config-image-registry", "p1", `{"registry":"index.docker.io"}`, map[string]string{"insecure-skip-verify": "true"})...)
+	objs = append(objs, newConfig("shared", "config-image-registry", "", `{"registry":"ghcr.io"}`, map[string]string{})...)
+	objs = append(objs, newConfig("private", "config-image-registry", "p2", `{"registry":"quay.io"}`, map[string]string{})...)
+	objs = append(objs, newConfig("helm", "config-helm-repository", "p1", `{"url":"https://charts.example.com"}`, map[string]string{"url": "https://charts.example.com"})...)
+	pending := newConfig("pending", "config-image-registry", "p1", `{"registry":"gcr.io"}`, nil)[0]
+	p := newProvider(append(objs, pending)...)
+
+	v, err := value.NewValue(`name: "registry", type: "config-image-registry"`, nil, "")
+	r.NoError(err)
+	r.NoError(p.Read(nil, v, &mock.Action{}))
+	registry, err := v.GetString("config", "properties", "registry")
+	r.NoError(err)
+	r.Equal("index.docker.io", registry)
+	insecure, err := v.GetString("config", "data", "insecure-skip-verify")
+	r.NoError(err)
+	r.Equal("true", insecure)
+
+	for _, params := range []string{`name: "private"`, `name: "registry", type: "config-helm-repository"`, `name: "not-exist"`} {
+		v, err = value.NewValue(params, nil, "")
+		r.NoError(err)
+		r.Error(p.Read(nil, v, &mock.Action{}), params)
+	}
+
+	v, err = value.NewValue(`name: "pending"`, nil, "")
+	r.NoError(err)
+	act := &mock.Action{}
+	r.NoError(p.Read(nil, v, act))
+	r.Equal("Wait", act.Phase)
+
+	v, err = value.NewValue(`type: "config-image-registry"`, nil, "")
+	r.NoError(err)
+	r.NoError(p.List(nil, v, &mock.Action{}))
+	configs := []Config{}
+	r.NoError(v.UnmarshalTo(&struct {
+		Configs *[]Config `json:"configs"`
+	}{Configs: &configs}))
+	r.Len(configs, 2)
+	r.Equal("registry", configs[0].Name)
+	r.Equal("shared", configs[1].Name)
+}
+
+func TestWrite(t *testing.T) {
+	r := require.New(t)
+	def := &v1beta1.ComponentDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "config-endpoint",
+			Namespace: types.DefaultKubeVelaNS,
+			Labels:    map[string]string{definitionCatalog: types.VelaCoreConfig},
+		},
+		Spec: v1beta1.ComponentDefinitionSpec{Schematic: &common.Schematic{CUE: &common.CUE{Template: `
+output: {
+	apiVersion: "v1"
+	kind:       "Secret"
+	stringData: url: parameter.url
+}
+parameter: {
+	url:     string
+	timeout: *30 | int
+}
+`}}},
+	}
+	notConfig := &v1beta1.ComponentDefinition{ObjectMeta: metav1.ObjectMeta{Name: "webservice", Namespace: types.DefaultKubeVelaNS}}
+	objs := []client.Object{def, notConfig}
+	objs = append(objs, newConfig("other", "config-endpoint", "p2", `{"url":"x"}`, nil)...)
+	objs = append(objs, newConfig("shared", "config-endpoint", "", `{"url":"x"}`, nil)...)
+	objs = append(objs, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "addon-config", Namespace: types.DefaultKubeVelaNS}})
+	p := newProvider(objs...)
+	write := func(params string) error {
+		v, err := value.NewValue(params, nil, "")
+		r.NoError(err)
+		return p.Write(nil, v, &mock.Action{})
+	}
+
+	r.NoError(write(`name: "endpoint", type: "config-endpoint", properties: url: "https://a.example.com", alias: "Endpoint"`))
+	app := &v1beta1.Application{}
+	r.NoError(p.cli.Get(context.Background(), client.ObjectKey{Namespace: types.DefaultKubeVelaNS, Name: "endpoint"}, app))
+	r.Equal("p1", app.Labels[types.LabelConfigProject])
+	r.Equal("config-endpoint", app.Labels[types.LabelConfigType])
+	r.Equal(types.VelaCoreConfig, app.Labels[types.LabelConfigCatalog])
+	r.Equal("Endpoint", app.Annotations[types.AnnotationConfigAlias])
+	r.Equal(`{"url":"https://a.example.com"}`, string(app.Spec.Components[0].Properties.Raw))
+
+	r.NoError(write(`name: "endpoint", type: "config-endpoint", properties: url: "https://b.example.com"`))
+	r.NoError(p.cli.Get(context.Background(), client.ObjectKey{Namespace: types.DefaultKubeVelaNS, Name: "endpoint"}, app))
+	r.Equal(`{"url":"https://b.example.com"}`, string(app.Spec.Components[0].Properties.Raw))
+
+	// the properties are validated with the parameters of the config type
+	r.Error(write(`name: "endpoint", type: "config-endpoint", properties: {}`))
+	r.Error(write(`name: "endpoint", type: "config-endpoint", properties: {url: "x", timeout: "30s"}`))
+	r.Error(write(`name: "web", type: "webservice", properties: {}`))
+	r.Error(write(`name: "endpoint", type: "not-exist", properties: {}`))
+	// the configs of the other projects and the shared ones are not overwritten
+	r.Error(write(`name: "other", type: "config-endpoint", properties: url: "y"`))
+	r.Error(write(`name: "shared", type: "config-endpoint", properties: url: "y"`))
+	r.Error(write(`name: "addon-config", type: "config-endpoint", properties: url: "y"`))
+
+	// the application out of any project can't write the configs
+	ns := &corev1.Namespace{}
+	r.NoError(p.cli.Get(context.Background(), client.ObjectKey{Name: "default"}, ns))
+	ns.Labels = nil
+	r.NoError(p.cli.Update(context.Background(), ns))
+	err := write(`name: "new", type: "config-endpoint", properties: url: "y"`)
+	r.Error(err)
+	r.Contains(err.Error(), "not bound to any project")
+}
@@ -0,0 +1,632 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/format/index"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	githttp "gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+	gitssh "gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/auth"
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers"
+	wfTypes "github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+const (
+	// ProviderName is provider name for install.
+	ProviderName = "git"
+)
+
+const (
+	// SecretKeyUsername is the key of the username in the credential secret
+	SecretKeyUsername = corev1.BasicAuthUsernameKey
+	// SecretKeyPassword is the key of the password or the access token in the credential secret
+	SecretKeyPassword = corev1.BasicAuthPasswordKey
+	// SecretKeySSHPrivateKey is the key of the ssh private key in the credential secret
+	SecretKeySSHPrivateKey = corev1.SSHAuthPrivateKey
+	// SecretKeyKnownHosts is the key of the ssh known hosts in the credential secret
+	SecretKeyKnownHosts = "known_hosts"
+)
+
+var (
+	// WorkDir is the directory to keep the local repositories
+	WorkDir = filepath.Join(os.TempDir(), "kubevela-git")
+	// CommandTimeout is the timeout of fetching from or pushing to the remote
+	CommandTimeout = 2 * time.Minute
+	// RepoExpiration is the duration to keep the local repositories since they're cloned at last, the expired ones
+	// are removed from WorkDir when cloning
+	RepoExpiration = 24 * time.Hour
+)
+
+// workDirLock serializes the pruning and the cloning of the local repositories in WorkDir
+var workDirLock sync.Mutex
+
+type provider struct {
+	cli     client.Client
+	app     *v1beta1.Application
+	workDir string
+}
+
+// SecretRef refers to the secret that contains the credentials of the repository
+type SecretRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// CloneParams is the parameters of cloning the repository
+type CloneParams struct {
+	URL       string     `json:"url"`
+	Branch    string     `json:"branch"`
+	SecretRef *SecretRef `json:"secretRef,omitempty"`
+}
+
+// File is the file to write into the repository, the content is either the raw content or
+// the values encoded as the yaml documents
+type File struct {
+	Name    string        `json:"name"`
+	Content *string       `json:"content,omitempty"`
+	Values  []interface{} `json:"values,omitempty"`
+}
+
+// WriteFilesParams is the parameters of writing files into the repository
+type WriteFilesParams struct {
+	Path  string `json:"path"`
+	Dir   string `json:"dir"`
+	Clean bool   `json:"clean"`
+	Files []File `json:"files"`
+}
+
+// Author is the author of the commit
+type Author struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// CommitParams is the parameters of committing the changes
+type CommitParams struct {
+	Path    string                 `json:"path"`
+	Message string                 `json:"message"`
+	Author  Author                 `json:"author"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+}
+
+// PushParams is the parameters of pushing the commits
+type PushParams struct {
+	Path      string     `json:"path"`
+	Branch    string     `json:"branch"`
+	SecretRef *SecretRef `json:"secretRef,omitempty"`
+}
+
+// CommitMessageData is the data to render the commit message template
+type CommitMessageData struct {
+	AppName   string
+	Namespace string
+	Files     []string
+	Data      map[string]interface{}
+}
+
+// Clone clones the repository into the local directory, or fetches and resets the existing one to the
+// latest commit of the branch. The branch is created from scratch if it doesn't exist in the remote.
+func (p *provider) Clone(ctx wfContext.Context, v *value.Value, act wfTypes.Action) error {
+	params := &CloneParams{}
+	if err := v.UnmarshalTo(params); err != nil {
+		return err
+	}
+	if params.URL == "" || params.Branch == "" {
+		return errors.New("url and branch of the repository must be set")
+	}
+	credentials, err := p.loadAuth(params.SecretRef, params.URL)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(p.workDir, p.repoID(params.URL, params.Branch))
+	repo, err := p.openRepo(dir, params.URL)
+	if err != nil {
+		return err
+	}
+	remote, err := repo.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return err
+	}
+	refs, err := remote.List(&git.ListOptions{Auth: credentials})
+	if err != nil && !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return errors.WithMessagef(err, "failed to list branches of %s", params.URL)
+	}
+	branch := plumbing.NewBranchReferenceName(params.Branch)
+	var exists bool
+	for _, ref := range refs {
+		if ref.Name() == branch {
+			exists = true
+		}
+	}
+	if err := repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, branch)); err != nil {
+		return err
+	}
+	if err := cleanDir(dir, dir); err != nil {
+		return err
+	}
+	if !exists {
+		// start an empty branch
+		if err := repo.Storer.RemoveReference(branch); err != nil {
+			return err
+		}
+		if err := repo.Storer.SetIndex(&index.Index{Version: 2}); err != nil {
+			return err
+		}
+	} else {
+		remoteRef := plumbing.NewRemoteReferenceName(git.DefaultRemoteName, params.Branch)
+		fetchCtx, cancel := context.WithTimeout(context.Background(), CommandTimeout)
+		defer cancel()
+		err := repo.FetchContext(fetchCtx, &git.FetchOptions{
+			RefSpecs: []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", branch, remoteRef))},
+			Auth:     credentials,
+			Tags:     git.NoTags,
+			Force:    true,
+		})
+		if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+			return errors.WithMessagef(err, "failed to fetch %s", params.URL)
+		}
+		ref, err := repo.Reference(remoteRef, true)
+		if err != nil {
+			return err
+		}
+		worktree, err := repo.Worktree()
+		if err != nil {
+			return err
+		}
+		// the files are removed by cleanDir, the hard reset checks out all of them from the commit
+		if err := worktree.Reset(&git.ResetOptions{Commit: ref.Hash(), Mode: git.HardReset}); err != nil {
+			return err
+		}
+	}
+	if err := v.FillObject(dir, "path"); err != nil {
+		return err
+	}
+	return v.FillObject(headSHA(repo), "sha")
+}
+
+// WriteFiles writes the files into the directory of the local repository.
+func (p *provider) WriteFiles(ctx wfContext.Context, v *value.Value, act wfTypes.Action) error {
+	params := &WriteFilesParams{}
+	if err := v.UnmarshalTo(params); err != nil {
+		return err
+	}
+	repo, err := p.checkRepoPath(params.Path)
+	if err != nil {
+		return err
+	}
+	dir, err := subPath(repo, params.Dir)
+	if err != nil {
+		return err
+	}
+	if params.Clean {
+		if params.Dir == "" {
+			return errors.New(`dir must be set explicitly to clean, use "." to clean the whole repository`)
+		}
+		if err := cleanDir(repo, dir); err != nil {
+			return err
+		}
+	}
+	for _, f := range params.Files {
+		path, err := subPath(dir, f.Name)
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return errors.New("the name of the file must be set")
+		}
+		content, err := f.encode()
+		if err != nil {
+			return errors.WithMessagef(err, "failed to encode file %s", f.Name)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, content, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Commit commits all the changes in the local repository with the message rendered from the template,
+// nothing is committed if there is no change.
+func (p *provider) Commit(ctx wfContext.Context, v *value.Value, act wfTypes.Action) error {
+	params := &CommitParams{}
+	if err := v.UnmarshalTo(params); err != nil {
+		return err
+	}
+	dir, err := p.checkRepoPath(params.Path)
+	if err != nil {
+		return err
+	}
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return err
+	}
+	files, err := stageAll(repo)
+	if err != nil {
+		return err
+	}
+	changed := len(files) > 0
+	if changed {
+		message, err := renderMessage(params.Message, CommitMessageData{
+			AppName:   p.app.Name,
+			Namespace: p.app.Namespace,
+			Files:     files,
+			Data:      params.Data,
+		})
+		if err != nil {
+			return err
+		}
+		worktree, err := repo.Worktree()
+		if err != nil {
+			return err
+		}
+		if _, err := worktree.Commit(message, &git.CommitOptions{
+			Author: &object.Signature{Name: params.Author.Name, Email: params.Author.Email, When: time.Now()},
+		}); err != nil {
+			return errors.Wrap(err, "failed to commit")
+		}
+	}
+	if err := v.FillObject(changed, "changed"); err != nil {
+		return err
+	}
+	return v.FillObject(headSHA(repo), "sha")
+}
+
+// Push pushes the commits of the local repository to the branch of the remote.
+func (p *provider) Push(ctx wfContext.Context, v *value.Value, act wfTypes.Action) error {
+	params := &PushParams{}
+	if err := v.UnmarshalTo(params); err != nil {
+		return err
+	}
+	dir, err := p.checkRepoPath(params.Path)
+	if err != nil {
+		return err
+	}
+	if params.Branch == "" {
+		return errors.New("branch to push must be set")
+	}
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return errors.New("nothing to push, the repository has no commit")
+	}
+	remote, err := repo.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return err
+	}
+	credentials, err := p.loadAuth(params.SecretRef, remote.Config().URLs[0])
+	if err != nil {
+		return err
+	}
+	pushCtx, cancel := context.WithTimeout(context.Background(), CommandTimeout)
+	defer cancel()
+	err = repo.PushContext(pushCtx, &git.PushOptions{
+		RefSpecs: []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", head.Name(), plumbing.NewBranchReferenceName(params.Branch)))},
+		Auth:     credentials,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return errors.WithMessagef(err, "failed to push to branch %s", params.Branch)
+	}
+	return v.FillObject(head.Hash().String(), "sha")
+}
+
+// repoID identifies the local repository by the application, the url and the branch of the remote
+func (p *provider) repoID(url, branch string) string {
+	h := sha256.Sum256([]byte(strings.Join([]string{p.app.Namespace, p.app.Name, url, branch}, "/")))
+	return hex.EncodeToString(h[:])[:16]
+}
+
+// checkRepoPath makes sure the path is a local repository cloned by the provider
+func (p *provider) checkRepoPath(path string) (string, error) {
+	if path == "" {
+		return "", errors.New("path of the repository must be set")
+	}
+	rel, err := filepath.Rel(p.workDir, filepath.Clean(path))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") || strings.Contains(rel, string(filepath.Separator)) {
+		return "", errors.Errorf("%s is not a repository cloned by the git provider", path)
+	}
+	if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+		return "", errors.Errorf("%s is not a repository cloned by the git provider", path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// openRepo prunes the expired local repositories, and opens the one in the directory with its modification time
+// refreshed, so it's kept for RepoExpiration since cloned
+func (p *provider) openRepo(dir, url string) (*git.Repository, error) {
+	workDirLock.Lock()
+	defer workDirLock.Unlock()
+	p.pruneRepos(dir)
+	repo, err := openRepo(dir, url)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := os.Chtimes(dir, now, now); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// pruneRepos removes the local repositories not cloned within RepoExpiration except the one to keep
+func (p *provider) pruneRepos(keep string) {
+	entries, err := os.ReadDir(p.workDir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		path := filepath.Join(p.workDir, e.Name())
+		if path == keep || !e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || time.Since(info.ModTime()) < RepoExpiration {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			klog.Warningf("failed to remove the expired git repository %s: %s", path, err.Error())
+		}
+	}
+}
+
+// subPath joins the relative path to the base directory, the path must not escape from the base directory
+func subPath(base, rel string) (string, error) {
+	path := filepath.Join(base, filepath.Clean("/"+rel))
+	if r, err := filepath.Rel(base, path); err != nil || strings.HasPrefix(r, "..") || r == ".git" || strings.HasPrefix(r, ".git"+string(filepath.Separator)) {
+		return "", errors.Errorf("invalid path %s", rel)
+	}
+	return path, nil
+}
+
+// cleanDir removes all the files in the directory of the repository except the .git directory
+func cleanDir(repo, dir string) error {
+	if dir != repo {
+		return os.RemoveAll(dir)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.Name() == ".git" {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f File) encode() ([]byte, error) {
+	if f.Content != nil {
+		return []byte(*f.Content), nil
+	}
+	var docs [][]byte
+	for _, v := range f.Values {
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, b)
+	}
+	return bytes.Join(docs, []byte("---\n")), nil
+}
+
+func renderMessage(message string, data CommitMessageData) (string, error) {
+	tmpl, err := template.New("message").Option("missingkey=zero").Funcs(template.FuncMap{"join": strings.Join}).Parse(message)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid commit message template")
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return "", errors.Wrap(err, "failed to render commit message")
+	}
+	if strings.TrimSpace(buf.String()) == "" {
+		return "", errors.New("commit message must not be empty")
+	}
+	return buf.String(), nil
+}
+
+// loadAuth returns the authentication of the remote with the credentials in the secret, the credentials are never
+// persisted into the local repository. Without the impersonation of the application, the secret must be in the
+// namespace of the application.
+func (p *provider) loadAuth(ref *SecretRef, url string) (transport.AuthMethod, error) {
+	if ref == nil || ref.Name == "" {
+		return nil, nil
+	}
+	key := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+	if key.Namespace == "" {
+		key.Namespace = p.app.Namespace
+	}
+	readCtx := auth.ContextWithUserInfo(context.Background(), p.app)
+	if key.Namespace != p.app.Namespace {
+		if userInfo, ok := request.UserFrom(readCtx); !ok || userInfo.GetName() == "" {
+			return nil, errors.Errorf("cannot read secret %s outside the namespace of the application %s", key, p.app.Namespace)
+		}
+	}
+	secret := &corev1.Secret{}
+	if err := p.cli.Get(readCtx, key, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to get the credentials of the repository from secret %s", key)
+	}
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid url of the repository")
+	}
+	if endpoint.Protocol != "ssh" {
+		password, ok := secret.Data[SecretKeyPassword]
+		if !ok {
+			return nil, nil
+		}
+		username := string(secret.Data[SecretKeyUsername])
+		if username == "" {
+			username = "git"
+		}
+		return &githttp.BasicAuth{Username: username, Password: string(password)}, nil
+	}
+	privateKey, ok := secret.Data[SecretKeySSHPrivateKey]
+	if !ok {
+		return nil, nil
+	}
+	user := endpoint.User
+	if user == "" {
+		user = "git"
+	}
+	keys, err := gitssh.NewPublicKeys(user, privateKey, "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid ssh private key in secret %s", key)
+	}
+	if knownHosts, ok := secret.Data[SecretKeyKnownHosts]; ok {
+		if keys.HostKeyCallback, err = newKnownHostsCallback(knownHosts); err != nil {
+			return nil, errors.Wrapf(err, "invalid known hosts in secret %s", key)
+		}
+		return keys, nil
+	}
+	// the host keys are never trusted on the first use, the known hosts configured in the controller are used
+	// if they're not given in the secret
+	if keys.HostKeyCallback, err = gitssh.NewKnownHostsCallback(); err != nil {
+		return nil, errors.Wrapf(err, "the host keys of the repository are unknown, set %s in secret %s or SSH_KNOWN_HOSTS of the controller", SecretKeyKnownHosts, key)
+	}
+	return keys, nil
+}
+
+func newKnownHostsCallback(knownHosts []byte) (ssh.HostKeyCallback, error) {
+	f, err := os.CreateTemp("", "kubevela-git-known-hosts-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	_, err = f.Write(knownHosts)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	// the known hosts are loaded into the memory, the file can be removed
+	return gitssh.NewKnownHostsCallback(f.Name())
+}
+
+// openRepo opens the local repository in the directory or initializes a new one, the url of the origin remote is
+// updated if changed
+func openRepo(dir string, url string) (*git.Repository, error) {
+	repo, err := git.PlainOpen(dir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return nil, err
+		}
+		repo, err = git.PlainInit(dir, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return nil, err
+	}
+	if remote, ok := cfg.Remotes[git.DefaultRemoteName]; ok && len(remote.URLs) == 1 && remote.URLs[0] == url {
+		return repo, nil
+	}
+	cfg.Remotes[git.DefaultRemoteName] = &config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{url}}
+	if err := repo.Storer.SetConfig(cfg); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// stageAll stages all the changes in the worktree, the names of the changed files are returned in order
+func stageAll(repo *git.Repository) ([]string, error) {
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := worktree.Add("."); err != nil {
+		return nil, err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for file, s := range status {
+		if s.Worktree == git.Deleted {
+			if _, err := worktree.Remove(file); err != nil {
+				return nil, err
+			}
+		} else if s.Staging == git.Unmodified || s.Staging == git.Untracked {
+			continue
+		}
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func headSHA(repo *git.Repository) string {
+	head, err := repo.Head()
+	if err != nil {
+		return ""
+	}
+	return head.Hash().String()
+}
+
+// Install register handlers to provider discover.
+func Install(p providers.Providers, cli client.Client, app *v1beta1.Application) {
+	prd := &provider{cli: cli, app: app, workDir: WorkDir}
+	p.Register(ProviderName, map[string]providers.Handler{
+		"clone":  prd.Clone,
+		"write":  prd.WriteFiles,
+		"commit": prd.Commit,
+		"push":   prd.Push,
+	})
+}
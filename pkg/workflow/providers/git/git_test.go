@@ -0,0 +1,271 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/client"
+	githttp "gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/server"
+	gitssh "gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers/mock"
+)
+
+func init() {
+	// serve the local repositories in process, so that the tests don't depend on the git binary
+	client.InstallProtocol("file", server.DefaultServer)
+}
+
+func TestProvider(t *testing.T) {
+	r := require.New(t)
+	tmp := t.TempDir()
+	remote := filepath.Join(tmp, "remote.git")
+	remoteRepo, err := git.PlainInit(remote, true)
+	r.NoError(err)
+
+	cli := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "git-creds", Namespace: "default"},
+		Data: map[string][]byte{
+			SecretKeyUsername: []byte("admin"),
+			SecretKeyPassword: []byte("token"),
+		},
+	}).Build()
+	p := &provider{
+		cli:     cli,
+		app:     &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}},
+		workDir: filepath.Join(tmp, "work"),
+	}
+
+	// the first commit creates the branch
+	path, sha := clone(r, p, remote, "")
+	r.Equal("", sha)
+	v, err := value.NewValue(fmt.Sprintf(`
+path: "%s"
+dir: "apps"
+files: [{
+	name: "app.yaml"
+	values: [{kind: "Deployment", metadata: name: "a"}, {kind: "Service", metadata: name: "a"}]
+}, {
+	name: "README.md"
+	content: "hello"
+}]
+`, path), nil, "")
+	r.NoError(err)
+	r.NoError(p.WriteFiles(nil, v, &mock.Action{}))
+	sha, changed := commit(r, p, path)
+	r.True(changed)
+	r.NotEqual("", sha)
+	v, err = value.NewValue(fmt.Sprintf(`path: "%s", branch: "deploy", secretRef: name: "git-creds"`, path), nil, "")
+	r.NoError(err)
+	r.NoError(p.Push(nil, v, &mock.Action{}))
+	pushed, err := v.GetString("sha")
+	r.NoError(err)
+	r.Equal(sha, pushed)
+	remoteRef, err := remoteRepo.Reference(plumbing.NewBranchReferenceName("deploy"), true)
+	r.NoError(err)
+	r.Equal(sha, remoteRef.Hash().String())
+	remoteCommit, err := remoteRepo.CommitObject(remoteRef.Hash())
+	r.NoError(err)
+	r.Equal("update default/app: apps/README.md,apps/app.yaml", remoteCommit.Message)
+
+	// clone again resets to the remote branch, nothing changed if the files are the same
+	r.NoError(os.WriteFile(filepath.Join(path, "dirty"), []byte("x"), 0600))
+	path, sha = clone(r, p, remote, "git-creds")
+	r.Equal(remoteRef.Hash().String(), sha)
+	_, err = os.Stat(filepath.Join(path, "dirty"))
+	r.True(os.IsNotExist(err))
+	content, err := os.ReadFile(filepath.Join(path, "apps", "app.yaml"))
+	r.NoError(err)
+	r.Equal("kind: Deployment\nmetadata:\n  name: a\n---\nkind: Service\nmetadata:\n  name: a\n", string(content))
+	v, err = value.NewValue(fmt.Sprintf(`path: "%s", dir: "apps", clean: true, files: [{name: "app.yaml", content: "kind: Deployment\nmetadata:\n  name: a\n---\nkind: Service\nmetadata:\n  name: a\n"}, {name: "README.md", content: "hello"}]`, path), nil, "")
+	r.NoError(err)
+	r.NoError(p.WriteFiles(nil, v, &mock.Action{}))
+	_, changed = commit(r, p, path)
+	r.False(changed)
+
+	// clean removes the files not written any more
+	v, err = value.NewValue(fmt.Sprintf(`path: "%s", dir: "apps", clean: true, files: [{name: "other.yaml", content: "kind: ConfigMap"}]`, path), nil, "")
+	r.NoError(err)
+	r.NoError(p.WriteFiles(nil, v, &mock.Action{}))
+	_, err = os.Stat(filepath.Join(path, "apps", "app.yaml"))
+	r.True(os.IsNotExist(err))
+	_, changed = commit(r, p, path)
+	r.True(changed)
+
+	// the whole repository is cleaned only if the dir is set explicitly
+	v, err = value.NewValue(fmt.Sprintf(`path: "%s", clean: true, files: [{name: "other.yaml", content: "kind: ConfigMap"}]`, path), nil, "")
+	r.NoError(err)
+	r.Error(p.WriteFiles(nil, v, &mock.Action{}))
+	_, err = os.Stat(filepath.Join(path, "apps", "other.yaml"))
+	r.NoError(err)
+	v, err = value.NewValue(fmt.Sprintf(`path: "%s", dir: ".", clean: true, files: [{name: "other.yaml", content: "kind: ConfigMap"}]`, path), nil, "")
+	r.NoError(err)
+	r.NoError(p.WriteFiles(nil, v, &mock.Action{}))
+	_, err = os.Stat(filepath.Join(path, "apps"))
+	r.True(os.IsNotExist(err))
+
+	// the expired local repositories are removed when cloning
+	expired := filepath.Join(p.workDir, "expired")
+	r.NoError(os.MkdirAll(filepath.Join(expired, ".git"), 0750))
+	outdated := time.Now().Add(-RepoExpiration - time.Minute)
+	r.NoError(os.Chtimes(expired, outdated, outdated))
+	r.NoError(os.Chtimes(path, outdated, outdated))
+	path, _ = clone(r, p, remote, "")
+	_, err = os.Stat(expired)
+	r.True(os.IsNotExist(err))
+	info, err := os.Stat(path)
+	r.NoError(err)
+	r.True(time.Since(info.ModTime()) < RepoExpiration)
+
+	// invalid paths
+	for _, params := range []string{
+		fmt.Sprintf(`path: "%s", files: [{name: "../../escape", content: "x"}]`, tmp),
+		fmt.Sprintf(`path: "%s", files: [{name: ".git/config", content: "x"}]`, path),
+		fmt.Sprintf(`path: "%s", dir: ".git", files: [{name: "x", content: "x"}]`, path),
+	} {
+		v, err = value.NewValue(params, nil, "")
+		r.NoError(err)
+		r.Error(p.WriteFiles(nil, v, &mock.Action{}), params)
+	}
+	_, err = os.Stat(filepath.Join(tmp, "escape"))
+	r.True(os.IsNotExist(err))
+
+	// secret not found
+	v, err = value.NewValue(fmt.Sprintf(`url: "%s", branch: "deploy", secretRef: name: "not-exist"`, remote), nil, "")
+	r.NoError(err)
+	r.Error(p.Clone(nil, v, &mock.Action{}))
+}
+
+func TestLoadAuth(t *testing.T) {
+	r := require.New(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	r.NoError(err)
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	publicKey, err := ssh.NewPublicKey(&key.PublicKey)
+	r.NoError(err)
+	cli := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "basic", Namespace: "default"},
+		Data:       map[string][]byte{SecretKeyPassword: []byte("token")},
+	}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ssh", Namespace: "default"},
+		Data: map[string][]byte{
+			SecretKeySSHPrivateKey: privateKey,
+			SecretKeyKnownHosts:    []byte("github.com " + string(ssh.MarshalAuthorizedKey(publicKey))),
+		},
+	}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ssh-without-known-hosts", Namespace: "default"},
+		Data:       map[string][]byte{SecretKeySSHPrivateKey: privateKey},
+	}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "basic", Namespace: "vela-system"},
+		Data:       map[string][]byte{SecretKeyPassword: []byte("token")},
+	}).Build()
+	p := &provider{cli: cli, app: &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}}
+
+	a, err := p.loadAuth(&SecretRef{Name: "basic"}, "https://github.com/oam-dev/kubevela.git")
+	r.NoError(err)
+	r.Equal(&githttp.BasicAuth{Username: "git", Password: "token"}, a)
+
+	a, err = p.loadAuth(&SecretRef{Name: "ssh"}, "git@github.com:oam-dev/kubevela.git")
+	r.NoError(err)
+	keys, ok := a.(*gitssh.PublicKeys)
+	r.True(ok)
+	r.Equal("git", keys.User)
+	addr := &net.TCPAddr{IP: net.ParseIP("1.1.1.1"), Port: 22}
+	r.NoError(keys.HostKeyCallback("github.com:22", addr, publicKey))
+	r.Error(keys.HostKeyCallback("gitlab.com:22", addr, publicKey))
+
+	// the host key is never trusted on the first use, the known hosts of the controller are required
+	t.Setenv("SSH_KNOWN_HOSTS", filepath.Join(t.TempDir(), "not-exist"))
+	_, err = p.loadAuth(&SecretRef{Name: "ssh-without-known-hosts"}, "git@github.com:oam-dev/kubevela.git")
+	r.Error(err)
+	r.Contains(err.Error(), "host keys of the repository are unknown")
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	r.NoError(os.WriteFile(knownHosts, []byte("example.com "+string(ssh.MarshalAuthorizedKey(publicKey))), 0600))
+	t.Setenv("SSH_KNOWN_HOSTS", knownHosts)
+	a, err = p.loadAuth(&SecretRef{Name: "ssh-without-known-hosts"}, "git@example.com:oam-dev/kubevela.git")
+	r.NoError(err)
+	keys, ok = a.(*gitssh.PublicKeys)
+	r.True(ok)
+	r.NoError(keys.HostKeyCallback("example.com:22", addr, publicKey))
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	r.NoError(err)
+	otherKey, err := ssh.NewPublicKey(&other.PublicKey)
+	r.NoError(err)
+	r.Error(keys.HostKeyCallback("example.com:22", addr, otherKey))
+
+	// the secret outside the namespace of the application can't be read without impersonation
+	_, err = p.loadAuth(&SecretRef{Name: "basic", Namespace: "vela-system"}, "https://github.com/oam-dev/kubevela.git")
+	r.Error(err)
+	r.Contains(err.Error(), "outside the namespace of the application")
+	p.app.Annotations = map[string]string{oam.AnnotationApplicationServiceAccountName: "deployer"}
+	_, err = p.loadAuth(&SecretRef{Name: "basic", Namespace: "vela-system"}, "https://github.com/oam-dev/kubevela.git")
+	r.NoError(err)
+
+	_, err = p.loadAuth(&SecretRef{Name: "not-exist"}, "https://github.com/oam-dev/kubevela.git")
+	r.Error(err)
+}
+
+func clone(r *require.Assertions, p *provider, remote, secret string) (string, string) {
+	params := fmt.Sprintf(`url: "%s", branch: "deploy"`, remote)
+	if secret != "" {
+		params += fmt.Sprintf(`, secretRef: name: "%s"`, secret)
+	}
+	v, err := value.NewValue(params, nil, "")
+	r.NoError(err)
+	r.NoError(p.Clone(nil, v, &mock.Action{}))
+	path, err := v.GetString("path")
+	r.NoError(err)
+	sha, err := v.GetString("sha")
+	r.NoError(err)
+	return path, sha
+}
+
+func commit(r *require.Assertions, p *provider, path string) (string, bool) {
+	v, err := value.NewValue(fmt.Sprintf(`
+path: "%s"
+message: "update {{.Namespace}}/{{.AppName}}: {{join .Files \",\"}}"
+author: {name: "vela", email: "vela@example.com"}
+`, path), nil, "")
+	r.NoError(err)
+	r.NoError(p.Commit(nil, v, &mock.Action{}))
+	sha, err := v.GetString("sha")
+	r.NoError(err)
+	changed, err := v.GetBool("changed")
+	r.NoError(err)
+	return sha, changed
+}
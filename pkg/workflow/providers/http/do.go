@@ -19,9 +19,16 @@ package http
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
 	"strings"
+	"time"
 
 	"cuelang.org/go/cue"
+	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -36,13 +43,74 @@ import (
 const (
 	// ProviderName is provider name for install.
 	ProviderName = "http"
+
+	// MaxRetryAttempts is the max attempts of the request in one execution of the step, use the retry policy of the
+	// step for longer retries
+	MaxRetryAttempts = 5
+	// MaxRetryWaitTime is the max total time to wait between the attempts in one execution of the step, so the
+	// controller worker is not blocked for long
+	MaxRetryWaitTime = 30 * time.Second
 )
 
+// sleep is replaced in tests to skip the backoff
+var sleep = time.Sleep
+
 type provider struct {
 	cli client.Client
 	ns  string
 }
 
+// AuthParams is the parameters to authenticate the request with the credentials in secrets
+type AuthParams struct {
+	Bearer *BearerAuth `json:"bearer,omitempty"`
+	Basic  *BasicAuth  `json:"basic,omitempty"`
+}
+
+// BearerAuth reads the bearer token from the key of the secret
+type BearerAuth struct {
+	Secret string `json:"secret"`
+	Key    string `json:"key"`
+}
+
+// BasicAuth reads the username and password from the keys of the secret
+type BasicAuth struct {
+	Secret      string `json:"secret"`
+	UsernameKey string `json:"usernameKey"`
+	PasswordKey string `json:"passwordKey"`
+}
+
+// RetryParams is the parameters to retry the request
+type RetryParams struct {
+	Attempts    int    `json:"attempts"`
+	StatusCodes []int  `json:"statusCodes"`
+	Backoff     string `json:"backoff"`
+	MaxBackoff  string `json:"maxBackoff"`
+}
+
+// ExpectParams is the assertions on the response, the step fails if they are not satisfied
+type ExpectParams struct {
+	StatusCode StatusCodes `json:"statusCode,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// StatusCodes is a list of status codes, it can be unmarshalled from a single status code
+type StatusCodes []int
+
+// UnmarshalJSON unmarshal the status codes from a number or a list of numbers
+func (in *StatusCodes) UnmarshalJSON(b []byte) error {
+	var code int
+	if err := json.Unmarshal(b, &code); err == nil {
+		*in = StatusCodes{code}
+		return nil
+	}
+	var codes []int
+	if err := json.Unmarshal(b, &codes); err != nil {
+		return err
+	}
+	*in = codes
+	return nil
+}
+
 // Do process http request.
 func (h *provider) Do(ctx wfContext.Context, v *value.Value, act types.Action) error {
 	tlsConfig, err := v.LookupValue("tls_config")
@@ -51,18 +119,8 @@ func (h *provider) Do(ctx wfContext.Context, v *value.Value, act types.Action) e
 		if err != nil {
 			return err
 		}
-		objectKey := client.ObjectKey{
-			Namespace: h.ns,
-			Name:      secretName,
-		}
-		index := strings.Index(secretName, "/")
-		if index > 0 {
-			objectKey.Namespace = secretName[:index-1]
-			objectKey.Name = secretName[index:]
-		}
-
-		secret := new(v1.Secret)
-		if err := h.cli.Get(context.Background(), objectKey, secret); err != nil {
+		secret, err := h.getSecret(secretName)
+		if err != nil {
 			return err
 		}
 		if ca, ok := secret.Data["ca.crt"]; ok {
@@ -94,13 +152,233 @@ func (h *provider) Do(ctx wfContext.Context, v *value.Value, act types.Action) e
 			}
 		}
 	}
-	ret, err := builtin.RunTaskByKey("http", cue.Value{}, &registry.Meta{
-		Obj: v.CueValue(),
-	})
+
+	// the credentials are only filled into the request to send, not the step value
+	obj := v.CueValue()
+	authorization, err := h.authorization(v)
 	if err != nil {
 		return err
 	}
-	return v.FillObject(ret, "response")
+	if authorization != "" {
+		obj = obj.Fill(authorization, "request", "header", "Authorization")
+		if obj.Err() != nil {
+			return errors.WithMessage(obj.Err(), "fill authorization header")
+		}
+	}
+
+	retry, err := parseRetry(v)
+	if err != nil {
+		return err
+	}
+	ret, err := doWithRetry(obj, retry)
+	if err != nil {
+		return err
+	}
+	if body, ok := ret["body"].(string); ok {
+		if data, ok := decodeJSON(body, ret["header"]); ok {
+			ret["json"] = data
+		}
+	}
+	if err := v.FillObject(ret, "response"); err != nil {
+		return err
+	}
+
+	if ev, err := v.LookupValue("expect"); err == nil {
+		expect := &ExpectParams{}
+		if err := ev.UnmarshalTo(expect); err != nil {
+			return errors.WithMessage(err, "invalid expect")
+		}
+		msg, err := expect.check(ret)
+		if err != nil {
+			return err
+		}
+		if msg != "" {
+			act.Fail(msg)
+		}
+	}
+	return nil
+}
+
+// getSecret reads the secret in the namespace of the application, the secret can be referred by name or by
+// namespace/name. The secrets in the other namespaces are not allowed, since the credentials are sent to the urls
+// chosen by the application.
+func (h *provider) getSecret(name string) (*v1.Secret, error) {
+	objectKey := client.ObjectKey{
+		Namespace: h.ns,
+		Name:      name,
+	}
+	if index := strings.Index(name, "/"); index > 0 {
+		if name[:index] != h.ns {
+			return nil, errors.Errorf("secret %s is outside the namespace %s of the application", name, h.ns)
+		}
+		objectKey.Name = name[index+1:]
+	}
+	secret := new(v1.Secret)
+	if err := h.cli.Get(context.Background(), objectKey, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// authorization returns the value of the Authorization header from the auth parameters
+func (h *provider) authorization(v *value.Value) (string, error) {
+	av, err := v.LookupValue("auth")
+	if err != nil {
+		return "", nil
+	}
+	params := &AuthParams{}
+	if err := av.UnmarshalTo(params); err != nil {
+		return "", errors.WithMessage(err, "invalid auth")
+	}
+	readKey := func(secret *v1.Secret, key string) (string, error) {
+		data, ok := secret.Data[key]
+		if !ok {
+			return "", errors.Errorf("key %s not found in secret %s", key, secret.Name)
+		}
+		return string(data), nil
+	}
+	switch {
+	case params.Bearer != nil:
+		secret, err := h.getSecret(params.Bearer.Secret)
+		if err != nil {
+			return "", err
+		}
+		token, err := readKey(secret, params.Bearer.Key)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + strings.TrimSpace(token), nil
+	case params.Basic != nil:
+		secret, err := h.getSecret(params.Basic.Secret)
+		if err != nil {
+			return "", err
+		}
+		username, err := readKey(secret, params.Basic.UsernameKey)
+		if err != nil {
+			return "", err
+		}
+		password, err := readKey(secret, params.Basic.PasswordKey)
+		if err != nil {
+			return "", err
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)), nil
+	default:
+		return "", nil
+	}
+}
+
+func parseRetry(v *value.Value) (*RetryParams, error) {
+	rv, err := v.LookupValue("retry")
+	if err != nil {
+		return &RetryParams{Attempts: 1}, nil
+	}
+	retry := &RetryParams{}
+	if err := rv.UnmarshalTo(retry); err != nil {
+		return nil, errors.WithMessage(err, "invalid retry")
+	}
+	if retry.Attempts < 1 {
+		retry.Attempts = 1
+	}
+	if retry.Attempts > MaxRetryAttempts {
+		retry.Attempts = MaxRetryAttempts
+	}
+	return retry, nil
+}
+
+// doWithRetry sends the request, and retries it with the exponential backoff if it fails to connect
+// or responds the retryable status codes. The total wait time is limited by MaxRetryWaitTime.
+func doWithRetry(obj cue.Value, retry *RetryParams) (map[string]interface{}, error) {
+	backoff, maxBackoff := time.Second, 30*time.Second
+	var err error
+	if retry.Backoff != "" {
+		if backoff, err = time.ParseDuration(retry.Backoff); err != nil {
+			return nil, errors.Wrap(err, "invalid retry backoff")
+		}
+	}
+	if retry.MaxBackoff != "" {
+		if maxBackoff, err = time.ParseDuration(retry.MaxBackoff); err != nil {
+			return nil, errors.Wrap(err, "invalid retry maxBackoff")
+		}
+	}
+	if maxBackoff > MaxRetryWaitTime {
+		maxBackoff = MaxRetryWaitTime
+	}
+	var waited time.Duration
+	for attempt := 1; ; attempt++ {
+		got, err := builtin.RunTaskByKey("http", cue.Value{}, &registry.Meta{
+			Obj: obj,
+		})
+		var ret map[string]interface{}
+		if err == nil {
+			var ok bool
+			if ret, ok = got.(map[string]interface{}); !ok {
+				return nil, errors.New("invalid response")
+			}
+		}
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+		retryable := isRetryableError(err) || (err == nil && containsCode(retry.StatusCodes, ret["statusCode"]))
+		if !retryable || attempt >= retry.Attempts || waited+backoff > MaxRetryWaitTime {
+			return ret, err
+		}
+		sleep(backoff)
+		waited += backoff
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// isRetryableError checks if the request fails to connect or times out, the invalid requests are not retried
+func isRetryableError(err error) bool {
+	var netErr net.Error
+	return err != nil && errors.As(err, &netErr)
+}
+
+func containsCode(codes []int, code interface{}) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeJSON decodes the body if the response is in json format
+func decodeJSON(body string, header interface{}) (interface{}, bool) {
+	isJSON := false
+	if h, ok := header.(http.Header); ok {
+		isJSON = strings.Contains(h.Get("Content-Type"), "json")
+	}
+	trimmed := strings.TrimSpace(body)
+	if !isJSON && !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return nil, false
+	}
+	var data interface{}
+	if err := json.Unmarshal([]byte(trimmed), &data); err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// check returns the message of the failed assertions
+func (in *ExpectParams) check(ret map[string]interface{}) (string, error) {
+	code, _ := ret["statusCode"].(int)
+	if len(in.StatusCode) > 0 && !containsCode(in.StatusCode, code) {
+		return fmt.Sprintf("unexpected status code %d, expected %v", code, []int(in.StatusCode)), nil
+	}
+	if in.Body != "" {
+		re, err := regexp.Compile(in.Body)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid expected body %s", in.Body)
+		}
+		body, _ := ret["body"].(string)
+		if !re.MatchString(body) {
+			return fmt.Sprintf("response body of status code %d doesn't match %s", code, in.Body), nil
+		}
+	}
+	return "", nil
 }
 
 // Install register handlers to provider discover.
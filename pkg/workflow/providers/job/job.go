@@ -0,0 +1,415 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/hashstructure/v2"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/auth"
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/resourcekeeper"
+	"github.com/oam-dev/kubevela/pkg/velaql/providers/query"
+	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers/kube"
+	"github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+const (
+	// ProviderName is provider name for install.
+	ProviderName = "job"
+	// ContainerName is the name of the container that runs the task in the job
+	ContainerName = "job"
+
+	// messageLogLines is the number of log lines in the message of the failed step
+	messageLogLines = 10
+)
+
+const (
+	// StatusRunning means the job is not finished
+	StatusRunning = "running"
+	// StatusSucceeded means the job is completed
+	StatusSucceeded = "succeeded"
+	// StatusFailed means the job is failed
+	StatusFailed = "failed"
+)
+
+// LogReader reads the tail lines of the logs of the pod
+type LogReader func(ctx context.Context, namespace, pod string, tailLines int64) (string, error)
+
+type provider struct {
+	app      *v1beta1.Application
+	cli      client.Client
+	apply    kube.Dispatcher
+	readLogs LogReader
+}
+
+// RunParams is the parameters of the job to run
+type RunParams struct {
+	Cluster                 string          `json:"cluster"`
+	Name                    string          `json:"name"`
+	Namespace               string          `json:"namespace"`
+	Image                   string          `json:"image"`
+	Command                 []string        `json:"command,omitempty"`
+	Args                    []string        `json:"args,omitempty"`
+	Env                     []corev1.EnvVar `json:"env,omitempty"`
+	Secrets                 []string        `json:"secrets,omitempty"`
+	ServiceAccountName      string          `json:"serviceAccountName,omitempty"`
+	BackoffLimit            int32           `json:"backoffLimit"`
+	ActiveDeadlineSeconds   *int64          `json:"activeDeadlineSeconds,omitempty"`
+	TTLSecondsAfterFinished int32           `json:"ttlSecondsAfterFinished"`
+	LogTailLines            int64           `json:"logTailLines"`
+}
+
+// finishedJob is the result of the finished job recorded in the workflow context, so the job deleted by the ttl
+// controller is not recreated in the same run of the workflow
+type finishedJob struct {
+	SpecHash string  `json:"specHash"`
+	Status   string  `json:"status"`
+	ExitCode *int32  `json:"exitCode,omitempty"`
+	Logs     *string `json:"logs,omitempty"`
+	Message  string  `json:"message,omitempty"`
+}
+
+// Run creates the job and waits for it to finish, the logs and the exit code are collected into the outputs.
+// The job is recreated if the spec is changed, otherwise the result of the finished job is reused.
+func (p *provider) Run(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	params := &RunParams{}
+	if err := v.UnmarshalTo(params); err != nil {
+		return err
+	}
+	if params.Name == "" || params.Image == "" {
+		return errors.New("name and image of the job must be set")
+	}
+	if params.Namespace == "" {
+		params.Namespace = p.app.Namespace
+	}
+	deployCtx := multicluster.ContextWithClusterName(context.Background(), params.Cluster)
+	deployCtx = auth.ContextWithUserInfo(deployCtx, p.app)
+
+	desired, err := p.renderJob(params)
+	if err != nil {
+		return err
+	}
+	id := jobID(params)
+	if finished := loadFinishedJob(ctx, id); finished != nil && finished.SpecHash == desired.Annotations[oam.AnnotationJobSpecHash] {
+		return finished.fill(v, act)
+	}
+	existing, err := p.getJob(deployCtx, params.Namespace, params.Name)
+	if err != nil {
+		return err
+	}
+	switch {
+	case existing == nil:
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+		if err != nil {
+			return err
+		}
+		manifest := &unstructured.Unstructured{Object: obj}
+		manifest.SetAPIVersion("batch/v1")
+		manifest.SetKind("Job")
+		// the job is recorded meta-only, so it's not recreated by the state-keep after it's deleted by the ttl controller
+		applyCtx := resourcekeeper.ContextWithDispatchOptions(deployCtx, resourcekeeper.MetaOnlyOption{})
+		if err := p.apply(applyCtx, params.Cluster, common.WorkflowResourceCreator, manifest); err != nil {
+			return err
+		}
+		act.Wait(fmt.Sprintf("wait for job %s to complete", params.Name))
+		return v.FillObject(StatusRunning, "status")
+	case existing.Annotations[oam.AnnotationJobSpecHash] != desired.Annotations[oam.AnnotationJobSpecHash]:
+		if existing.DeletionTimestamp == nil {
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(batchv1.SchemeGroupVersion.WithKind("Job"))
+			obj.SetNamespace(params.Namespace)
+			obj.SetName(params.Name)
+			if err := p.cli.Delete(deployCtx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !kerrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to delete outdated job %s", params.Name)
+			}
+		}
+		act.Wait(fmt.Sprintf("wait for outdated job %s to be deleted", params.Name))
+		return v.FillObject(StatusRunning, "status")
+	default:
+	}
+
+	status := jobStatus(existing)
+	if status == StatusRunning {
+		act.Wait(fmt.Sprintf("wait for job %s to complete", params.Name))
+		return v.FillObject(StatusRunning, "status")
+	}
+	finished, err := p.collectResult(deployCtx, existing, params, status)
+	if err != nil {
+		return err
+	}
+	if err := saveFinishedJob(ctx, id, finished); err != nil {
+		return err
+	}
+	return finished.fill(v, act)
+}
+
+// collectResult collects the exit code and the logs of the latest pod of the finished job
+func (p *provider) collectResult(ctx context.Context, job *batchv1.Job, params *RunParams, status string) (*finishedJob, error) {
+	pod, err := p.latestPod(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	finished := &finishedJob{
+		SpecHash: job.Annotations[oam.AnnotationJobSpecHash],
+		Status:   status,
+	}
+	var logs string
+	if pod != nil {
+		if code, ok := exitCode(pod); ok {
+			finished.ExitCode = &code
+		}
+		if p.readLogs != nil {
+			if logs, err = p.readLogs(ctx, pod.Namespace, pod.Name, params.LogTailLines); err != nil {
+				logs = fmt.Sprintf("failed to collect logs: %s", err.Error())
+			}
+			logs = strings.ToValidUTF8(logs, "")
+			finished.Logs = &logs
+		}
+	}
+	if status == StatusFailed {
+		finished.Message = fmt.Sprintf("job %s failed", params.Name)
+		if finished.ExitCode != nil {
+			finished.Message += fmt.Sprintf(" with exit code %d", *finished.ExitCode)
+		}
+		if tail := lastLines(strings.TrimSpace(logs), messageLogLines); tail != "" {
+			finished.Message += ": " + tail
+		}
+	}
+	return finished, nil
+}
+
+func (f *finishedJob) fill(v *value.Value, act types.Action) error {
+	outputs := map[string]interface{}{"status": f.Status}
+	if f.ExitCode != nil {
+		outputs["exitCode"] = *f.ExitCode
+	}
+	if f.Logs != nil {
+		outputs["logs"] = *f.Logs
+	}
+	if err := v.FillObject(outputs); err != nil {
+		return err
+	}
+	if f.Status == StatusFailed {
+		act.Fail(f.Message)
+	}
+	return nil
+}
+
+func jobID(params *RunParams) string {
+	h := sha256.New()
+	for _, s := range []string{params.Cluster, params.Namespace, params.Name} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func loadFinishedJob(ctx wfContext.Context, id string) *finishedJob {
+	if ctx == nil {
+		return nil
+	}
+	data := ctx.GetMutableValue(types.ContextPrefixJob, id)
+	if data == "" {
+		return nil
+	}
+	finished := &finishedJob{}
+	if err := json.Unmarshal([]byte(data), finished); err != nil {
+		return nil
+	}
+	return finished
+}
+
+func saveFinishedJob(ctx wfContext.Context, id string, finished *finishedJob) error {
+	if ctx == nil {
+		return nil
+	}
+	data, err := json.Marshal(finished)
+	if err != nil {
+		return err
+	}
+	ctx.SetMutableValue(string(data), types.ContextPrefixJob, id)
+	return nil
+}
+
+func (p *provider) renderJob(params *RunParams) (*batchv1.Job, error) {
+	container := corev1.Container{
+		Name:    ContainerName,
+		Image:   params.Image,
+		Command: params.Command,
+		Args:    params.Args,
+		Env:     params.Env,
+	}
+	for _, secret := range params.Secrets {
+		container.EnvFrom = append(container.EnvFrom, corev1.EnvFromSource{
+			SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: secret}},
+		})
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: params.Name, Namespace: params.Namespace},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &params.BackoffLimit,
+			ActiveDeadlineSeconds:   params.ActiveDeadlineSeconds,
+			TTLSecondsAfterFinished: &params.TTLSecondsAfterFinished,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers:         []corev1.Container{container},
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: params.ServiceAccountName,
+				},
+			},
+		},
+	}
+	hash, err := hashstructure.Hash(job.Spec, hashstructure.FormatV2, nil)
+	if err != nil {
+		return nil, err
+	}
+	labels := map[string]string{
+		oam.LabelAppName:      p.app.Name,
+		oam.LabelAppNamespace: p.app.Namespace,
+	}
+	job.Labels = labels
+	job.Spec.Template.Labels = labels
+	job.Annotations = map[string]string{oam.AnnotationJobSpecHash: fmt.Sprintf("%x", hash)}
+	return job, nil
+}
+
+// getJob reads the job in unstructured to bypass the informer cache of the controller
+func (p *provider) getJob(ctx context.Context, namespace, name string) (*batchv1.Job, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(batchv1.SchemeGroupVersion.WithKind("Job"))
+	if err := p.cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get job %s", name)
+	}
+	job := &batchv1.Job{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// latestPod returns the pod of the job created at last, which is the last attempt of the retries
+func (p *provider) latestPod(ctx context.Context, job *batchv1.Job) (*corev1.Pod, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PodList"))
+	if err := p.cli.List(ctx, list, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list pods of job %s", job.Name)
+	}
+	var latest *corev1.Pod
+	for _, item := range list.Items {
+		pod := &corev1.Pod{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, pod); err != nil {
+			return nil, err
+		}
+		if latest == nil || latest.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			latest = pod
+		}
+	}
+	return latest, nil
+}
+
+func jobStatus(job *batchv1.Job) string {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return StatusSucceeded
+		case batchv1.JobFailed:
+			return StatusFailed
+		default:
+		}
+	}
+	return StatusRunning
+}
+
+func lastLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func exitCode(pod *corev1.Pod) (int32, bool) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == ContainerName && status.State.Terminated != nil {
+			return status.State.Terminated.ExitCode, true
+		}
+	}
+	return 0, false
+}
+
+// NewLogReader reads the logs with the clientset created from the rest config, the clientset is created once and
+// shared by the readers
+func NewLogReader(cfg *rest.Config) LogReader {
+	if cfg == nil {
+		return nil
+	}
+	clientSet, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to create kubernetes client")
+		return func(ctx context.Context, namespace, pod string, tailLines int64) (string, error) {
+			return "", err
+		}
+	}
+	return func(ctx context.Context, namespace, pod string, tailLines int64) (string, error) {
+		opts := &corev1.PodLogOptions{Container: ContainerName}
+		if tailLines > 0 {
+			opts.TailLines = &tailLines
+		}
+		buffer, err := query.ReadPodLogs(ctx, clientSet, namespace, pod, opts)
+		if buffer == nil {
+			return "", err
+		}
+		return buffer.String(), err
+	}
+}
+
+// Install register handlers to provider discover.
+func Install(p providers.Providers, app *v1beta1.Application, cli client.Client, readLogs LogReader, apply kube.Dispatcher) {
+	prd := &provider{app: app, cli: cli, apply: apply, readLogs: readLogs}
+	p.Register(ProviderName, map[string]providers.Handler{
+		"run": prd.Run,
+	})
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/resourcekeeper"
+	utilscommon "github.com/oam-dev/kubevela/pkg/utils/common"
+	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers/mock"
+)
+
+func TestRun(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().Build()
+	p := &provider{
+		app:   &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}},
+		cli:   cli,
+		apply: mock.NewDispatcher(cli),
+		readLogs: func(ctx context.Context, namespace, pod string, tailLines int64) (string, error) {
+			var lines []string
+			for i := 0; i < 12; i++ {
+				lines = append(lines, fmt.Sprintf("%s line %d", pod, i))
+			}
+			return strings.Join(lines, "\n"), nil
+		},
+	}
+	wfCtx, err := wfContext.NewContext(cli, "default", "app", "uid")
+	r.NoError(err)
+	run := func(image string) (*value.Value, *mock.Action) {
+		v, err := value.NewValue(fmt.Sprintf(`
+name: "migrate"
+image: "%s"
+command: ["sh", "-c", "migrate up"]
+env: [{name: "DB_HOST", value: "mysql"}]
+secrets: ["db-credentials"]
+backoffLimit: 0
+ttlSecondsAfterFinished: 600
+logTailLines: 50
+`, image), nil, "")
+		r.NoError(err)
+		act := &mock.Action{}
+		r.NoError(p.Run(wfCtx, v, act))
+		return v, act
+	}
+	getJob := func() *batchv1.Job {
+		job := &batchv1.Job{}
+		r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "migrate"}, job))
+		return job
+	}
+	finish := func(condition batchv1.JobConditionType, code int32) {
+		job := getJob()
+		job.Status.Conditions = []batchv1.JobCondition{{Type: condition, Status: corev1.ConditionTrue}}
+		r.NoError(cli.Status().Update(ctx, job))
+		for i, name := range []string{"migrate-old", "migrate-new"} {
+			r.NoError(cli.Create(ctx, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              name,
+					Namespace:         "default",
+					Labels:            map[string]string{"job-name": "migrate"},
+					CreationTimestamp: metav1.NewTime(time.Now().Add(time.Duration(i) * time.Minute)),
+				},
+				Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  ContainerName,
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: code}},
+				}}},
+			}))
+		}
+	}
+
+	// the job is created and the step waits for it
+	v, act := run("migrate:v1")
+	r.Equal("Wait", act.Phase)
+	status, err := v.GetString("status")
+	r.NoError(err)
+	r.Equal(StatusRunning, status)
+	job := getJob()
+	r.Equal("app", job.Labels[oam.LabelAppName])
+	r.NotEmpty(job.Annotations[oam.AnnotationJobSpecHash])
+	container := job.Spec.Template.Spec.Containers[0]
+	r.Equal("migrate:v1", container.Image)
+	r.Equal("db-credentials", container.EnvFrom[0].SecretRef.Name)
+	r.Equal(corev1.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+	r.Equal(int32(600), *job.Spec.TTLSecondsAfterFinished)
+	_, act = run("migrate:v1")
+	r.Equal("Wait", act.Phase)
+
+	// the result of the latest pod is collected
+	finish(batchv1.JobComplete, 0)
+	v, act = run("migrate:v1")
+	r.Equal("", act.Phase)
+	status, err = v.GetString("status")
+	r.NoError(err)
+	r.Equal(StatusSucceeded, status)
+	code, err := v.LookupValue("exitCode")
+	r.NoError(err)
+	r.Equal("0", fmt.Sprint(code.CueValue()))
+	logs, err := v.GetString("logs")
+	r.NoError(err)
+	r.True(strings.HasPrefix(logs, "migrate-new line 0"))
+
+	// the job is recreated if the spec is changed
+	_, act = run("migrate:v2")
+	r.Equal("Wait", act.Phase)
+	r.True(kerrors.IsNotFound(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "migrate"}, &batchv1.Job{})))
+	r.NoError(cli.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace("default")))
+	_, act = run("migrate:v2")
+	r.Equal("Wait", act.Phase)
+	r.Equal("migrate:v2", getJob().Spec.Template.Spec.Containers[0].Image)
+
+	// the step fails with the tail of the logs
+	finish(batchv1.JobFailed, 2)
+	v, act = run("migrate:v2")
+	r.Equal("Fail", act.Phase)
+	r.True(strings.HasPrefix(act.Message, "job migrate failed with exit code 2: migrate-new line 2\n"), act.Message)
+	r.True(strings.HasSuffix(act.Message, "migrate-new line 11"))
+	status, err = v.GetString("status")
+	r.NoError(err)
+	r.Equal(StatusFailed, status)
+
+	// the job deleted by the ttl controller is not recreated, the recorded result is reused
+	message := act.Message
+	r.NoError(cli.Delete(ctx, getJob()))
+	r.NoError(cli.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace("default")))
+	v, act = run("migrate:v2")
+	r.Equal("Fail", act.Phase)
+	r.Equal(message, act.Message)
+	r.True(kerrors.IsNotFound(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "migrate"}, &batchv1.Job{})))
+	code, err = v.LookupValue("exitCode")
+	r.NoError(err)
+	r.Equal("2", fmt.Sprint(code.CueValue()))
+}
+
+func TestRunInvalid(t *testing.T) {
+	r := require.New(t)
+	p := &provider{app: &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}}
+	v, err := value.NewValue(`name: "migrate"`, nil, "")
+	r.NoError(err)
+	r.Error(p.Run(nil, v, &mock.Action{}))
+}
+
+func TestRunStateKeep(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(utilscommon.Scheme).Build()
+	app := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Generation: 1}}
+	rk, err := resourcekeeper.NewResourceKeeper(ctx, cli, app)
+	r.NoError(err)
+	p := &provider{
+		app: app,
+		cli: cli,
+		apply: func(ctx context.Context, cluster string, owner common.ResourceCreatorRole, manifests ...*unstructured.Unstructured) error {
+			return rk.Dispatch(ctx, manifests, nil)
+		},
+	}
+	v, err := value.NewValue(`
+name: "smoke-test"
+image: "curl"
+ttlSecondsAfterFinished: 600
+`, nil, "")
+	r.NoError(err)
+	r.NoError(p.Run(nil, v, &mock.Action{}))
+	key := client.ObjectKey{Namespace: "default", Name: "smoke-test"}
+	r.NoError(cli.Get(ctx, key, &batchv1.Job{}))
+
+	// the job deleted by the ttl controller is not recreated by the state-keep
+	r.NoError(cli.Delete(ctx, &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "smoke-test"}}))
+	rk, err = resourcekeeper.NewResourceKeeper(ctx, cli, app)
+	r.NoError(err)
+	r.NoError(rk.StateKeep(ctx))
+	r.True(kerrors.IsNotFound(cli.Get(ctx, key, &batchv1.Job{})))
+}
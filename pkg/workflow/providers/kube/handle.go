@@ -17,10 +17,20 @@ limitations under the License.
 package kube
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
@@ -230,6 +240,178 @@ func (h *provider) Delete(ctx wfContext.Context, v *value.Value, act types.Actio
 	return nil
 }
 
+// WaitForParams is the parameters of the wait-for operation
+type WaitForParams struct {
+	Cluster string `json:"cluster"`
+	// JSONPath is the JSONPath expression of the observed field, such as {.status.phase}
+	JSONPath string `json:"jsonPath,omitempty"`
+	// Expected is the expected value of the JSONPath, any non-empty value is expected if not set
+	Expected *string `json:"expected,omitempty"`
+	// Predicate is the CUE expression of the condition, the object is referred as `object`
+	Predicate string `json:"predicate,omitempty"`
+	// Condition is the standard condition in status.conditions of the object
+	Condition *WaitForCondition `json:"condition,omitempty"`
+	Timeout   string            `json:"timeout"`
+}
+
+// WaitForCondition is the standard condition in status.conditions
+type WaitForCondition struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
+// WaitFor waits until the condition of the CR in cluster is satisfied, the step fails if the condition is not
+// satisfied within the timeout.
+func (h *provider) WaitFor(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	val, err := v.LookupValue("value")
+	if err != nil {
+		return err
+	}
+	obj := new(unstructured.Unstructured)
+	if err := val.UnmarshalTo(obj); err != nil {
+		return err
+	}
+	params := &WaitForParams{}
+	if err := v.UnmarshalTo(params); err != nil {
+		return err
+	}
+	set := 0
+	for _, c := range []bool{params.JSONPath != "", params.Predicate != "", params.Condition != nil} {
+		if c {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("exactly one of jsonPath, predicate and condition should be set")
+	}
+	timeout, err := time.ParseDuration(params.Timeout)
+	if err != nil {
+		return errors.Wrapf(err, "invalid timeout %s", params.Timeout)
+	}
+	key := client.ObjectKeyFromObject(obj)
+	if key.Namespace == "" {
+		key.Namespace = "default"
+		obj.SetNamespace(key.Namespace)
+	}
+	readCtx := multicluster.ContextWithClusterName(context.Background(), params.Cluster)
+	readCtx = auth.ContextWithUserInfo(readCtx, h.app)
+
+	satisfied, observed := false, "not found"
+	if err := h.cli.Get(readCtx, key, obj); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+	} else {
+		if satisfied, observed, err = checkWaitForCondition(obj, params); err != nil {
+			return err
+		}
+		if err := cue.FillUnstructuredObject(v, obj, "value"); err != nil {
+			return err
+		}
+	}
+	if err := v.FillObject(observed, "observed"); err != nil {
+		return err
+	}
+	if err := v.FillObject(satisfied, "satisfied"); err != nil {
+		return err
+	}
+
+	id := waitForID(obj, params)
+	if satisfied {
+		ctx.DeleteMutableValue(types.ContextPrefixWaitFor, id)
+		return nil
+	}
+	now := time.Now()
+	start, err := time.Parse(time.RFC3339, ctx.GetMutableValue(types.ContextPrefixWaitFor, id))
+	if err != nil {
+		start = now
+		ctx.SetMutableValue(now.Format(time.RFC3339), types.ContextPrefixWaitFor, id)
+	}
+	resource := fmt.Sprintf("%s %s", obj.GetKind(), key.String())
+	if now.Sub(start) > timeout {
+		ctx.DeleteMutableValue(types.ContextPrefixWaitFor, id)
+		act.Fail(fmt.Sprintf("timeout after %s waiting for %s, last observed: %s", params.Timeout, resource, observed))
+		return nil
+	}
+	act.Wait(fmt.Sprintf("waiting for %s, last observed: %s", resource, observed))
+	return nil
+}
+
+func checkWaitForCondition(obj *unstructured.Unstructured, params *WaitForParams) (bool, string, error) {
+	switch {
+	case params.JSONPath != "":
+		path := params.JSONPath
+		if !strings.HasPrefix(path, "{") {
+			path = "{" + path + "}"
+		}
+		j := jsonpath.New("wait-for").AllowMissingKeys(true)
+		if err := j.Parse(path); err != nil {
+			return false, "", errors.Wrapf(err, "invalid jsonPath %s", params.JSONPath)
+		}
+		buf := &bytes.Buffer{}
+		if err := j.Execute(buf, obj.Object); err != nil {
+			return false, "", errors.Wrapf(err, "execute jsonPath %s", params.JSONPath)
+		}
+		observed := buf.String()
+		if params.Expected != nil {
+			return observed == *params.Expected, fmt.Sprintf("%q", observed), nil
+		}
+		return observed != "", fmt.Sprintf("%q", observed), nil
+	case params.Condition != nil:
+		conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		if err != nil {
+			return false, "", err
+		}
+		expected := params.Condition.Status
+		if expected == "" {
+			expected = string(metav1.ConditionTrue)
+		}
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok || condition["type"] != params.Condition.Type {
+				continue
+			}
+			status, _ := condition["status"].(string)
+			observed := fmt.Sprintf("condition %s is %s", params.Condition.Type, status)
+			if msg, _ := condition["message"].(string); msg != "" {
+				observed += ": " + msg
+			}
+			return status == expected, observed, nil
+		}
+		return false, fmt.Sprintf("condition %s not found", params.Condition.Type), nil
+	default:
+		bs, err := json.Marshal(obj.Object)
+		if err != nil {
+			return false, "", err
+		}
+		pv, err := value.NewValue(fmt.Sprintf("object: %s\nsatisfied: %s", string(bs), params.Predicate), nil, "")
+		if err != nil {
+			return false, "", errors.WithMessagef(err, "invalid predicate %s", params.Predicate)
+		}
+		satisfied, err := pv.GetBool("satisfied")
+		if err != nil {
+			return false, "", errors.WithMessagef(err, "evaluate predicate %s", params.Predicate)
+		}
+		return satisfied, fmt.Sprintf("predicate is %t", satisfied), nil
+	}
+}
+
+// waitForID identifies the wait-for operation by the resource and the condition
+func waitForID(obj *unstructured.Unstructured, params *WaitForParams) string {
+	h := sha256.New()
+	for _, s := range []string{params.Cluster, obj.GetAPIVersion(), obj.GetKind(), obj.GetNamespace(), obj.GetName(), params.JSONPath, params.Predicate} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	if params.Expected != nil {
+		h.Write([]byte(*params.Expected))
+	}
+	if params.Condition != nil {
+		h.Write([]byte(params.Condition.Type + "=" + params.Condition.Status))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
 // Install register handlers to provider discover.
 func Install(p providers.Providers, app *v1beta1.Application, cli client.Client, apply Dispatcher, deleter Deleter) {
 	if app != nil {
@@ -247,5 +429,6 @@ func Install(p providers.Providers, app *v1beta1.Application, cli client.Client,
 		"read":              prd.Read,
 		"list":              prd.List,
 		"delete":            prd.Delete,
+		"wait-for":          prd.WaitFor,
 	})
 }
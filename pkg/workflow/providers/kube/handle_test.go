@@ -27,6 +27,7 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/require"
 
 	corev1 "k8s.io/api/core/v1"
 	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -39,6 +40,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"sigs.k8s.io/controller-runtime/pkg/envtest/printer"
 	"sigs.k8s.io/yaml"
@@ -46,6 +48,8 @@ import (
 	"github.com/oam-dev/kubevela/pkg/cue/model/value"
 	"github.com/oam-dev/kubevela/pkg/cue/packages"
 	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers/mock"
+	wfTypes "github.com/oam-dev/kubevela/pkg/workflow/types"
 )
 
 // These tests use Ginkgo (BDD-style Go testing framework). Refer to
@@ -412,6 +416,116 @@ val: {
 	})
 })
 
+func TestWaitFor(t *testing.T) {
+	r := require.New(t)
+	deploy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "app", "namespace": "default"},
+		"spec":       map[string]interface{}{"replicas": int64(2)},
+		"status": map[string]interface{}{
+			"readyReplicas": int64(1),
+			"conditions": []interface{}{map[string]interface{}{
+				"type":    "Available",
+				"status":  "False",
+				"message": "Deployment does not have minimum availability.",
+			}},
+		},
+	}}
+	cli := fake.NewClientBuilder().WithObjects(deploy).Build()
+	p := &provider{cli: cli}
+	ref := `value: {apiVersion: "apps/v1", kind: "Deployment", metadata: name: "app"}
+cluster: ""
+timeout: "5m"
+`
+	testCases := map[string]struct {
+		condition string
+		phase     string
+		observed  string
+		hasErr    bool
+	}{
+		"jsonpath-satisfied": {
+			condition: `jsonPath: "{.status.readyReplicas}"`,
+			observed:  `"1"`,
+		},
+		"jsonpath-waiting": {
+			condition: `jsonPath: ".status.readyReplicas", expected: "2"`,
+			phase:     "Wait",
+			observed:  `"1"`,
+		},
+		"condition-waiting": {
+			condition: `condition: type: "Available"`,
+			phase:     "Wait",
+			observed:  "condition Available is False: Deployment does not have minimum availability.",
+		},
+		"condition-not-found": {
+			condition: `condition: type: "Progressing"`,
+			phase:     "Wait",
+			observed:  "condition Progressing not found",
+		},
+		"predicate-satisfied": {
+			condition: `predicate: "object.status.readyReplicas >= 1"`,
+			observed:  "predicate is true",
+		},
+		"predicate-waiting": {
+			condition: `predicate: "object.status.readyReplicas == object.spec.replicas"`,
+			phase:     "Wait",
+			observed:  "predicate is false",
+		},
+		"no-condition": {
+			hasErr: true,
+		},
+		"multiple-conditions": {
+			condition: `predicate: "true", jsonPath: "{.status}"`,
+			hasErr:    true,
+		},
+	}
+	for name, tc := range testCases {
+		ctx, err := wfContext.NewContext(cli, "default", "app", "uid")
+		r.NoError(err, name)
+		v, err := value.NewValue(ref+tc.condition, nil, "")
+		r.NoError(err, name)
+		act := &mock.Action{}
+		err = p.WaitFor(ctx, v, act)
+		if tc.hasErr {
+			r.Error(err, name)
+			continue
+		}
+		r.NoError(err, name)
+		r.Equal(tc.phase, act.Phase, name)
+		observed, err := v.GetString("observed")
+		r.NoError(err, name)
+		r.Equal(tc.observed, observed, name)
+	}
+
+	ctx, err := wfContext.NewContext(cli, "default", "app", "uid")
+	r.NoError(err)
+	v, err := value.NewValue(`value: {apiVersion: "apps/v1", kind: "Deployment", metadata: name: "app"}
+cluster: ""
+timeout: "1m"
+condition: type: "Available"
+`, nil, "")
+	r.NoError(err)
+	params := &WaitForParams{Cluster: "", Timeout: "1m", Condition: &WaitForCondition{Type: "Available"}}
+	ctx.SetMutableValue(time.Now().Add(-2*time.Minute).Format(time.RFC3339), wfTypes.ContextPrefixWaitFor, waitForID(deploy, params))
+	act := &mock.Action{}
+	r.NoError(p.WaitFor(ctx, v, act))
+	r.Equal("Fail", act.Phase)
+	r.Equal("timeout after 1m waiting for Deployment default/app, last observed: condition Available is False: Deployment does not have minimum availability.", act.Message)
+	r.Equal("", ctx.GetMutableValue(wfTypes.ContextPrefixWaitFor, waitForID(deploy, params)))
+
+	v, err = value.NewValue(`value: {apiVersion: "apps/v1", kind: "Deployment", metadata: name: "not-exist"}
+cluster: ""
+timeout: "5m"
+jsonPath: "{.status}"
+`, nil, "")
+	r.NoError(err)
+	act = &mock.Action{}
+	r.NoError(p.WaitFor(ctx, v, act))
+	r.Equal("Wait", act.Phase)
+	r.Equal("waiting for Deployment default/not-exist, last observed: not found", act.Message)
+}
+
 func newWorkflowContextForTest() (wfContext.Context, error) {
 	cm := corev1.ConfigMap{}
 
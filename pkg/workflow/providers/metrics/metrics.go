@@ -0,0 +1,339 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers"
+	"github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+const (
+	// ProviderName is provider name for install.
+	ProviderName = "metrics"
+)
+
+const (
+	// VerdictSucceeded means the samples meet the thresholds
+	VerdictSucceeded = "Succeeded"
+	// VerdictFailed means too many samples break the thresholds
+	VerdictFailed = "Failed"
+	// VerdictInconclusive means there are not enough samples to make the verdict
+	VerdictInconclusive = "Inconclusive"
+)
+
+var queryTimeout = 30 * time.Second
+
+type provider struct {
+	cli *http.Client
+}
+
+// QueryParams is the parameters of the range query
+type QueryParams struct {
+	Endpoint string            `json:"endpoint"`
+	Query    string            `json:"query"`
+	Window   string            `json:"window"`
+	Step     string            `json:"step"`
+	Header   map[string]string `json:"header,omitempty"`
+}
+
+// Series is a time series returned by the query
+type Series struct {
+	Metric map[string]string `json:"metric"`
+	Values []float64         `json:"values"`
+}
+
+// QueryResult is the result of the range query
+type QueryResult struct {
+	// Values are the values of all the samples in the series
+	Values []float64 `json:"values"`
+	Series []Series  `json:"series"`
+}
+
+// AnalyzeParams is the parameters of the metric analysis
+type AnalyzeParams struct {
+	QueryParams      `json:",inline"`
+	SuccessCondition string `json:"successCondition,omitempty"`
+	FailureCondition string `json:"failureCondition,omitempty"`
+	Count            int    `json:"count"`
+	FailureLimit     int    `json:"failureLimit"`
+}
+
+// AnalyzeResult is the result of the metric analysis
+type AnalyzeResult struct {
+	Values       []float64 `json:"values"`
+	SuccessCount int       `json:"successCount"`
+	FailureCount int       `json:"failureCount"`
+	Verdict      string    `json:"verdict"`
+	Message      string    `json:"message"`
+}
+
+// Query runs the range query against the prometheus compatible endpoint.
+func (p *provider) Query(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	params := &QueryParams{}
+	if err := v.UnmarshalTo(params); err != nil {
+		return err
+	}
+	result, err := p.query(params, time.Time{})
+	if err != nil {
+		return err
+	}
+	return v.FillObject(result, "result")
+}
+
+// Analyze queries the metrics and evaluates the samples with the thresholds, the step waits until there are
+// enough samples, and fails if the failed samples exceed the failure limit. Only the samples after the analysis
+// starts are evaluated, so the samples before the release don't count.
+func (p *provider) Analyze(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	params := &AnalyzeParams{}
+	if err := v.UnmarshalTo(params); err != nil {
+		return err
+	}
+	if params.SuccessCondition == "" && params.FailureCondition == "" {
+		return errors.New("either successCondition or failureCondition should be set")
+	}
+	id := analysisID(params)
+	start, err := time.Parse(time.RFC3339, ctx.GetMutableValue(types.ContextPrefixMetricsAnalysis, id))
+	if err != nil {
+		start = time.Now()
+		ctx.SetMutableValue(start.Format(time.RFC3339), types.ContextPrefixMetricsAnalysis, id)
+	}
+	qr, err := p.query(&params.QueryParams, start)
+	if err != nil {
+		return err
+	}
+	result, err := analyze(params, qr.Values)
+	if err != nil {
+		return err
+	}
+	if err := v.FillObject(result, "result"); err != nil {
+		return err
+	}
+	switch result.Verdict {
+	case VerdictInconclusive:
+		act.Wait(result.Message)
+		return nil
+	case VerdictFailed:
+		act.Fail(result.Message)
+	default:
+	}
+	ctx.DeleteMutableValue(types.ContextPrefixMetricsAnalysis, id)
+	return nil
+}
+
+// analysisID identifies the analysis to record its start time in the workflow context
+func analysisID(params *AnalyzeParams) string {
+	h := sha256.New()
+	for _, s := range []string{params.Endpoint, params.Query, params.SuccessCondition, params.FailureCondition} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func analyze(params *AnalyzeParams, values []float64) (*AnalyzeResult, error) {
+	samples := values
+	if params.Count > 0 && len(samples) > params.Count {
+		samples = samples[len(samples)-params.Count:]
+	}
+	result := &AnalyzeResult{Values: samples}
+	if len(samples) == 0 || len(samples) < params.Count {
+		result.Verdict = VerdictInconclusive
+		result.Message = fmt.Sprintf("waiting for enough samples, %d/%d collected", len(samples), params.Count)
+		return result, nil
+	}
+	for _, sample := range samples {
+		failed := false
+		if params.SuccessCondition != "" {
+			ok, err := evaluate(params.SuccessCondition, sample)
+			if err != nil {
+				return nil, errors.WithMessage(err, "invalid successCondition")
+			}
+			failed = !ok
+		}
+		if params.FailureCondition != "" && !failed {
+			ok, err := evaluate(params.FailureCondition, sample)
+			if err != nil {
+				return nil, errors.WithMessage(err, "invalid failureCondition")
+			}
+			failed = ok
+		}
+		if failed {
+			result.FailureCount++
+		} else {
+			result.SuccessCount++
+		}
+	}
+	if result.FailureCount > params.FailureLimit {
+		result.Verdict = VerdictFailed
+		result.Message = fmt.Sprintf("%d of %d samples failed, exceeds the failure limit %d", result.FailureCount, len(samples), params.FailureLimit)
+		return result, nil
+	}
+	result.Verdict = VerdictSucceeded
+	result.Message = fmt.Sprintf("%d of %d samples succeeded", result.SuccessCount, len(samples))
+	return result, nil
+}
+
+// evaluate checks the sample with the condition like `>= 0.99`, the supported operators are
+// <, <=, >, >=, == and !=.
+func evaluate(condition string, sample float64) (bool, error) {
+	condition = strings.TrimSpace(condition)
+	var op string
+	for _, o := range []string{"<=", ">=", "==", "!=", "<", ">"} {
+		if strings.HasPrefix(condition, o) {
+			op = o
+			break
+		}
+	}
+	if op == "" {
+		return false, errors.Errorf("unknown operator in condition %s", condition)
+	}
+	threshold, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimPrefix(condition, op)), 64)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid threshold in condition %s", condition)
+	}
+	switch op {
+	case "<=":
+		return sample <= threshold, nil
+	case ">=":
+		return sample >= threshold, nil
+	case "==":
+		return sample == threshold, nil
+	case "!=":
+		return sample != threshold, nil
+	case "<":
+		return sample < threshold, nil
+	default:
+		return sample > threshold, nil
+	}
+}
+
+type queryRangeResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][]interface{}   `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// query runs the range query over the window until now, the window is limited to start after since if set. The query
+// must return at most one series, since the samples of different series can't be evaluated together.
+func (p *provider) query(params *QueryParams, since time.Time) (*QueryResult, error) {
+	window, err := time.ParseDuration(params.Window)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid window %s", params.Window)
+	}
+	step, err := time.ParseDuration(params.Step)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid step %s", params.Step)
+	}
+	end := time.Now()
+	start := end.Add(-window)
+	if start.Before(since) {
+		start = since
+	}
+	query := url.Values{}
+	query.Set("query", params.Query)
+	query.Set("start", strconv.FormatInt(start.Unix(), 10))
+	query.Set("end", strconv.FormatInt(end.Unix(), 10))
+	query.Set("step", strconv.FormatFloat(step.Seconds(), 'f', -1, 64))
+
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(params.Endpoint, "/")+"/api/v1/query_range?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range params.Header {
+		req.Header.Set(k, v)
+	}
+	resp, err := p.cli.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "query metrics")
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	r := &queryRangeResponse{}
+	if err := json.Unmarshal(body, r); err != nil {
+		return nil, errors.Errorf("query metrics failed with status code %d: %s", resp.StatusCode, string(body))
+	}
+	if r.Status != "success" {
+		return nil, errors.Errorf("query metrics failed: %s", r.Error)
+	}
+	if len(r.Data.Result) > 1 {
+		return nil, errors.Errorf("the query returns %d series, aggregate it into one series, such as by sum", len(r.Data.Result))
+	}
+	result := &QueryResult{Values: []float64{}, Series: []Series{}}
+	for _, s := range r.Data.Result {
+		series := Series{Metric: s.Metric, Values: []float64{}}
+		if series.Metric == nil {
+			series.Metric = map[string]string{}
+		}
+		for _, sample := range s.Values {
+			if len(sample) != 2 {
+				continue
+			}
+			str, ok := sample[1].(string)
+			if !ok {
+				continue
+			}
+			f, err := strconv.ParseFloat(str, 64)
+			// NaN and Inf can't be encoded into the step outputs
+			if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+				continue
+			}
+			series.Values = append(series.Values, f)
+			result.Values = append(result.Values, f)
+		}
+		result.Series = append(result.Series, series)
+	}
+	return result, nil
+}
+
+// Install register handlers to provider discover.
+func Install(p providers.Providers) {
+	prd := &provider{cli: &http.Client{}}
+	p.Register(ProviderName, map[string]providers.Handler{
+		"query":   prd.Query,
+		"analyze": prd.Analyze,
+	})
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers/mock"
+	"github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+func TestQuery(t *testing.T) {
+	r := require.New(t)
+	srv, _ := newMockServer(t)
+	defer srv.Close()
+
+	p := &provider{cli: srv.Client()}
+	v, err := value.NewValue(fmt.Sprintf(`
+endpoint: "%s"
+query: "error_rate"
+window: "5m"
+step: "1m"
+header: Authorization: "Bearer token"
+`, srv.URL), nil, "")
+	r.NoError(err)
+	r.NoError(p.Query(nil, v, &mock.Action{}))
+	values, err := v.LookupValue("result", "values")
+	r.NoError(err)
+	s, err := values.String()
+	r.NoError(err)
+	r.Contains(s, "0.01")
+	r.Contains(s, "0.2")
+	r.NotContains(s, "0.3")
+	iv, err := v.LookupByScript("result.series[0].metric.instance")
+	r.NoError(err)
+	instance, err := iv.CueValue().String()
+	r.NoError(err)
+	r.Equal("a", instance)
+
+	v, err = value.NewValue(fmt.Sprintf(`
+endpoint: "%s"
+query: "bad"
+window: "5m"
+step: "1m"
+`, srv.URL), nil, "")
+	r.NoError(err)
+	r.Error(p.Query(nil, v, &mock.Action{}))
+
+	v, err = value.NewValue(fmt.Sprintf(`
+endpoint: "%s"
+query: "error_rate_by_instance"
+window: "5m"
+step: "1m"
+`, srv.URL), nil, "")
+	r.NoError(err)
+	r.Error(p.Query(nil, v, &mock.Action{}))
+}
+
+func TestAnalyze(t *testing.T) {
+	r := require.New(t)
+	srv, lastStart := newMockServer(t)
+	defer srv.Close()
+
+	testCases := map[string]struct {
+		params  string
+		phase   string
+		verdict string
+		hasErr  bool
+	}{
+		"succeeded": {
+			params:  `successCondition: "< 0.5", count: 3, failureLimit: 0`,
+			verdict: VerdictSucceeded,
+		},
+		"failed": {
+			params:  `failureCondition: ">0.1", count: 3, failureLimit: 0`,
+			phase:   "Fail",
+			verdict: VerdictFailed,
+		},
+		"tolerated": {
+			params:  `failureCondition: ">0.1", count: 3, failureLimit: 1`,
+			verdict: VerdictSucceeded,
+		},
+		"inconclusive": {
+			params:  `successCondition: "< 0.5", count: 10, failureLimit: 0`,
+			phase:   "Wait",
+			verdict: VerdictInconclusive,
+		},
+		"no-condition": {
+			params: `count: 3, failureLimit: 0`,
+			hasErr: true,
+		},
+		"invalid-condition": {
+			params: `successCondition: "about 0.5", count: 3, failureLimit: 0`,
+			hasErr: true,
+		},
+	}
+	p := &provider{cli: srv.Client()}
+	for name, tc := range testCases {
+		ctx, err := wfContext.NewContext(fake.NewClientBuilder().Build(), "default", "app", "uid")
+		r.NoError(err, name)
+		v, err := value.NewValue(fmt.Sprintf(`
+endpoint: "%s"
+query: "error_rate"
+window: "5m"
+step: "1m"
+%s
+`, srv.URL, tc.params), nil, "")
+		r.NoError(err, name)
+		act := &mock.Action{}
+		err = p.Analyze(ctx, v, act)
+		if tc.hasErr {
+			r.Error(err, name)
+			continue
+		}
+		r.NoError(err, name)
+		r.Equal(tc.phase, act.Phase, name)
+		verdict, err := v.GetString("result", "verdict")
+		r.NoError(err, name)
+		r.Equal(tc.verdict, verdict, name)
+	}
+
+	// the window is limited to the start of the analysis
+	ctx, err := wfContext.NewContext(fake.NewClientBuilder().Build(), "default", "app", "uid")
+	r.NoError(err)
+	params := &AnalyzeParams{QueryParams: QueryParams{Endpoint: srv.URL, Query: "error_rate"}, SuccessCondition: "< 0.5"}
+	start := time.Now().Add(-time.Minute).Truncate(time.Second)
+	ctx.SetMutableValue(start.Format(time.RFC3339), types.ContextPrefixMetricsAnalysis, analysisID(params))
+	v, err := value.NewValue(fmt.Sprintf(`
+endpoint: "%s"
+query: "error_rate"
+window: "5m"
+step: "1m"
+successCondition: "< 0.5"
+count: 10
+failureLimit: 0
+`, srv.URL), nil, "")
+	r.NoError(err)
+	act := &mock.Action{}
+	r.NoError(p.Analyze(ctx, v, act))
+	r.Equal("Wait", act.Phase)
+	r.Equal(strconv.FormatInt(start.Unix(), 10), *lastStart)
+	r.Equal(start.Format(time.RFC3339), ctx.GetMutableValue(types.ContextPrefixMetricsAnalysis, analysisID(params)))
+}
+
+func newMockServer(t *testing.T) (*httptest.Server, *string) {
+	lastStart := new(string)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/v1/query_range" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		*lastStart = req.URL.Query().Get("start")
+		if req.URL.Query().Get("query") == "error_rate_by_instance" {
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+{"metric":{"instance":"a"},"values":[[1650000000,"0.01"]]},
+{"metric":{"instance":"b"},"values":[[1650000000,"0.3"]]}]}}`))
+			return
+		}
+		if req.URL.Query().Get("query") != "error_rate" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","error":"parse error"}`))
+			return
+		}
+		for _, key := range []string{"start", "end", "step"} {
+			if req.URL.Query().Get(key) == "" {
+				t.Errorf("missing %s in the query", key)
+			}
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+{"metric":{"instance":"a"},"values":[[1650000000,"0.01"],[1650000060,"0.02"],[1650000120,"NaN"],[1650000180,"0.2"]]}]}}`))
+	})), lastStart
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"sync"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers"
+	"github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+// Call is a provider call recorded during the simulation
+type Call struct {
+	// Step is the name of the step that makes the call
+	Step string
+	// Provider is the name of the provider
+	Provider string
+	// Handler is the name of the handler in the provider
+	Handler string
+	// Params are the rendered parameters passed to the handler
+	Params string
+	// Error is the error returned by the handler
+	Error string
+}
+
+// Recorder wraps the providers and records every call to their handlers
+type Recorder struct {
+	providers.Providers
+
+	l     sync.Mutex
+	step  string
+	calls []Call
+}
+
+// NewRecorder creates a recorder of the given providers
+func NewRecorder(p providers.Providers) *Recorder {
+	return &Recorder{Providers: p}
+}
+
+// GetHandler get the handler by provider name and handle name, the returned handler records the call.
+func (r *Recorder) GetHandler(provider, name string) (providers.Handler, bool) {
+	h, ok := r.Providers.GetHandler(provider, name)
+	if !ok {
+		return nil, false
+	}
+	return func(ctx wfContext.Context, v *value.Value, act types.Action) error {
+		params, err := v.String()
+		if err != nil {
+			params = err.Error()
+		}
+		call := Call{Provider: provider, Handler: name, Params: params}
+		if err := h(ctx, v, act); err != nil {
+			call.Error = err.Error()
+			r.record(call)
+			return err
+		}
+		r.record(call)
+		return nil
+	}, true
+}
+
+// SetStep sets the step that makes the subsequent calls
+func (r *Recorder) SetStep(name string) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.step = name
+}
+
+// Calls returns the recorded calls in order
+func (r *Recorder) Calls() []Call {
+	r.l.Lock()
+	defer r.l.Unlock()
+	calls := make([]Call, len(r.calls))
+	copy(calls, r.calls)
+	return calls
+}
+
+func (r *Recorder) record(call Call) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	call.Step = r.step
+	r.calls = append(r.calls, call)
+}
+
+// InstallFakes replaces the providers that talk to the outside of the cluster with fakes
+// that always succeed, such as http, email, metrics, git, job, notification and config.
+func InstallFakes(p providers.Providers) {
+	p.Register("http", map[string]providers.Handler{
+		"do": func(ctx wfContext.Context, v *value.Value, act types.Action) error {
+			return v.FillObject(map[string]interface{}{"body": "", "statusCode": 200}, "response")
+		},
+	})
+	p.Register("email", map[string]providers.Handler{
+		"send": func(ctx wfContext.Context, v *value.Value, act types.Action) error {
+			return nil
+		},
+	})
+	p.Register("metrics", map[string]providers.Handler{
+		"query": func(ctx wfContext.Context, v *value.Value, act types.Action) error {
+			return v.FillObject(map[string]interface{}{"values": []float64{}, "series": []interface{}{}}, "result")
+		},
+		"analyze": func(ctx wfContext.Context, v *value.Value, act types.Action) error {
+			return v.FillObject(map[string]interface{}{
+				"values":       []float64{},
+				"successCount": 0,
+				"failureCount": 0,
+				"verdict":      "Succeeded",
+				"message":      "simulated",
+			}, "result")
+		},
+	})
+	p.Register("git", map[string]providers.Handler{
+		"clone": func(ctx wfContext.Context, v *value.Value, act types.Action) error {
+			return v.FillObject(map[string]interface{}{"path": "", "sha": ""})
+		},
+		"write": func(ctx wfContext.Context, v *value.Value, act types.Action) error {
+			return nil
+		},
+		"commit": func(ctx wfContext.Context, v *value.Value, act types.Action) error {
+			return v.FillObject(map[string]interface{}{"changed": true, "sha": ""})
+		},
+		"push": func(ctx wfContext.Context, v *value.Value, act types.Action) error {
+			return v.FillObject("", "sha")
+		},
+	})
+	p.Register("job", map[string]providers.Handler{
+		"run": func(ctx wfContext.Context, v *value.Value, act types.Action) error {
+			return v.FillObject(map[string]interface{}{"status": "succeeded", "exitCode": 0, "logs": ""})
+		},
+	})
+	p.Register("notification", map[string]providers.Handler{
+		"approval-links": func(ctx wfContext.Context, v *value.Value, act types.Action) error {
+			return v.FillObject(map[string]interface{}{"approve": "", "reject": ""}, "links")
+		},
+		"sign": func(ctx wfContext.Context, v *value.Value, act types.Action) error {
+			return v.FillObject("", "signature")
+		},
+	})
+	p.Register("config", map[string]providers.Handler{
+		"read": func(ctx wfContext.Context, v *value.Value, act types.Action) error {
+			return v.FillObject(map[string]interface{}{"properties": map[string]interface{}{}, "data": map[string]string{}}, "config")
+		},
+		"list": func(ctx wfContext.Context, v *value.Value, act types.Action) error {
+			return v.FillObject([]interface{}{}, "configs")
+		},
+		"write": func(ctx wfContext.Context, v *value.Value, act types.Action) error {
+			return nil
+		},
+	})
+}
+
+// NewDispatcher returns a kube dispatcher that applies the manifests to the given client, which
+// is usually a fake client.
+func NewDispatcher(cli client.Client) func(ctx context.Context, cluster string, owner common.ResourceCreatorRole, manifests ...*unstructured.Unstructured) error {
+	return func(ctx context.Context, cluster string, owner common.ResourceCreatorRole, manifests ...*unstructured.Unstructured) error {
+		for _, manifest := range manifests {
+			existing := &unstructured.Unstructured{}
+			existing.SetGroupVersionKind(manifest.GroupVersionKind())
+			err := cli.Get(ctx, client.ObjectKeyFromObject(manifest), existing)
+			switch {
+			case kerrors.IsNotFound(err):
+				err = cli.Create(ctx, manifest)
+			case err == nil:
+				manifest.SetResourceVersion(existing.GetResourceVersion())
+				err = cli.Update(ctx, manifest)
+			default:
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// NewDeleter returns a kube deleter that deletes the manifest from the given client, which
+// is usually a fake client.
+func NewDeleter(cli client.Client) func(ctx context.Context, cluster string, owner common.ResourceCreatorRole, manifest *unstructured.Unstructured) error {
+	return func(ctx context.Context, cluster string, owner common.ResourceCreatorRole, manifest *unstructured.Unstructured) error {
+		return client.IgnoreNotFound(cli.Delete(ctx, manifest))
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	"github.com/oam-dev/kubevela/pkg/workflow/callback"
+	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers"
+	"github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+const (
+	// ProviderName is provider name for install.
+	ProviderName = "notification"
+)
+
+type provider struct {
+	app *v1beta1.Application
+	cli client.Client
+}
+
+// ApprovalLinksParams is the parameters of generating the approval links
+type ApprovalLinksParams struct {
+	CallbackURL string `json:"callbackURL"`
+	StepID      string `json:"stepID"`
+	Expiration  string `json:"expiration,omitempty"`
+}
+
+// SignParams is the parameters of signing the payload
+type SignParams struct {
+	Payload string `json:"payload"`
+	Key     string `json:"key"`
+}
+
+// ApprovalLinks generates the signed links that approve or reject the suspended workflow of the application.
+// The links are bound to the step, so they are outdated once the workflow is restarted. The approvals of the links
+// are recorded as types.ApproverWorkflowCallback.
+func (p *provider) ApprovalLinks(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	params := &ApprovalLinksParams{}
+	if err := v.UnmarshalTo(params); err != nil {
+		return err
+	}
+	if params.CallbackURL == "" {
+		return errors.New("callbackURL must be set")
+	}
+	if params.StepID == "" {
+		return errors.New("stepID must be set")
+	}
+	now := time.Now()
+	expiresAt, err := callback.Expiration(params.Expiration, now)
+	if err != nil {
+		return err
+	}
+	key, err := callback.GetOrCreateKey(context.Background(), p.cli)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the key to sign the callbacks")
+	}
+	links := map[string]string{}
+	for _, action := range []callback.Action{callback.ActionApprove, callback.ActionReject} {
+		token, err := callback.Sign(key, callback.Claims{
+			Namespace: p.app.Namespace,
+			Name:      p.app.Name,
+			StepID:    params.StepID,
+			Action:    action,
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+		links[string(action)] = callback.URL(params.CallbackURL, token)
+	}
+	return v.FillObject(links, "links")
+}
+
+// Sign signs the payload with HMAC-SHA256, the receivers verify the payload with the same key.
+func (p *provider) Sign(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	params := &SignParams{}
+	if err := v.UnmarshalTo(params); err != nil {
+		return err
+	}
+	if params.Key == "" {
+		return errors.New("key must be set")
+	}
+	return v.FillObject(callback.SignPayload([]byte(params.Key), []byte(params.Payload)), "signature")
+}
+
+// Install register handlers to provider discover.
+func Install(p providers.Providers, app *v1beta1.Application, cli client.Client) {
+	prd := &provider{app: app, cli: cli}
+	p.Register(ProviderName, map[string]providers.Handler{
+		"approval-links": prd.ApprovalLinks,
+		"sign":           prd.Sign,
+	})
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	"github.com/oam-dev/kubevela/pkg/workflow/callback"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers/mock"
+)
+
+func TestApprovalLinks(t *testing.T) {
+	r := require.New(t)
+	cli := fake.NewClientBuilder().Build()
+	p := &provider{app: &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}, cli: cli}
+	v, err := value.NewValue(`
+callbackURL: "https://velaux.example.com/"
+stepID: "step-abc"
+expiration: "1h"
+`, nil, "")
+	r.NoError(err)
+	r.NoError(p.ApprovalLinks(nil, v, &mock.Action{}))
+
+	key, err := callback.GetKey(context.Background(), cli)
+	r.NoError(err)
+	r.Len(key, 32)
+	for _, action := range []callback.Action{callback.ActionApprove, callback.ActionReject} {
+		link, err := v.GetString("links", string(action))
+		r.NoError(err)
+		prefix := "https://velaux.example.com" + callback.Path
+		r.True(strings.HasPrefix(link, prefix), link)
+		claims, err := callback.Verify(key, strings.TrimPrefix(link, prefix), time.Now())
+		r.NoError(err)
+		r.Equal(action, claims.Action)
+		r.Equal("default", claims.Namespace)
+		r.Equal("app", claims.Name)
+		r.Equal("step-abc", claims.StepID)
+		r.NotEmpty(claims.Nonce)
+		_, err = callback.Verify(key, strings.TrimPrefix(link, prefix), time.Now().Add(2*time.Hour))
+		r.Equal(callback.ErrExpired, err)
+	}
+
+	v, err = value.NewValue(`callbackURL: "https://velaux.example.com"`, nil, "")
+	r.NoError(err)
+	r.Error(p.ApprovalLinks(nil, v, &mock.Action{}))
+}
+
+func TestSign(t *testing.T) {
+	r := require.New(t)
+	p := &provider{}
+	v, err := value.NewValue(`
+payload: "{\"hello\":\"world\"}"
+key: "secret"
+`, nil, "")
+	r.NoError(err)
+	r.NoError(p.Sign(nil, v, &mock.Action{}))
+	signature, err := v.GetString("signature")
+	r.NoError(err)
+	r.Equal(callback.SignPayload([]byte("secret"), []byte(`{"hello":"world"}`)), signature)
+	r.Len(signature, 64)
+}
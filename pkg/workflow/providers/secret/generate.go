@@ -0,0 +1,595 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/auth"
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/oam/util"
+	"github.com/oam-dev/kubevela/pkg/resourcekeeper"
+	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers/kube"
+	"github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+const (
+	// ProviderName is provider name for install.
+	ProviderName = "secret"
+)
+
+const (
+	// AlgorithmRSA generates the RSA keys
+	AlgorithmRSA = "RSA"
+	// AlgorithmECDSA generates the ECDSA keys
+	AlgorithmECDSA = "ECDSA"
+)
+
+const (
+	lowercaseChars = "abcdefghijklmnopqrstuvwxyz"
+	uppercaseChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars     = "0123456789"
+)
+
+type provider struct {
+	app   *v1beta1.Application
+	cli   client.Client
+	apply kube.Dispatcher
+}
+
+// GenerateParams is the parameters of generating the secret
+type GenerateParams struct {
+	Cluster      string                      `json:"cluster"`
+	Name         string                      `json:"name"`
+	Namespace    string                      `json:"namespace"`
+	Type         corev1.SecretType           `json:"type"`
+	Strings      map[string]RandomStringSpec `json:"strings,omitempty"`
+	KeyPairs     map[string]KeySpec          `json:"keyPairs,omitempty"`
+	Certificates map[string]CertificateSpec  `json:"certificates,omitempty"`
+}
+
+// RandomStringSpec is the policy of the random string, the string contains at least one character
+// of each enabled character set
+type RandomStringSpec struct {
+	Length        int    `json:"length"`
+	Lowercase     bool   `json:"lowercase"`
+	Uppercase     bool   `json:"uppercase"`
+	Digits        bool   `json:"digits"`
+	Symbols       bool   `json:"symbols"`
+	SymbolCharset string `json:"symbolCharset"`
+}
+
+// KeySpec is the spec of the private key
+type KeySpec struct {
+	Algorithm string `json:"algorithm"`
+	Bits      int    `json:"bits"`
+	Curve     string `json:"curve"`
+}
+
+// CertificateSpec is the spec of the X.509 certificate, it's self-signed if no issuer is set
+type CertificateSpec struct {
+	KeySpec      `json:",inline"`
+	CommonName   string     `json:"commonName"`
+	Organization []string   `json:"organization,omitempty"`
+	DNSNames     []string   `json:"dnsNames,omitempty"`
+	IPAddresses  []string   `json:"ipAddresses,omitempty"`
+	Validity     string     `json:"validity"`
+	IsCA         bool       `json:"isCA"`
+	Issuer       string     `json:"issuer,omitempty"`
+	IssuerRef    *IssuerRef `json:"issuerRef,omitempty"`
+}
+
+// IssuerRef refers to the secret of the CA certificate and key that issues the certificate
+type IssuerRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	CertKey   string `json:"certKey"`
+	KeyKey    string `json:"keyKey"`
+}
+
+// Generate generates the data of the secret and applies it to the cluster. The existing data in the
+// secret is kept, so the data is only generated once.
+func (p *provider) Generate(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	params := &GenerateParams{}
+	if err := v.UnmarshalTo(params); err != nil {
+		return err
+	}
+	if params.Name == "" {
+		return errors.New("name of the secret must be set")
+	}
+	if params.Namespace == "" {
+		params.Namespace = p.app.Namespace
+	}
+	deployCtx := multicluster.ContextWithClusterName(context.Background(), params.Cluster)
+	deployCtx = auth.ContextWithUserInfo(deployCtx, p.app)
+
+	existing := &corev1.Secret{}
+	if err := p.cli.Get(deployCtx, client.ObjectKey{Namespace: params.Namespace, Name: params.Name}, existing); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get secret %s/%s", params.Namespace, params.Name)
+		}
+		existing = nil
+	}
+	data := map[string][]byte{}
+	if existing != nil {
+		for k, v := range existing.Data {
+			data[k] = v
+		}
+	}
+	if err := checkKeys(params); err != nil {
+		return err
+	}
+	g := &generator{ctx: deployCtx, cli: p.cli, namespace: p.app.Namespace, data: data, certs: map[string]*keyPair{}, generated: []string{}}
+	if err := g.generate(params); err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{Type: params.Type, Data: data}
+	if existing != nil {
+		// the type of the secret is immutable
+		secret.Type = existing.Type
+	}
+	if secret.Type == "" {
+		secret.Type = corev1.SecretTypeOpaque
+	}
+	secret.SetName(params.Name)
+	secret.SetNamespace(params.Namespace)
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(secret)
+	if err != nil {
+		return err
+	}
+	manifest := &unstructured.Unstructured{Object: obj}
+	manifest.SetAPIVersion("v1")
+	manifest.SetKind("Secret")
+	util.AddLabels(manifest, map[string]string{
+		oam.LabelAppName:      p.app.Name,
+		oam.LabelAppNamespace: p.app.Namespace,
+	})
+	// the generated data is not recorded in the resourcetracker, it's readable to anyone who can read resourcetrackers
+	applyCtx := resourcekeeper.ContextWithDispatchOptions(deployCtx, resourcekeeper.MetaOnlyOption{})
+	if err := p.apply(applyCtx, params.Cluster, common.WorkflowResourceCreator, manifest); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	keys = sortedKeys(keys)
+	public := map[string]string{}
+	for name := range params.KeyPairs {
+		public[publicKey(name)] = string(data[publicKey(name)])
+	}
+	for name := range params.Certificates {
+		public[name+".crt"] = string(data[name+".crt"])
+	}
+	return v.FillObject(map[string]interface{}{
+		"keys":      keys,
+		"generated": g.generated,
+		"public":    public,
+	}, "result")
+}
+
+func privateKey(keyPair string) string { return keyPair + ".pem" }
+
+func publicKey(keyPair string) string { return keyPair + ".pub" }
+
+func certificateKeys(certificate string) (string, string) {
+	return certificate + ".crt", certificate + ".key"
+}
+
+// checkKeys checks that the strings, key pairs and certificates are stored in distinct keys of the secret, so none of
+// them overwrites another
+func checkKeys(params *GenerateParams) error {
+	owners := map[string]string{}
+	add := func(owner string, keys ...string) error {
+		for _, key := range keys {
+			if another, ok := owners[key]; ok {
+				return errors.Errorf("key %s of the secret is generated by both %s and %s", key, another, owner)
+			}
+			owners[key] = owner
+		}
+		return nil
+	}
+	for name := range params.Strings {
+		if err := add("string "+name, name); err != nil {
+			return err
+		}
+	}
+	for name := range params.KeyPairs {
+		if err := add("key pair "+name, privateKey(name), publicKey(name)); err != nil {
+			return err
+		}
+	}
+	for name := range params.Certificates {
+		certKey, keyKey := certificateKeys(name)
+		if err := add("certificate "+name, certKey, keyKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type keyPair struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+type generator struct {
+	ctx       context.Context
+	cli       client.Client
+	namespace string
+	data      map[string][]byte
+	certs     map[string]*keyPair
+	generated []string
+}
+
+func (g *generator) exists(keys ...string) bool {
+	for _, k := range keys {
+		if len(g.data[k]) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *generator) generate(params *GenerateParams) error {
+	var names []string
+	for key := range params.Strings {
+		names = append(names, key)
+	}
+	for _, key := range sortedKeys(names) {
+		if g.exists(key) {
+			continue
+		}
+		s, err := RandomString(params.Strings[key])
+		if err != nil {
+			return errors.WithMessagef(err, "failed to generate string %s", key)
+		}
+		g.data[key] = []byte(s)
+		g.generated = append(g.generated, key)
+	}
+	names = nil
+	for name := range params.KeyPairs {
+		names = append(names, name)
+	}
+	for _, name := range sortedKeys(names) {
+		privKey, pubKey := privateKey(name), publicKey(name)
+		if g.exists(privKey, pubKey) {
+			continue
+		}
+		key, err := GenerateKey(params.KeyPairs[name])
+		if err != nil {
+			return errors.WithMessagef(err, "failed to generate key pair %s", name)
+		}
+		keyPEM, err := encodePrivateKey(key)
+		if err != nil {
+			return err
+		}
+		pubDER, err := x509.MarshalPKIXPublicKey(key.Public())
+		if err != nil {
+			return err
+		}
+		g.data[privKey] = keyPEM
+		g.data[pubKey] = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+		g.generated = append(g.generated, privKey, pubKey)
+	}
+	// the issuers are generated before the certificates they issue
+	visiting := map[string]bool{}
+	var generateCert func(name string) error
+	generateCert = func(name string) error {
+		if _, ok := g.certs[name]; ok {
+			return nil
+		}
+		spec, ok := params.Certificates[name]
+		if !ok {
+			return errors.Errorf("issuer %s is not found in the certificates", name)
+		}
+		if visiting[name] {
+			return errors.Errorf("circular issuer of certificate %s", name)
+		}
+		visiting[name] = true
+		if spec.Issuer != "" {
+			if err := generateCert(spec.Issuer); err != nil {
+				return err
+			}
+		}
+		kp, err := g.certificate(name, spec)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to generate certificate %s", name)
+		}
+		g.certs[name] = kp
+		return nil
+	}
+	names = nil
+	for name := range params.Certificates {
+		names = append(names, name)
+	}
+	for _, name := range sortedKeys(names) {
+		if err := generateCert(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) certificate(name string, spec CertificateSpec) (*keyPair, error) {
+	certKey, keyKey := certificateKeys(name)
+	if g.exists(certKey, keyKey) {
+		return parseKeyPair(g.data[certKey], g.data[keyKey])
+	}
+	var issuer *keyPair
+	var err error
+	switch {
+	case spec.Issuer != "":
+		issuer = g.certs[spec.Issuer]
+	case spec.IssuerRef != nil:
+		if issuer, err = g.loadIssuer(spec.IssuerRef); err != nil {
+			return nil, err
+		}
+	default:
+	}
+	certPEM, key, err := GenerateCertificate(spec, issuer)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := encodePrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	g.data[certKey] = certPEM
+	g.data[keyKey] = keyPEM
+	g.generated = append(g.generated, certKey, keyKey)
+	return parseKeyPair(certPEM, keyPEM)
+}
+
+// loadIssuer reads the CA from the secret in the namespace of the application, the CAs of the other namespaces can't
+// be used, otherwise any application could issue certificates with the CA of another tenant
+func (g *generator) loadIssuer(ref *IssuerRef) (*keyPair, error) {
+	key := client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}
+	if key.Namespace == "" {
+		key.Namespace = g.namespace
+	}
+	if key.Namespace != g.namespace {
+		return nil, errors.Errorf("issuer secret %s is outside the namespace %s of the application", key, g.namespace)
+	}
+	secret := &corev1.Secret{}
+	if err := g.cli.Get(g.ctx, key, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to get issuer secret %s", key)
+	}
+	return parseKeyPair(secret.Data[ref.CertKey], secret.Data[ref.KeyKey])
+}
+
+// RandomString generates a random string with the policy
+func RandomString(spec RandomStringSpec) (string, error) {
+	var charsets []string
+	if spec.Lowercase {
+		charsets = append(charsets, lowercaseChars)
+	}
+	if spec.Uppercase {
+		charsets = append(charsets, uppercaseChars)
+	}
+	if spec.Digits {
+		charsets = append(charsets, digitChars)
+	}
+	if spec.Symbols && spec.SymbolCharset != "" {
+		charsets = append(charsets, spec.SymbolCharset)
+	}
+	if len(charsets) == 0 {
+		return "", errors.New("at least one character set must be enabled")
+	}
+	if spec.Length < len(charsets) {
+		return "", errors.Errorf("length must be at least %d to contain all the enabled character sets", len(charsets))
+	}
+	all := ""
+	result := make([]byte, 0, spec.Length)
+	for _, cs := range charsets {
+		all += cs
+		c, err := randomChar(cs)
+		if err != nil {
+			return "", err
+		}
+		result = append(result, c)
+	}
+	for len(result) < spec.Length {
+		c, err := randomChar(all)
+		if err != nil {
+			return "", err
+		}
+		result = append(result, c)
+	}
+	// shuffle so that the guaranteed characters are not always at the beginning
+	for i := len(result) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		result[i], result[j.Int64()] = result[j.Int64()], result[i]
+	}
+	return string(result), nil
+}
+
+func randomChar(charset string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+	if err != nil {
+		return 0, err
+	}
+	return charset[n.Int64()], nil
+}
+
+// GenerateKey generates the private key by the spec
+func GenerateKey(spec KeySpec) (crypto.Signer, error) {
+	switch spec.Algorithm {
+	case AlgorithmRSA, "":
+		bits := spec.Bits
+		if bits == 0 {
+			bits = 2048
+		}
+		if bits < 2048 {
+			return nil, errors.Errorf("RSA key size %d is too small, at least 2048", bits)
+		}
+		return rsa.GenerateKey(rand.Reader, bits)
+	case AlgorithmECDSA:
+		var curve elliptic.Curve
+		switch spec.Curve {
+		case "P256", "":
+			curve = elliptic.P256()
+		case "P384":
+			curve = elliptic.P384()
+		case "P521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported curve %s", spec.Curve)
+		}
+		return ecdsa.GenerateKey(curve, rand.Reader)
+	default:
+		return nil, errors.Errorf("unsupported algorithm %s", spec.Algorithm)
+	}
+}
+
+// GenerateCertificate generates the certificate signed by the issuer, or self-signed if the issuer is nil.
+// The certificate is returned in PEM format along with its private key.
+func GenerateCertificate(spec CertificateSpec, issuer *keyPair) ([]byte, crypto.Signer, error) {
+	validity := 365 * 24 * time.Hour
+	if spec.Validity != "" {
+		var err error
+		if validity, err = time.ParseDuration(spec.Validity); err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid validity %s", spec.Validity)
+		}
+	}
+	key, err := GenerateKey(spec.KeySpec)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: spec.CommonName, Organization: spec.Organization},
+		DNSNames:     spec.DNSNames,
+		NotBefore:    now.Add(-5 * time.Minute),
+		NotAfter:     now.Add(validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+	}
+	for _, ip := range spec.IPAddresses {
+		addr := net.ParseIP(ip)
+		if addr == nil {
+			return nil, nil, errors.Errorf("invalid ip address %s", ip)
+		}
+		tmpl.IPAddresses = append(tmpl.IPAddresses, addr)
+	}
+	if spec.IsCA {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	} else {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	}
+	parent, signer := tmpl, key
+	if issuer != nil {
+		if !issuer.cert.IsCA {
+			return nil, nil, errors.Errorf("issuer %s is not a CA", issuer.cert.Subject.CommonName)
+		}
+		parent, signer = issuer.cert, issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, key.Public(), signer)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), key, nil
+}
+
+func encodePrivateKey(key crypto.Signer) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+func parseKeyPair(certPEM, keyPEM []byte) (*keyPair, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, errors.New("invalid certificate")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid certificate")
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, errors.New("invalid private key")
+	}
+	var key interface{}
+	switch keyBlock.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(keyBlock.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid private key")
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key")
+	}
+	return &keyPair{cert: cert, key: signer}, nil
+}
+
+func sortedKeys(keys []string) []string {
+	sort.Strings(keys)
+	return keys
+}
+
+// Install register handlers to provider discover.
+func Install(p providers.Providers, app *v1beta1.Application, cli client.Client, apply kube.Dispatcher) {
+	prd := &provider{app: app, cli: cli, apply: apply}
+	p.Register(ProviderName, map[string]providers.Handler{
+		"generate": prd.Generate,
+	})
+}
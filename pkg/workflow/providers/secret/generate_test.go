@@ -0,0 +1,198 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/resourcekeeper"
+	utilscommon "github.com/oam-dev/kubevela/pkg/utils/common"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers/mock"
+)
+
+func TestGenerate(t *testing.T) {
+	r := require.New(t)
+	cli := fake.NewClientBuilder().Build()
+	var clusters []string
+	dispatch := mock.NewDispatcher(cli)
+	p := &provider{
+		app: &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}},
+		cli: cli,
+		apply: func(ctx context.Context, cluster string, owner common.ResourceCreatorRole, manifests ...*unstructured.Unstructured) error {
+			clusters = append(clusters, cluster)
+			return dispatch(ctx, cluster, owner, manifests...)
+		},
+	}
+	params := `
+name: "app-secret"
+type: "Opaque"
+strings: password: {length: 24, lowercase: true, uppercase: true, digits: true, symbols: true, symbolCharset: "!@"}
+keyPairs: {
+	rsa: {algorithm: "RSA", bits: 2048}
+	ec: {algorithm: "ECDSA", curve: "P384"}
+}
+certificates: {
+	ca: {commonName: "root", isCA: true, algorithm: "ECDSA", validity: "87600h"}
+	tls: {commonName: "server", dnsNames: ["server.default.svc"], ipAddresses: ["10.0.0.1"], issuer: "ca", algorithm: "ECDSA", validity: "24h"}
+}
+`
+	generate := func() (*corev1.Secret, []string) {
+		v, err := value.NewValue(params, nil, "")
+		r.NoError(err)
+		r.NoError(p.Generate(nil, v, &mock.Action{}))
+		result, err := v.LookupValue("result")
+		r.NoError(err)
+		out := struct {
+			Keys      []string          `json:"keys"`
+			Generated []string          `json:"generated"`
+			Public    map[string]string `json:"public"`
+		}{}
+		r.NoError(result.UnmarshalTo(&out))
+		r.Equal([]string{"ca.crt", "ca.key", "ec.pem", "ec.pub", "password", "rsa.pem", "rsa.pub", "tls.crt", "tls.key"}, out.Keys)
+		r.Equal(4, len(out.Public))
+		r.Contains(out.Public["tls.crt"], "BEGIN CERTIFICATE")
+		secret := &corev1.Secret{}
+		r.NoError(cli.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "app-secret"}, secret))
+		return secret, out.Generated
+	}
+
+	secret, generated := generate()
+	r.Equal(9, len(generated))
+	r.Equal("app", secret.Labels[oam.LabelAppName])
+	r.Equal(corev1.SecretTypeOpaque, secret.Type)
+	password := string(secret.Data["password"])
+	r.Equal(24, len(password))
+	for _, charset := range []string{lowercaseChars, uppercaseChars, digitChars, "!@"} {
+		r.True(strings.ContainsAny(password, charset), charset)
+	}
+	ca, err := parseKeyPair(secret.Data["ca.crt"], secret.Data["ca.key"])
+	r.NoError(err)
+	r.True(ca.cert.IsCA)
+	tls, err := parseKeyPair(secret.Data["tls.crt"], secret.Data["tls.key"])
+	r.NoError(err)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	_, err = tls.cert.Verify(x509.VerifyOptions{DNSName: "server.default.svc", Roots: pool})
+	r.NoError(err)
+	block, _ := pem.Decode(secret.Data["rsa.pub"])
+	r.NotNil(block)
+	_, err = x509.ParsePKIXPublicKey(block.Bytes)
+	r.NoError(err)
+
+	// the existing data is kept, and only the missing keys are generated
+	delete(secret.Data, "ec.pub")
+	r.NoError(cli.Update(context.Background(), secret))
+	again, generated := generate()
+	r.Equal([]string{"ec.pem", "ec.pub"}, generated)
+	r.Equal(password, string(again.Data["password"]))
+	r.Equal(string(secret.Data["tls.crt"]), string(again.Data["tls.crt"]))
+	r.Equal([]string{"", ""}, clusters)
+}
+
+func TestGenerateMetaOnly(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(utilscommon.Scheme).Build()
+	app := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Generation: 1}}
+	rk, err := resourcekeeper.NewResourceKeeper(ctx, cli, app)
+	r.NoError(err)
+	p := &provider{
+		app: app,
+		cli: cli,
+		apply: func(ctx context.Context, cluster string, owner common.ResourceCreatorRole, manifests ...*unstructured.Unstructured) error {
+			return rk.Dispatch(ctx, manifests, nil)
+		},
+	}
+	v, err := value.NewValue(`
+name: "app-secret"
+strings: password: {length: 16, lowercase: true, digits: true}
+`, nil, "")
+	r.NoError(err)
+	r.NoError(p.Generate(nil, v, &mock.Action{}))
+
+	// the generated password is not copied into the resourcetracker
+	rts := &v1beta1.ResourceTrackerList{}
+	r.NoError(cli.List(ctx, rts))
+	r.Len(rts.Items, 1)
+	r.Len(rts.Items[0].Spec.ManagedResources, 1)
+	r.Equal("app-secret", rts.Items[0].Spec.ManagedResources[0].Name)
+	r.Nil(rts.Items[0].Spec.ManagedResources[0].Data)
+}
+
+func TestGenerateWithIssuerRef(t *testing.T) {
+	r := require.New(t)
+	caCert, caKey, err := GenerateCertificate(CertificateSpec{CommonName: "root", IsCA: true}, nil)
+	r.NoError(err)
+	caKeyPEM, err := encodePrivateKey(caKey)
+	r.NoError(err)
+	cli := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "root-ca", Namespace: "default"},
+		Data:       map[string][]byte{"tls.crt": caCert, "tls.key": caKeyPEM},
+	}).Build()
+	p := &provider{
+		app:   &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}},
+		cli:   cli,
+		apply: mock.NewDispatcher(cli),
+	}
+	v, err := value.NewValue(`
+name: "app-tls"
+namespace: "prod"
+type: "kubernetes.io/tls"
+certificates: tls: {commonName: "app", validity: "1h", issuerRef: {name: "root-ca", certKey: "tls.crt", keyKey: "tls.key"}}
+`, nil, "")
+	r.NoError(err)
+	r.NoError(p.Generate(nil, v, &mock.Action{}))
+	secret := &corev1.Secret{}
+	r.NoError(cli.Get(context.Background(), client.ObjectKey{Namespace: "prod", Name: "app-tls"}, secret))
+	r.Equal(corev1.SecretTypeTLS, secret.Type)
+	tls, err := parseKeyPair(secret.Data["tls.crt"], secret.Data["tls.key"])
+	r.NoError(err)
+	ca, err := parseKeyPair(caCert, caKeyPEM)
+	r.NoError(err)
+	r.NoError(tls.cert.CheckSignatureFrom(ca.cert))
+
+	for _, params := range []string{
+		`certificates: tls: {commonName: "app"}`,
+		`name: "x", certificates: tls: {commonName: "app", issuerRef: {name: "not-exist", certKey: "tls.crt", keyKey: "tls.key"}}`,
+		`name: "x", certificates: tls: {commonName: "app", issuerRef: {name: "root-ca", namespace: "vela-system", certKey: "tls.crt", keyKey: "tls.key"}}`,
+		`name: "x", certificates: tls: {commonName: "app", issuer: "not-exist"}`,
+		`name: "x", strings: "tls.crt": {length: 16, lowercase: true}, certificates: tls: {commonName: "app"}`,
+		`name: "x", certificates: {a: {commonName: "a", isCA: true, issuer: "b"}, b: {commonName: "b", isCA: true, issuer: "a"}}`,
+		`name: "x", certificates: {a: {commonName: "a"}, b: {commonName: "b", issuer: "a"}}`,
+		`name: "x", strings: password: {length: 2, lowercase: true, uppercase: true, digits: true}`,
+		`name: "x", keyPairs: k: {algorithm: "RSA", bits: 1024}`,
+	} {
+		v, err := value.NewValue(params, nil, "")
+		r.NoError(err)
+		r.Error(p.Generate(nil, v, &mock.Action{}), params)
+	}
+}
@@ -0,0 +1,352 @@
+/*
+ Copyright 2022. The KubeVela Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"crypto/sha1" // #nosec G505, sha1 is only used to digest the data
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"strings"
+	"text/template"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Masterminds/semver/v3"
+	"github.com/Masterminds/sprig/v3"
+	"github.com/jmespath/go-jmespath"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
+	"github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+const (
+	// FormatJSON is the json format
+	FormatJSON = "json"
+	// FormatYAML is the yaml format
+	FormatYAML = "yaml"
+	// FormatTOML is the toml format
+	FormatTOML = "toml"
+)
+
+// JSONPath queries the value with the kubectl style jsonpath, such as {.items[*].metadata.name}
+func (p *provider) JSONPath(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	data, err := lookupData(v, "value")
+	if err != nil {
+		return err
+	}
+	path, err := v.GetString("path")
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(path, "{") {
+		path = "{" + path + "}"
+	}
+	jp := jsonpath.New("path").AllowMissingKeys(true)
+	if err := jp.Parse(path); err != nil {
+		return errors.Wrapf(err, "invalid jsonpath %s", path)
+	}
+	found, err := jp.FindResults(data)
+	if err != nil {
+		return err
+	}
+	results := []interface{}{}
+	for _, rs := range found {
+		for _, r := range rs {
+			results = append(results, r.Interface())
+		}
+	}
+	if len(results) == 1 {
+		if err := fillData(v, results[0], "result"); err != nil {
+			return err
+		}
+	}
+	return fillData(v, results, "results")
+}
+
+// JMESPath queries the value with the jmespath expression
+func (p *provider) JMESPath(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	data, err := lookupData(v, "value")
+	if err != nil {
+		return err
+	}
+	expr, err := v.GetString("expression")
+	if err != nil {
+		return err
+	}
+	result, err := jmespath.Search(expr, data)
+	if err != nil {
+		return errors.Wrapf(err, "invalid jmespath %s", expr)
+	}
+	if result == nil {
+		return nil
+	}
+	return fillData(v, result, "result")
+}
+
+// templateFuncMap returns the functions of sprig, except the ones reading the environment variables or resolving
+// the hosts in the controller, as helm does
+func templateFuncMap() template.FuncMap {
+	funcs := sprig.TxtFuncMap()
+	for _, name := range []string{"env", "expandenv", "getHostByName"} {
+		delete(funcs, name)
+	}
+	return funcs
+}
+
+// Template renders the go template with the data, the functions of sprig are available except env, expandenv and
+// getHostByName
+func (p *provider) Template(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	text, err := v.GetString("template")
+	if err != nil {
+		return err
+	}
+	var data interface{}
+	if _, err := v.LookupValue("data"); err == nil {
+		if data, err = lookupData(v, "data"); err != nil {
+			return err
+		}
+	}
+	tmpl := template.New("template").Funcs(templateFuncMap())
+	if strict, err := v.GetBool("strict"); err == nil && strict {
+		tmpl = tmpl.Option("missingkey=error")
+	}
+	if tmpl, err = tmpl.Parse(text); err != nil {
+		return errors.Wrap(err, "invalid template")
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return errors.Wrap(err, "render template")
+	}
+	return v.FillObject(buf.String(), "result")
+}
+
+// Encode encodes the input string with base64, base64url or hex
+func (p *provider) Encode(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	input, encoding, err := getInputAndKind(v, "encoding")
+	if err != nil {
+		return err
+	}
+	var output string
+	switch encoding {
+	case "base64":
+		output = base64.StdEncoding.EncodeToString([]byte(input))
+	case "base64url":
+		output = base64.URLEncoding.EncodeToString([]byte(input))
+	case "hex":
+		output = hex.EncodeToString([]byte(input))
+	default:
+		return errors.Errorf("unsupported encoding %s", encoding)
+	}
+	return v.FillObject(output, "output")
+}
+
+// Decode decodes the input string with base64, base64url or hex
+func (p *provider) Decode(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	input, encoding, err := getInputAndKind(v, "encoding")
+	if err != nil {
+		return err
+	}
+	var output []byte
+	switch encoding {
+	case "base64":
+		output, err = base64.StdEncoding.DecodeString(input)
+	case "base64url":
+		output, err = base64.URLEncoding.DecodeString(input)
+	case "hex":
+		output, err = hex.DecodeString(input)
+	default:
+		return errors.Errorf("unsupported encoding %s", encoding)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "decode %s", encoding)
+	}
+	return v.FillObject(string(output), "output")
+}
+
+// Hash digests the input string, the output is hex encoded
+func (p *provider) Hash(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	input, algorithm, err := getInputAndKind(v, "algorithm")
+	if err != nil {
+		return err
+	}
+	var h hash.Hash
+	switch algorithm {
+	case "sha1":
+		h = sha1.New() // #nosec G401
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return errors.Errorf("unsupported algorithm %s", algorithm)
+	}
+	h.Write([]byte(input))
+	return v.FillObject(hex.EncodeToString(h.Sum(nil)), "output")
+}
+
+// Marshal encodes the value in json, yaml or toml format
+func (p *provider) Marshal(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	data, err := lookupData(v, "value")
+	if err != nil {
+		return err
+	}
+	format, err := v.GetString("format")
+	if err != nil {
+		return err
+	}
+	var output []byte
+	switch format {
+	case FormatJSON:
+		output, err = json.Marshal(data)
+	case FormatYAML:
+		output, err = yaml.Marshal(data)
+	case FormatTOML:
+		buf := &bytes.Buffer{}
+		err = toml.NewEncoder(buf).Encode(data)
+		output = buf.Bytes()
+	default:
+		return errors.Errorf("unsupported format %s", format)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "marshal %s", format)
+	}
+	return v.FillObject(string(output), "output")
+}
+
+// Unmarshal decodes the input string in json, yaml or toml format
+func (p *provider) Unmarshal(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	input, format, err := getInputAndKind(v, "format")
+	if err != nil {
+		return err
+	}
+	var data interface{}
+	switch format {
+	case FormatJSON:
+		err = json.Unmarshal([]byte(input), &data)
+	case FormatYAML:
+		err = yaml.Unmarshal([]byte(input), &data)
+	case FormatTOML:
+		_, err = toml.Decode(input, &data)
+	default:
+		return errors.Errorf("unsupported format %s", format)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "unmarshal %s", format)
+	}
+	return fillData(v, data, "value")
+}
+
+// CompareVersion compares the semantic versions, the result is -1, 0 or 1 if the version is
+// less than, equal to or greater than the target
+func (p *provider) CompareVersion(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	version, err := parseVersion(v, "version")
+	if err != nil {
+		return err
+	}
+	target, err := parseVersion(v, "target")
+	if err != nil {
+		return err
+	}
+	return v.FillObject(version.Compare(target), "result")
+}
+
+// BumpVersion increases the major, minor or patch of the semantic version, the "v" prefix is kept
+func (p *provider) BumpVersion(ctx wfContext.Context, v *value.Value, act types.Action) error {
+	version, err := parseVersion(v, "version")
+	if err != nil {
+		return err
+	}
+	bump, err := v.GetString("bump")
+	if err != nil {
+		return err
+	}
+	var next semver.Version
+	switch bump {
+	case "major":
+		next = version.IncMajor()
+	case "minor":
+		next = version.IncMinor()
+	case "patch":
+		next = version.IncPatch()
+	default:
+		return errors.Errorf("unsupported bump %s", bump)
+	}
+	if prerelease, err := v.GetString("prerelease"); err == nil && prerelease != "" {
+		if next, err = next.SetPrerelease(prerelease); err != nil {
+			return errors.Wrapf(err, "invalid prerelease %s", prerelease)
+		}
+	}
+	result := next.String()
+	if strings.HasPrefix(version.Original(), "v") {
+		result = "v" + result
+	}
+	return v.FillObject(result, "result")
+}
+
+func parseVersion(v *value.Value, path string) (*semver.Version, error) {
+	s, err := v.GetString(path)
+	if err != nil {
+		return nil, err
+	}
+	version, err := semver.NewVersion(s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid version %s", s)
+	}
+	return version, nil
+}
+
+func getInputAndKind(v *value.Value, kind string) (string, string, error) {
+	input, err := v.GetString("input")
+	if err != nil {
+		return "", "", err
+	}
+	k, err := v.GetString(kind)
+	if err != nil {
+		return "", "", err
+	}
+	return input, k, nil
+}
+
+// lookupData decodes the value at the path into the json compatible data
+func lookupData(v *value.Value, path string) (interface{}, error) {
+	val, err := v.LookupValue(path)
+	if err != nil {
+		return nil, err
+	}
+	var data interface{}
+	if err := val.UnmarshalTo(&data); err != nil {
+		return nil, errors.WithMessagef(err, "decode %s", path)
+	}
+	return data, nil
+}
+
+// fillData fills the data through json, so that the integers are kept as int in cue
+func fillData(v *value.Value, data interface{}, path string) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return v.FillObject(json.RawMessage(b), path)
+}
@@ -0,0 +1,180 @@
+/*
+ Copyright 2022. The KubeVela Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"cuelang.org/go/cue"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers"
+)
+
+func TestTransform(t *testing.T) {
+	prd := &provider{}
+	testCases := map[string]struct {
+		handler  providers.Handler
+		params   string
+		path     string
+		expected string
+		hasErr   bool
+	}{
+		"jsonpath single": {
+			handler:  prd.JSONPath,
+			params:   `value: {items: [{name: "a", replicas: 1}, {name: "b", replicas: 2}]}, path: "{.items[1].replicas}"`,
+			path:     "result",
+			expected: `2`,
+		},
+		"jsonpath multiple without braces": {
+			handler:  prd.JSONPath,
+			params:   `value: {items: [{name: "a"}, {name: "b"}]}, path: ".items[*].name"`,
+			path:     "results",
+			expected: `["a","b"]`,
+		},
+		"jsonpath invalid": {
+			handler: prd.JSONPath,
+			params:  `value: {}, path: "{.items[}"`,
+			hasErr:  true,
+		},
+		"jmespath": {
+			handler:  prd.JMESPath,
+			params:   `value: {items: [{name: "a", ready: true}, {name: "b", ready: false}]}, expression: "items[?ready].name"`,
+			path:     "result",
+			expected: `["a"]`,
+		},
+		"template": {
+			handler:  prd.Template,
+			params:   `template: "{{ .name | upper }}:{{ .port }}", data: {name: "web", port: 80}`,
+			path:     "result",
+			expected: `"WEB:80"`,
+		},
+		"template strict": {
+			handler: prd.Template,
+			params:  `template: "{{ .missing }}", data: {}, strict: true`,
+			hasErr:  true,
+		},
+		"template env": {
+			handler: prd.Template,
+			params:  `template: "{{ env \"HOME\" }}"`,
+			hasErr:  true,
+		},
+		"template getHostByName": {
+			handler: prd.Template,
+			params:  `template: "{{ getHostByName \"localhost\" }}"`,
+			hasErr:  true,
+		},
+		"base64 encode": {
+			handler:  prd.Encode,
+			params:   `input: "hello", encoding: "base64"`,
+			path:     "output",
+			expected: `"aGVsbG8="`,
+		},
+		"hex decode": {
+			handler:  prd.Decode,
+			params:   `input: "68656c6c6f", encoding: "hex"`,
+			path:     "output",
+			expected: `"hello"`,
+		},
+		"invalid base64": {
+			handler: prd.Decode,
+			params:  `input: "!", encoding: "base64"`,
+			hasErr:  true,
+		},
+		"sha256": {
+			handler:  prd.Hash,
+			params:   `input: "hello", algorithm: "sha256"`,
+			path:     "output",
+			expected: `"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"`,
+		},
+		"marshal yaml": {
+			handler:  prd.Marshal,
+			params:   `value: {name: "a", ports: [80]}, format: "yaml"`,
+			path:     "output",
+			expected: `"name: a\nports:\n- 80\n"`,
+		},
+		"marshal toml": {
+			handler:  prd.Marshal,
+			params:   `value: {name: "a"}, format: "toml"`,
+			path:     "output",
+			expected: `"name = \"a\"\n"`,
+		},
+		"unmarshal json": {
+			handler:  prd.Unmarshal,
+			params:   `input: "{\"replicas\": 3}", format: "json"`,
+			path:     "value",
+			expected: `{"replicas":3}`,
+		},
+		"unmarshal toml": {
+			handler:  prd.Unmarshal,
+			params:   `input: "[server]\nport = 8080", format: "toml"`,
+			path:     "value",
+			expected: `{"server":{"port":8080}}`,
+		},
+		"unmarshal invalid yaml": {
+			handler: prd.Unmarshal,
+			params:  `input: "a: [", format: "yaml"`,
+			hasErr:  true,
+		},
+		"compare version": {
+			handler:  prd.CompareVersion,
+			params:   `version: "v1.2.10", target: "1.2.9"`,
+			path:     "result",
+			expected: `1`,
+		},
+		"bump version": {
+			handler:  prd.BumpVersion,
+			params:   `version: "v1.2.3", bump: "minor", prerelease: "rc.1"`,
+			path:     "result",
+			expected: `"v1.3.0-rc.1"`,
+		},
+		"invalid version": {
+			handler: prd.BumpVersion,
+			params:  `version: "latest", bump: "patch"`,
+			hasErr:  true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			v, err := value.NewValue(tc.params, nil, "")
+			r.NoError(err)
+			err = tc.handler(nil, v, nil)
+			if tc.hasErr {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+			result, err := v.LookupValue(tc.path)
+			r.NoError(err)
+			b, err := result.CueValue().MarshalJSON()
+			r.NoError(err)
+			r.Equal(tc.expected, string(b))
+		})
+	}
+}
+
+func TestFillDataKeepsInt(t *testing.T) {
+	r := require.New(t)
+	v, err := value.NewValue(`value: {replicas: 3}, path: "{.replicas}", result?: int`, nil, "")
+	r.NoError(err)
+	r.NoError((&provider{}).JSONPath(nil, v, nil))
+	result, err := v.LookupValue("result")
+	r.NoError(err)
+	r.Equal(cue.IntKind, result.CueValue().IncompleteKind())
+}
@@ -99,5 +99,15 @@ func Install(ctx monitorContext.Context, p providers.Providers) {
 		"patch-k8s-object": prd.PatchK8sObject,
 		"string":           prd.String,
 		"log":              prd.Log,
+		"jsonpath":         prd.JSONPath,
+		"jmespath":         prd.JMESPath,
+		"template":         prd.Template,
+		"encode":           prd.Encode,
+		"decode":           prd.Decode,
+		"hash":             prd.Hash,
+		"marshal":          prd.Marshal,
+		"unmarshal":        prd.Unmarshal,
+		"compare-version":  prd.CompareVersion,
+		"bump-version":     prd.BumpVersion,
 	})
 }
@@ -192,7 +192,7 @@ func (g *DeployPreApproveWorkflowStepGenerator) Generate(app *v1beta1.Applicatio
 				})
 			}
 		}
-		lastSuspend = step.Type == wftypes.WorkflowStepTypeSuspend
+		lastSuspend = step.Type == wftypes.WorkflowStepTypeSuspend || step.Type == wftypes.WorkflowStepTypeApproval
 		steps = append(steps, step)
 	}
 	return steps, nil
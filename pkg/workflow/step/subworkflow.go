@@ -0,0 +1,271 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package step
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	"github.com/oam-dev/kubevela/pkg/utils"
+	wftypes "github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+// SubWorkflowParameterPrefix is the prefix of the inputs that refer to the parameters of the sub-workflow
+const SubWorkflowParameterPrefix = "parameter."
+
+// SubWorkflowStepGenerator expands the sub-workflow steps into the step groups composed of the steps in the referred workflow
+type SubWorkflowStepGenerator struct {
+	context.Context
+	client.Client
+}
+
+// Generate generate workflow steps
+func (g *SubWorkflowStepGenerator) Generate(app *v1beta1.Application, existingSteps []v1beta1.WorkflowStep) (steps []v1beta1.WorkflowStep, err error) {
+	var groups []v1beta1.WorkflowStep
+	for _, step := range existingSteps {
+		if step.Type != wftypes.WorkflowStepTypeSubWorkflow {
+			steps = append(steps, step)
+			continue
+		}
+		spec, err := ParseSubWorkflowStepSpec(step)
+		if err != nil {
+			return nil, err
+		}
+		wf := &v1alpha1.Workflow{}
+		if err := g.Client.Get(g.Context, types.NamespacedName{Namespace: app.GetNamespace(), Name: spec.Ref}, wf); err != nil {
+			return nil, errors.Wrapf(err, "failed to get workflow %s of step %s", spec.Ref, step.Name)
+		}
+		group, err := ExpandSubWorkflow(step, wf, spec.Parameters)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, group)
+		groups = append(groups, group)
+	}
+	for _, group := range groups {
+		if err := ValidateExpandedStepNames(group, steps); err != nil {
+			return nil, err
+		}
+	}
+	return steps, nil
+}
+
+// ValidateExpandedStepNames checks the names of the sub steps expanded from the sub-workflow step don't collide with
+// the names of the other steps and their sub steps
+func ValidateExpandedStepNames(group v1beta1.WorkflowStep, steps []v1beta1.WorkflowStep) error {
+	names := map[string]bool{}
+	for _, step := range steps {
+		if step.Name == group.Name {
+			continue
+		}
+		names[step.Name] = true
+		for _, sub := range step.SubSteps {
+			names[sub.Name] = true
+		}
+	}
+	for _, sub := range group.SubSteps {
+		if names[sub.Name] {
+			return errors.Errorf("step %s expanded from sub-workflow step %s collides with another step", sub.Name, group.Name)
+		}
+	}
+	return nil
+}
+
+// ParseSubWorkflowStepSpec parses the properties of the sub-workflow step
+func ParseSubWorkflowStepSpec(step v1beta1.WorkflowStep) (*SubWorkflowStepSpec, error) {
+	spec := &SubWorkflowStepSpec{}
+	if step.Properties == nil {
+		return nil, errors.Errorf("the workflow of sub-workflow step %s is not set", step.Name)
+	}
+	if err := utils.StrictUnmarshal(step.Properties.Raw, spec); err != nil {
+		return nil, errors.Wrapf(err, "invalid properties of sub-workflow step %s", step.Name)
+	}
+	if spec.Ref == "" {
+		return nil, errors.Errorf("the workflow of sub-workflow step %s is not set", step.Name)
+	}
+	return spec, nil
+}
+
+// ExpandSubWorkflow expands the sub-workflow step into a step group, the sub steps are the steps of the workflow
+// prefixed with the name of the sub-workflow step, and the inputs from the parameters are filled into their properties.
+// The outputs of the sub steps are prefixed with the name of the sub-workflow step in the same way, so the outputs of
+// different instances of the workflow don't overwrite each other.
+func ExpandSubWorkflow(step v1beta1.WorkflowStep, wf *v1alpha1.Workflow, params map[string]interface{}) (v1beta1.WorkflowStep, error) {
+	resolved, err := ResolveSubWorkflowParameters(wf, params)
+	if err != nil {
+		return v1beta1.WorkflowStep{}, errors.WithMessagef(err, "invalid parameters of sub-workflow step %s", step.Name)
+	}
+	paramsValue, err := makeParametersValue(resolved)
+	if err != nil {
+		return v1beta1.WorkflowStep{}, err
+	}
+	names, outputs := map[string]bool{}, map[string]bool{}
+	for _, s := range wf.Steps {
+		names[s.Name] = true
+		for _, output := range s.Outputs {
+			outputs[output.Name] = true
+		}
+	}
+	group := v1beta1.WorkflowStep{
+		Name:      step.Name,
+		Type:      wftypes.WorkflowStepTypeStepGroup,
+		Meta:      step.Meta,
+		If:        step.If,
+		Timeout:   step.Timeout,
+		DependsOn: step.DependsOn,
+	}
+	for _, s := range wf.Steps {
+		if len(s.SubSteps) > 0 || s.Type == wftypes.WorkflowStepTypeStepGroup || s.Type == wftypes.WorkflowStepTypeSubWorkflow {
+			return v1beta1.WorkflowStep{}, errors.Errorf("step %s of workflow %s cannot be used in sub-workflow, the nested step groups are not supported", s.Name, wf.Name)
+		}
+		sub := common.WorkflowSubStep{
+			Name:       step.Name + "-" + s.Name,
+			Type:       s.Type,
+			Meta:       s.Meta,
+			Properties: s.Properties,
+			If:         s.If,
+			Timeout:    s.Timeout,
+			Retry:      s.Retry,
+			Cache:      s.Cache,
+		}
+		for _, output := range s.Outputs {
+			output.Name = step.Name + "-" + output.Name
+			sub.Outputs = append(sub.Outputs, output)
+		}
+		for _, dep := range s.DependsOn {
+			if names[dep] {
+				dep = step.Name + "-" + dep
+			}
+			sub.DependsOn = append(sub.DependsOn, dep)
+		}
+		for _, input := range s.Inputs {
+			if !strings.HasPrefix(input.From, SubWorkflowParameterPrefix) {
+				if outputs[strings.Split(input.From, ".")[0]] {
+					input.From = step.Name + "-" + input.From
+				}
+				sub.Inputs = append(sub.Inputs, input)
+				continue
+			}
+			if sub.Properties, err = fillParameter(sub.Properties, paramsValue, input.From, input.ParameterKey); err != nil {
+				return v1beta1.WorkflowStep{}, errors.WithMessagef(err, "step %s of workflow %s", s.Name, wf.Name)
+			}
+		}
+		group.SubSteps = append(group.SubSteps, sub)
+	}
+	return group, nil
+}
+
+// ResolveSubWorkflowParameters checks the parameters against the ones declared by the workflow, and returns
+// the parameters with the default values of the unset ones.
+func ResolveSubWorkflowParameters(wf *v1alpha1.Workflow, params map[string]interface{}) (map[string]interface{}, error) {
+	resolved := map[string]interface{}{}
+	declared := map[string]bool{}
+	for _, p := range wf.Parameters {
+		declared[p.Name] = true
+		v, ok := params[p.Name]
+		if !ok && p.Default != nil && len(p.Default.Raw) > 0 {
+			if err := json.Unmarshal(p.Default.Raw, &v); err != nil {
+				return nil, errors.Wrapf(err, "invalid default value of parameter %s", p.Name)
+			}
+			ok = true
+		}
+		if !ok {
+			if p.Required {
+				return nil, errors.Errorf("parameter %s is required", p.Name)
+			}
+			continue
+		}
+		if !isParameterType(p.Type, v) {
+			return nil, errors.Errorf("parameter %s must be %s", p.Name, p.Type)
+		}
+		resolved[p.Name] = v
+	}
+	for name := range params {
+		if !declared[name] {
+			return nil, errors.Errorf("parameter %s is not declared in workflow %s", name, wf.Name)
+		}
+	}
+	return resolved, nil
+}
+
+func isParameterType(typ v1alpha1.WorkflowParameterType, v interface{}) bool {
+	switch typ {
+	case v1alpha1.WorkflowParameterTypeString:
+		_, ok := v.(string)
+		return ok
+	case v1alpha1.WorkflowParameterTypeNumber:
+		_, ok := v.(float64)
+		return ok
+	case v1alpha1.WorkflowParameterTypeInteger:
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case v1alpha1.WorkflowParameterTypeBoolean:
+		_, ok := v.(bool)
+		return ok
+	case v1alpha1.WorkflowParameterTypeObject:
+		_, ok := v.(map[string]interface{})
+		return ok
+	case v1alpha1.WorkflowParameterTypeArray:
+		_, ok := v.([]interface{})
+		return ok
+	default:
+		return true
+	}
+}
+
+func makeParametersValue(params map[string]interface{}) (*value.Value, error) {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	return value.NewValue(string(b), nil, "")
+}
+
+func fillParameter(properties *runtime.RawExtension, params *value.Value, from, key string) (*runtime.RawExtension, error) {
+	paths := strings.Split(strings.TrimPrefix(from, SubWorkflowParameterPrefix), ".")
+	v, err := params.LookupValue(paths...)
+	if err != nil {
+		return nil, errors.Errorf("parameter %s is not set", strings.Join(paths, "."))
+	}
+	props := "{}"
+	if properties != nil && len(properties.Raw) > 0 {
+		props = string(properties.Raw)
+	}
+	pv, err := params.MakeValue(props)
+	if err != nil {
+		return nil, err
+	}
+	if err := pv.FillValueByScript(v, key); err != nil {
+		return nil, errors.WithMessagef(err, "fill parameter %s to %s", strings.Join(paths, "."), key)
+	}
+	b, err := pv.CueValue().MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{Raw: b}, nil
+}
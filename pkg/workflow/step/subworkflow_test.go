@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package step
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	common2 "github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestSubWorkflowStepGenerator(t *testing.T) {
+	r := require.New(t)
+	cli := fake.NewClientBuilder().WithScheme(common2.Scheme).WithObjects(&v1alpha1.Workflow{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "release",
+			Namespace: "test",
+		},
+		Parameters: []v1alpha1.WorkflowParameter{{
+			Name:     "image",
+			Type:     v1alpha1.WorkflowParameterTypeString,
+			Required: true,
+		}, {
+			Name:    "replicas",
+			Type:    v1alpha1.WorkflowParameterTypeInteger,
+			Default: &runtime.RawExtension{Raw: []byte(`2`)},
+		}},
+		Steps: []common.WorkflowStep{{
+			Name: "deploy",
+			Type: "apply-deployment",
+			Inputs: common.StepInputs{{
+				From:         "parameter.image",
+				ParameterKey: "image",
+			}, {
+				From:         "parameter.replicas",
+				ParameterKey: "replicas",
+			}},
+			Properties: &runtime.RawExtension{Raw: []byte(`{"name":"server"}`)},
+			Outputs:    common.StepOutputs{{Name: "revision", ValueFrom: "output.revision"}},
+		}, {
+			Name:      "notify",
+			Type:      "notification",
+			DependsOn: []string{"deploy"},
+			Inputs:    common.StepInputs{{From: "revision.name", ParameterKey: "revision"}, {From: "approver", ParameterKey: "approver"}},
+		}},
+	}).Build()
+	generator := &SubWorkflowStepGenerator{Context: context.Background(), Client: cli}
+	app := &v1beta1.Application{ObjectMeta: v1.ObjectMeta{Namespace: "test"}}
+
+	steps, err := generator.Generate(app, []v1beta1.WorkflowStep{{
+		Name: "approve",
+		Type: "suspend",
+	}, {
+		Name:       "prod",
+		Type:       "sub-workflow",
+		DependsOn:  []string{"approve"},
+		Properties: &runtime.RawExtension{Raw: []byte(`{"ref":"release","parameters":{"image":"nginx"}}`)},
+	}})
+	r.NoError(err)
+	r.Equal(2, len(steps))
+	r.Equal("approve", steps[0].Name)
+	r.Equal("prod", steps[1].Name)
+	r.Equal("step-group", steps[1].Type)
+	r.Equal([]string{"approve"}, steps[1].DependsOn)
+	r.Equal(2, len(steps[1].SubSteps))
+	r.Equal("prod-deploy", steps[1].SubSteps[0].Name)
+	r.Equal(0, len(steps[1].SubSteps[0].Inputs))
+	r.JSONEq(`{"name":"server","image":"nginx","replicas":2}`, string(steps[1].SubSteps[0].Properties.Raw))
+	r.Equal(common.StepOutputs{{Name: "prod-revision", ValueFrom: "output.revision"}}, steps[1].SubSteps[0].Outputs)
+	r.Equal("prod-notify", steps[1].SubSteps[1].Name)
+	r.Equal([]string{"prod-deploy"}, steps[1].SubSteps[1].DependsOn)
+	r.Equal(common.StepInputs{{From: "prod-revision.name", ParameterKey: "revision"}, {From: "approver", ParameterKey: "approver"}}, steps[1].SubSteps[1].Inputs)
+
+	_, err = generator.Generate(app, []v1beta1.WorkflowStep{{
+		Name: "prod-deploy",
+		Type: "suspend",
+	}, {
+		Name:       "prod",
+		Type:       "sub-workflow",
+		Properties: &runtime.RawExtension{Raw: []byte(`{"ref":"release","parameters":{"image":"nginx"}}`)},
+	}})
+	r.Error(err)
+	r.Contains(err.Error(), "step prod-deploy expanded from sub-workflow step prod collides with another step")
+
+	testCases := map[string]struct {
+		properties string
+		errMsg     string
+	}{
+		"missing-ref": {
+			properties: `{"parameters":{"image":"nginx"}}`,
+			errMsg:     "the workflow of sub-workflow step prod is not set",
+		},
+		"not-found": {
+			properties: `{"ref":"not-exist"}`,
+			errMsg:     "failed to get workflow not-exist",
+		},
+		"missing-required": {
+			properties: `{"ref":"release"}`,
+			errMsg:     "parameter image is required",
+		},
+		"invalid-type": {
+			properties: `{"ref":"release","parameters":{"image":"nginx","replicas":1.5}}`,
+			errMsg:     "parameter replicas must be integer",
+		},
+		"undeclared": {
+			properties: `{"ref":"release","parameters":{"image":"nginx","port":80}}`,
+			errMsg:     "parameter port is not declared in workflow release",
+		},
+	}
+	for name, tt := range testCases {
+		_, err := generator.Generate(app, []v1beta1.WorkflowStep{{
+			Name:       "prod",
+			Type:       "sub-workflow",
+			Properties: &runtime.RawExtension{Raw: []byte(tt.properties)},
+		}})
+		r.Error(err, name)
+		r.Contains(err.Error(), tt.errMsg, name)
+	}
+}
@@ -32,3 +32,11 @@ type DeployWorkflowStepSpec struct {
 	// IgnoreTerraformComponent default is true, true means this step will apply the components without the terraform workload.
 	IgnoreTerraformComponent *bool `json:"ignoreTerraformComponent,omitempty"`
 }
+
+// SubWorkflowStepSpec the spec of `sub-workflow` WorkflowStep
+type SubWorkflowStepSpec struct {
+	// Ref is the name of the Workflow in the namespace of the application
+	Ref string `json:"ref"`
+	// Parameters are the values of the parameters declared by the Workflow
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
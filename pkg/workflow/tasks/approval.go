@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/cue/packages"
+	"github.com/oam-dev/kubevela/pkg/cue/process"
+	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
+	"github.com/oam-dev/kubevela/pkg/workflow/tasks/custom"
+	"github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+// Approval is the approval step runner, it suspends the workflow until the step is approved by enough approvers
+func Approval(step v1beta1.WorkflowStep, opt *types.GeneratorOptions) (types.TaskRunner, error) {
+	return &approvalTaskRunner{
+		id:   opt.ID,
+		step: step,
+		pd:   opt.PackageDiscover,
+		pCtx: opt.ProcessContext,
+	}, nil
+}
+
+type approvalTaskRunner struct {
+	id   string
+	step v1beta1.WorkflowStep
+	pd   *packages.PackageDiscover
+	pCtx process.Context
+}
+
+// Name return approval step name.
+func (tr *approvalTaskRunner) Name() string {
+	return tr.step.Name
+}
+
+// Run checks the approvals of the step, the workflow is suspended until the step is approved, rejected or expired.
+func (tr *approvalTaskRunner) Run(ctx wfContext.Context, options *types.TaskRunOptions) (stepStatus common.StepStatus, operations *types.Operation, rErr error) {
+	stepStatus = common.StepStatus{
+		ID:    tr.id,
+		Name:  tr.step.Name,
+		Type:  types.WorkflowStepTypeApproval,
+		Phase: common.WorkflowStepPhaseRunning,
+	}
+	operations = &types.Operation{Suspend: true}
+
+	status := &stepStatus
+	defer handleOutput(ctx, status, operations, tr.step, options.PostStopHooks, tr.pd, tr.id, tr.pCtx)
+
+	if handleSuspendPreCheck(tr.step, status, operations, options, tr.pd, tr.pCtx) {
+		return stepStatus, operations, nil
+	}
+
+	firstExecuteTime := time.Now()
+	if options.Engine != nil {
+		ss := options.Engine.GetCommonStepStatus(tr.step.Name)
+		if !ss.FirstExecuteTime.IsZero() {
+			firstExecuteTime = ss.FirstExecuteTime.Time
+		}
+		// the approvals are recorded by the resume operations, keep them in the status
+		stepStatus.Approvals = ss.Approvals
+	}
+	props, err := types.GetApprovalProperties(tr.step)
+	if err != nil {
+		stepStatus.Message = fmt.Sprintf("invalid approval properties: %s", err.Error())
+		return stepStatus, operations, nil
+	}
+
+	quorum := props.GetQuorum()
+	approved, rejection := props.CountApprovals(stepStatus.Approvals)
+	switch {
+	case rejection != nil:
+		stepStatus.Phase = common.WorkflowStepPhaseFailed
+		stepStatus.Reason = types.StatusReasonRejected
+		stepStatus.Message = fmt.Sprintf("rejected by %s", rejection.Approver)
+		if rejection.Message != "" {
+			stepStatus.Message = fmt.Sprintf("%s: %s", stepStatus.Message, rejection.Message)
+		}
+		operations.Suspend = false
+		operations.Terminated = true
+		return stepStatus, operations, nil
+	case approved >= quorum:
+		stepStatus.Phase = common.WorkflowStepPhaseSucceeded
+		stepStatus.Message = fmt.Sprintf("approved by %d/%d approvers", approved, quorum)
+		operations.Suspend = false
+		return stepStatus, operations, nil
+	default:
+	}
+
+	stepStatus.Message = fmt.Sprintf("waiting for approvals, %d/%d approved", approved, quorum)
+	if props.Expiration != "" {
+		expiration, err := types.GetStepDeadline(props.Expiration, firstExecuteTime)
+		if err != nil {
+			stepStatus.Message = fmt.Sprintf("invalid approval expiration: %s", err.Error())
+			return stepStatus, operations, nil
+		}
+		if !time.Now().Before(expiration) {
+			stepStatus.Phase = common.WorkflowStepPhaseFailed
+			stepStatus.Reason = types.StatusReasonExpired
+			stepStatus.Message = fmt.Sprintf("approval expired, %d/%d approved", approved, quorum)
+			operations.Suspend = false
+			operations.Terminated = true
+			return stepStatus, operations, nil
+		}
+		next := metav1.NewTime(expiration)
+		stepStatus.NextExecuteTime = &next
+	}
+	return stepStatus, operations, nil
+}
+
+// Pending check task should be executed or not.
+func (tr *approvalTaskRunner) Pending(ctx wfContext.Context, stepStatus map[string]common.StepStatus) (bool, common.StepStatus) {
+	return custom.CheckPending(ctx, tr.step, tr.id, stepStatus)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+func TestApprovalStep(t *testing.T) {
+	r := require.New(t)
+	runner, err := Approval(v1beta1.WorkflowStep{
+		Name:       "approve",
+		Type:       types.WorkflowStepTypeApproval,
+		Properties: &runtime.RawExtension{Raw: []byte(`{"approvers":["alice","bob"],"groups":["sre"],"quorum":2,"expiration":"1h"}`)},
+	}, &types.GeneratorOptions{ID: "123"})
+	r.NoError(err)
+	r.Equal("approve", runner.Name())
+
+	approval := func(approver string, groups []string, action common.WorkflowStepApprovalAction) common.WorkflowStepApproval {
+		return common.WorkflowStepApproval{Approver: approver, Groups: groups, Action: action, Time: metav1.Now()}
+	}
+	firstExecuteTime := metav1.NewTime(time.Now().Add(-time.Minute))
+	testCases := map[string]struct {
+		firstExecuteTime metav1.Time
+		approvals        []common.WorkflowStepApproval
+		phase            common.WorkflowStepPhase
+		reason           string
+		suspend          bool
+		terminated       bool
+	}{
+		"waiting for approvals": {
+			firstExecuteTime: firstExecuteTime,
+			approvals: []common.WorkflowStepApproval{
+				approval("alice", nil, common.WorkflowStepApprovalApprove),
+				approval("alice", nil, common.WorkflowStepApprovalApprove),
+				approval("eve", nil, common.WorkflowStepApprovalApprove),
+			},
+			phase:   common.WorkflowStepPhaseRunning,
+			suspend: true,
+		},
+		"approved": {
+			firstExecuteTime: firstExecuteTime,
+			approvals: []common.WorkflowStepApproval{
+				approval("alice", nil, common.WorkflowStepApprovalApprove),
+				approval("carol", []string{"sre"}, common.WorkflowStepApprovalApprove),
+			},
+			phase: common.WorkflowStepPhaseSucceeded,
+		},
+		"rejected": {
+			firstExecuteTime: firstExecuteTime,
+			approvals: []common.WorkflowStepApproval{
+				approval("alice", nil, common.WorkflowStepApprovalApprove),
+				approval("bob", nil, common.WorkflowStepApprovalReject),
+			},
+			phase:      common.WorkflowStepPhaseFailed,
+			reason:     types.StatusReasonRejected,
+			terminated: true,
+		},
+		"expired": {
+			firstExecuteTime: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+			approvals: []common.WorkflowStepApproval{
+				approval("alice", nil, common.WorkflowStepApprovalApprove),
+			},
+			phase:      common.WorkflowStepPhaseFailed,
+			reason:     types.StatusReasonExpired,
+			terminated: true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			engine := &testEngine{stepStatus: common.WorkflowStepStatus{StepStatus: common.StepStatus{
+				Name:             "approve",
+				FirstExecuteTime: tc.firstExecuteTime,
+				Approvals:        tc.approvals,
+			}}}
+			status, operations, err := runner.Run(nil, &types.TaskRunOptions{Engine: engine})
+			r.NoError(err)
+			r.Equal(tc.phase, status.Phase)
+			r.Equal(tc.reason, status.Reason)
+			r.Equal(tc.suspend, operations.Suspend)
+			r.Equal(tc.terminated, operations.Terminated)
+			r.Equal(tc.approvals, status.Approvals)
+			if tc.suspend {
+				r.NotNil(status.NextExecuteTime)
+			}
+		})
+	}
+}
+
+func TestApprovalEligibility(t *testing.T) {
+	r := require.New(t)
+	anyone := &types.ApprovalProperties{}
+	r.True(anyone.IsEligible("alice", nil))
+	r.False(anyone.IsEligible(types.ApproverWorkflowCallback, nil))
+
+	restricted := &types.ApprovalProperties{Groups: []string{"sre"}}
+	r.True(restricted.IsEligible("alice", []string{"sre"}))
+	r.False(restricted.IsEligible(types.ApproverWorkflowCallback, []string{"sre"}))
+
+	listed := &types.ApprovalProperties{Approvers: []string{types.ApproverWorkflowCallback}}
+	r.True(listed.IsEligible(types.ApproverWorkflowCallback, nil))
+	r.False(listed.IsEligible("alice", nil))
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/cue/model"
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
+	"github.com/oam-dev/kubevela/pkg/cue/process"
+	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
+	wfTypes "github.com/oam-dev/kubevela/pkg/workflow/types"
+)
+
+// stepCache is the input hash and the outputs of the last succeeded execution of the step, it's kept
+// in the workflow context across the restarts of the workflow.
+type stepCache struct {
+	Hash    string            `json:"hash"`
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+// computeStepCacheHash hashes the template and the rendered parameter of the step, which contains the inputs, along
+// with the application revision and the generation of the application. The application revision pins the definitions
+// that the step is rendered with, and the generation changes with the components and the policies, so the steps
+// reading them are executed again once any of them is changed.
+func computeStepCacheHash(ctx wfContext.Context, pCtx process.Context, templ string, paramFile string) string {
+	var revision, generation string
+	if pCtx != nil {
+		revision = pCtx.BaseContextLabels()[model.ContextAppRevision]
+	}
+	if v, err := ctx.GetVar(wfTypes.ContextKeyMetadata, "generation"); err == nil {
+		generation, _ = v.String()
+	}
+	h := sha256.New()
+	for _, s := range []string{templ, paramFile, revision, generation} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// loadStepCache returns the cache of the step, nil is returned if the step is not cached
+func loadStepCache(ctx wfContext.Context, step v1beta1.WorkflowStep) *stepCache {
+	data := ctx.GetMutableValue(wfTypes.ContextPrefixStepCache, step.Name)
+	if data == "" {
+		return nil
+	}
+	cache := &stepCache{}
+	if err := json.Unmarshal([]byte(data), cache); err != nil {
+		return nil
+	}
+	return cache
+}
+
+// restoreStepCache sets the cached outputs of the step to the workflow context
+func restoreStepCache(ctx wfContext.Context, step v1beta1.WorkflowStep, cache *stepCache) error {
+	for _, output := range step.Outputs {
+		s, ok := cache.Outputs[output.Name]
+		if !ok {
+			return errors.Errorf("output %s is not cached", output.Name)
+		}
+		v, err := value.NewValue(fmt.Sprintf("output: %s", s), nil, "")
+		if err != nil {
+			return errors.WithMessagef(err, "restore output %s", output.Name)
+		}
+		ov, err := v.LookupValue("output")
+		if err != nil {
+			return errors.WithMessagef(err, "restore output %s", output.Name)
+		}
+		if err := ctx.SetVar(ov, output.Name); err != nil {
+			return errors.WithMessagef(err, "restore output %s", output.Name)
+		}
+	}
+	return nil
+}
+
+// saveStepCache saves the hash and the outputs of the succeeded step to the workflow context
+func saveStepCache(ctx wfContext.Context, step v1beta1.WorkflowStep, hash string) error {
+	cache := &stepCache{Hash: hash, Outputs: map[string]string{}}
+	for _, output := range step.Outputs {
+		v, err := ctx.GetVar(output.Name)
+		if err != nil {
+			return errors.WithMessagef(err, "get output %s", output.Name)
+		}
+		s, err := v.String()
+		if err != nil {
+			return errors.WithMessagef(err, "encode output %s", output.Name)
+		}
+		cache.Outputs[output.Name] = s
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	ctx.SetMutableValue(string(data), wfTypes.ContextPrefixStepCache, step.Name)
+	return nil
+}
@@ -87,6 +87,7 @@ func (t *TaskLoader) makeTaskGenerator(templ string) (wfTypes.TaskGenerator, err
 
 		exec := &executor{
 			handlers: t.handlers,
+			retry:    wfStep.Retry,
 			wfStatus: common.StepStatus{
 				Name:  wfStep.Name,
 				Type:  wfStep.Type,
@@ -143,8 +144,13 @@ func (t *TaskLoader) makeTaskGenerator(templ string) (wfTypes.TaskGenerator, err
 			var taskv *value.Value
 			var err error
 			var paramFile string
+			var cacheHash string
+			var cached bool
 
 			defer func() {
+				if cached {
+					return
+				}
 				if taskv == nil {
 					taskv, err = convertTemplate(ctx, t.pd, strings.Join([]string{templ, paramFile}, "\n"), exec.wfStatus.ID, options.PCtx)
 					if err != nil {
@@ -159,6 +165,11 @@ func (t *TaskLoader) makeTaskGenerator(templ string) (wfTypes.TaskGenerator, err
 						return
 					}
 				}
+				if cacheHash != "" && exec.wfStatus.Phase == common.WorkflowStepPhaseSucceeded && exec.wfStatus.Reason == "" {
+					if err := saveStepCache(ctx, wfStep, cacheHash); err != nil {
+						tracer.Error(err, "save step cache")
+					}
+				}
 			}()
 
 			for _, hook := range options.PreCheckHooks {
@@ -207,6 +218,21 @@ func (t *TaskLoader) makeTaskGenerator(templ string) (wfTypes.TaskGenerator, err
 				paramFile = fmt.Sprintf(model.ParameterFieldName+": {%s}\n", ps)
 			}
 
+			if wfStep.Cache {
+				cacheHash = computeStepCacheHash(ctx, options.PCtx, templ, paramFile)
+				if cache := loadStepCache(ctx, wfStep); cache != nil && cache.Hash == cacheHash {
+					if err := restoreStepCache(ctx, wfStep, cache); err != nil {
+						tracer.Error(err, "restore step cache")
+					} else {
+						cached = true
+						exec.wfStatus.Reason = wfTypes.StatusReasonCached
+						exec.wfStatus.Message = "the properties and inputs are unchanged since the last succeeded execution"
+						hooks.SetAdditionalNameInStatus(options.StepStatus, wfStep.Name, wfStep.Properties, exec.status())
+						return exec.status(), exec.operation(), nil
+					}
+				}
+			}
+
 			taskv, err = convertTemplate(ctx, t.pd, strings.Join([]string{templ, paramFile}, "\n"), exec.wfStatus.ID, options.PCtx)
 			if err != nil {
 				exec.err(ctx, false, err, wfTypes.StatusReasonRendering)
@@ -343,6 +369,7 @@ func getInputsTemplate(ctx wfContext.Context, step v1beta1.WorkflowStep) string
 
 type executor struct {
 	handlers providers.Providers
+	retry    *common.WorkflowStepRetry
 
 	wfStatus           common.StepStatus
 	suspend            bool
@@ -414,7 +441,7 @@ func (exec *executor) err(ctx wfContext.Context, wait bool, err error, reason st
 
 func (exec *executor) checkErrorTimes(ctx wfContext.Context) {
 	times := ctx.IncreaseCountValueInMemory(wfTypes.ContextPrefixFailedTimes, exec.wfStatus.ID)
-	if times >= wfTypes.MaxWorkflowStepErrorRetryTimes {
+	if times >= wfTypes.GetMaxRetryTimes(exec.retry) || !wfTypes.IsRetryableReason(exec.retry, exec.wfStatus.Reason) {
 		exec.wait = false
 		exec.failedAfterRetries = true
 		exec.wfStatus.Reason = wfTypes.StatusReasonFailedAfterRetries
@@ -27,6 +27,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/yaml"
 
 	"github.com/stretchr/testify/require"
@@ -36,6 +37,7 @@ import (
 	"github.com/oam-dev/kubevela/pkg/cue/model/value"
 	"github.com/oam-dev/kubevela/pkg/cue/process"
 	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
+	"github.com/oam-dev/kubevela/pkg/workflow/hooks"
 	"github.com/oam-dev/kubevela/pkg/workflow/providers"
 	"github.com/oam-dev/kubevela/pkg/workflow/types"
 )
@@ -232,6 +234,21 @@ close({
 			Name: "failed-after-retries",
 			Type: "error",
 		},
+		{
+			Name: "failed-after-step-retries",
+			Type: "error",
+			Retry: &common.WorkflowStepRetry{
+				Attempts: 2,
+			},
+		},
+		{
+			Name: "failed-with-unretryable-reason",
+			Type: "error",
+			Retry: &common.WorkflowStepRetry{
+				Attempts:         20,
+				RetryableReasons: []string{types.StatusReasonRendering},
+			},
+		},
 	}
 	for _, step := range steps {
 		gen, err := tasksLoader.GetTaskGenerator(context.Background(), step.Type)
@@ -265,6 +282,31 @@ close({
 			r.Equal(operation.FailedAfterRetries, true)
 			r.Equal(status.Phase, common.WorkflowStepPhaseFailed)
 			r.Equal(status.Reason, types.StatusReasonFailedAfterRetries)
+		case "failed-after-step-retries":
+			wfContext.CleanupMemoryStore("app-v1", "default")
+			newCtx := newWorkflowContextForTest(t)
+			run, err = gen(step, &types.GeneratorOptions{})
+			r.NoError(err)
+			for i := 0; i < 2; i++ {
+				status, operation, err = run.Run(newCtx, &types.TaskRunOptions{})
+				r.NoError(err)
+				r.Equal(operation.Waiting, true)
+				r.Equal(operation.FailedAfterRetries, false)
+			}
+			status, operation, err = run.Run(newCtx, &types.TaskRunOptions{})
+			r.NoError(err)
+			r.Equal(operation.FailedAfterRetries, true)
+			r.Equal(status.Reason, types.StatusReasonFailedAfterRetries)
+		case "failed-with-unretryable-reason":
+			wfContext.CleanupMemoryStore("app-v1", "default")
+			run, err = gen(step, &types.GeneratorOptions{})
+			r.NoError(err)
+			status, operation, err = run.Run(newWorkflowContextForTest(t), &types.TaskRunOptions{})
+			r.NoError(err)
+			r.Equal(operation.Waiting, false)
+			r.Equal(operation.FailedAfterRetries, true)
+			r.Equal(status.Phase, common.WorkflowStepPhaseFailed)
+			r.Equal(status.Reason, types.StatusReasonFailedAfterRetries)
 		default:
 			r.Equal(operation.Waiting, true)
 			r.Equal(status.Phase, common.WorkflowStepPhaseFailed)
@@ -746,3 +788,97 @@ metadata:
   name: app-v1
 `
 )
+
+func TestStepCache(t *testing.T) {
+	r := require.New(t)
+	cli := fake.NewClientBuilder().Build()
+	executed := 0
+	discover := providers.NewProviders()
+	discover.Register("test", map[string]providers.Handler{
+		"output": func(ctx wfContext.Context, v *value.Value, act types.Action) error {
+			executed++
+			ip, _ := v.MakeValue(`
+myIP: value: "1.1.1.1"
+`)
+			return v.FillObject(ip)
+		},
+	})
+	newLoader := func(revision string) *TaskLoader {
+		pCtx := process.NewContext(process.ContextData{
+			AppName:         "app",
+			CompName:        "app",
+			Namespace:       "default",
+			AppRevisionName: revision,
+		})
+		return NewTaskLoader(mockLoadTemplate, nil, discover, 0, pCtx)
+	}
+	tasksLoader := newLoader("app-v1")
+	generation := 1
+	step := v1beta1.WorkflowStep{
+		Name:       "output",
+		Type:       "output",
+		Cache:      true,
+		Properties: &runtime.RawExtension{Raw: []byte(`{"version":"v1"}`)},
+		Outputs: common.StepOutputs{{
+			ValueFrom: "myIP.value",
+			Name:      "podIP",
+		}},
+	}
+	run := func(step v1beta1.WorkflowStep) (wfContext.Context, common.StepStatus) {
+		// a new context is created every time to simulate the restart of the workflow
+		wfCtx, err := wfContext.NewContext(cli, "default", "app", "uid")
+		r.NoError(err)
+		v, _ := value.NewValue(fmt.Sprintf(`name: "app", generation: %d`, generation), nil, "")
+		r.NoError(wfCtx.SetVar(v, types.ContextKeyMetadata))
+		gen, err := tasksLoader.GetTaskGenerator(context.Background(), step.Type)
+		r.NoError(err)
+		runner, err := gen(step, &types.GeneratorOptions{})
+		r.NoError(err)
+		status, _, err := runner.Run(wfCtx, &types.TaskRunOptions{
+			PostStopHooks: []types.TaskPostStopHook{hooks.Output},
+		})
+		r.NoError(err)
+		r.NoError(wfCtx.Commit())
+		return wfCtx, status
+	}
+
+	_, status := run(step)
+	r.Equal(common.WorkflowStepPhaseSucceeded, status.Phase)
+	r.Equal("", status.Reason)
+	r.Equal(1, executed)
+
+	wfCtx, status := run(step)
+	r.Equal(common.WorkflowStepPhaseSucceeded, status.Phase)
+	r.Equal(types.StatusReasonCached, status.Reason)
+	r.Equal(1, executed)
+	ip, err := wfCtx.GetVar("podIP")
+	r.NoError(err)
+	s, err := ip.CueValue().String()
+	r.NoError(err)
+	r.Equal("1.1.1.1", s)
+
+	step.Properties = &runtime.RawExtension{Raw: []byte(`{"version":"v2"}`)}
+	_, status = run(step)
+	r.Equal("", status.Reason)
+	r.Equal(2, executed)
+
+	_, status = run(step)
+	r.Equal(types.StatusReasonCached, status.Reason)
+	r.Equal(2, executed)
+
+	// the step is executed again once the application or the definitions are changed
+	generation = 2
+	_, status = run(step)
+	r.Equal("", status.Reason)
+	r.Equal(3, executed)
+
+	tasksLoader = newLoader("app-v2")
+	_, status = run(step)
+	r.Equal("", status.Reason)
+	r.Equal(4, executed)
+
+	step.Cache = false
+	_, status = run(step)
+	r.Equal("", status.Reason)
+	r.Equal(5, executed)
+}
@@ -23,23 +23,28 @@ import (
 	"strings"
 	"time"
 
+	"github.com/imdario/mergo"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/cue/model/value"
 	"github.com/oam-dev/kubevela/pkg/cue/packages"
 	"github.com/oam-dev/kubevela/pkg/cue/process"
 	monitorContext "github.com/oam-dev/kubevela/pkg/monitor/context"
 	"github.com/oam-dev/kubevela/pkg/oam/discoverymapper"
+	oamutil "github.com/oam-dev/kubevela/pkg/oam/util"
 	"github.com/oam-dev/kubevela/pkg/velaql/providers/query"
 	wfContext "github.com/oam-dev/kubevela/pkg/workflow/context"
 	"github.com/oam-dev/kubevela/pkg/workflow/providers"
 	"github.com/oam-dev/kubevela/pkg/workflow/providers/email"
 	"github.com/oam-dev/kubevela/pkg/workflow/providers/http"
 	"github.com/oam-dev/kubevela/pkg/workflow/providers/kube"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers/metrics"
 	timeprovider "github.com/oam-dev/kubevela/pkg/workflow/providers/time"
 	"github.com/oam-dev/kubevela/pkg/workflow/providers/util"
 	"github.com/oam-dev/kubevela/pkg/workflow/providers/workspace"
@@ -97,16 +102,34 @@ func StepGroup(step v1beta1.WorkflowStep, opt *types.GeneratorOptions) (types.Ta
 	}, nil
 }
 
+// Foreach is the foreach step runner, it expands the template sub step by the items at runtime
+func Foreach(step v1beta1.WorkflowStep, opt *types.GeneratorOptions) (types.TaskRunner, error) {
+	if len(step.SubSteps) != 1 {
+		return nil, errors.Errorf("foreach step %s must have exactly one sub step as the template", step.Name)
+	}
+	return &foreachTaskRunner{
+		id:           opt.ID,
+		name:         step.Name,
+		step:         step,
+		subGenerator: opt.SubTaskGenerator,
+		pd:           opt.PackageDiscover,
+		pCtx:         opt.ProcessContext,
+	}, nil
+}
+
 func newTaskDiscover(ctx monitorContext.Context, providerHandlers providers.Providers, pd *packages.PackageDiscover, pCtx process.Context, templateLoader template.Loader) types.TaskDiscover {
 	// install builtin provider
 	workspace.Install(providerHandlers)
 	email.Install(providerHandlers)
 	util.Install(ctx, providerHandlers)
+	metrics.Install(providerHandlers)
 
 	return &taskDiscover{
 		builtins: map[string]types.TaskGenerator{
 			types.WorkflowStepTypeSuspend:   suspend,
 			types.WorkflowStepTypeStepGroup: StepGroup,
+			types.WorkflowStepTypeForeach:   Foreach,
+			types.WorkflowStepTypeApproval:  Approval,
 		},
 		remoteTaskDiscover: custom.NewTaskLoader(templateLoader.LoadTaskTemplate, pd, providerHandlers, 0, pCtx),
 		templateLoader:     templateLoader,
@@ -145,10 +168,59 @@ func (tr *suspendTaskRunner) Run(ctx wfContext.Context, options *types.TaskRunOp
 	status := &stepStatus
 	defer handleOutput(ctx, status, operations, tr.step, options.PostStopHooks, tr.pd, tr.id, tr.pCtx)
 
+	if handleSuspendPreCheck(tr.step, status, operations, options, tr.pd, tr.pCtx) {
+		return stepStatus, operations, nil
+	}
+	for _, input := range tr.step.Inputs {
+		if input.ParameterKey == "duration" {
+			inputValue, err := ctx.GetVar(strings.Split(input.From, ".")...)
+			if err != nil {
+				return common.StepStatus{}, nil, errors.WithMessagef(err, "do preStartHook: get input from [%s]", input.From)
+			}
+			d, err := inputValue.String()
+			if err != nil {
+				return common.StepStatus{}, nil, errors.WithMessagef(err, "do preStartHook: input value from [%s] is not a valid string", input.From)
+			}
+			props := map[string]json.RawMessage{}
+			if tr.step.Properties.Size() > 0 {
+				if err := json.Unmarshal(tr.step.Properties.Raw, &props); err != nil {
+					return common.StepStatus{}, nil, errors.WithMessage(err, "do preStartHook: decode properties")
+				}
+			}
+			props["duration"] = json.RawMessage(d)
+			tr.step.Properties = &runtime.RawExtension{Raw: oamutil.MustJSONMarshal(props)}
+		}
+	}
+	firstExecuteTime := time.Now()
+	if options.Engine != nil {
+		if ss := options.Engine.GetCommonStepStatus(tr.step.Name); !ss.FirstExecuteTime.IsZero() {
+			firstExecuteTime = ss.FirstExecuteTime.Time
+		}
+	}
+	resumeTime, err := GetSuspendStepResumeTime(tr.step, firstExecuteTime)
+	if err != nil {
+		stepStatus.Message = fmt.Sprintf("invalid suspend duration: %s", err.Error())
+		return stepStatus, operations, nil
+	}
+	if !resumeTime.IsZero() {
+		if !time.Now().Before(resumeTime) {
+			stepStatus.Phase = common.WorkflowStepPhaseSucceeded
+			operations.Suspend = false
+		} else {
+			next := metav1.NewTime(resumeTime)
+			stepStatus.NextExecuteTime = &next
+		}
+	}
+	return stepStatus, operations, nil
+}
+
+// handleSuspendPreCheck runs the pre check hooks of the step that suspends the workflow, it returns true
+// if the step is skipped or timeout.
+func handleSuspendPreCheck(step v1beta1.WorkflowStep, stepStatus *common.StepStatus, operations *types.Operation, options *types.TaskRunOptions, pd *packages.PackageDiscover, pCtx process.Context) bool {
 	for _, hook := range options.PreCheckHooks {
-		result, err := hook(tr.step, &types.PreCheckOptions{
-			PackageDiscover: tr.pd,
-			ProcessContext:  tr.pCtx,
+		result, err := hook(step, &types.PreCheckOptions{
+			PackageDiscover: pd,
+			ProcessContext:  pCtx,
 		})
 		if err != nil {
 			stepStatus.Phase = common.WorkflowStepPhaseSkipped
@@ -172,38 +244,9 @@ func (tr *suspendTaskRunner) Run(ctx wfContext.Context, options *types.TaskRunOp
 		default:
 			continue
 		}
-		return stepStatus, operations, nil
-	}
-	for _, input := range tr.step.Inputs {
-		if input.ParameterKey == "duration" {
-			inputValue, err := ctx.GetVar(strings.Split(input.From, ".")...)
-			if err != nil {
-				return common.StepStatus{}, nil, errors.WithMessagef(err, "do preStartHook: get input from [%s]", input.From)
-			}
-			d, err := inputValue.String()
-			if err != nil {
-				return common.StepStatus{}, nil, errors.WithMessagef(err, "do preStartHook: input value from [%s] is not a valid string", input.From)
-			}
-			tr.step.Properties = &runtime.RawExtension{Raw: []byte(`{"duration":` + d + `}`)}
-		}
-	}
-	d, err := GetSuspendStepDurationWaiting(tr.step)
-	if err != nil {
-		stepStatus.Message = fmt.Sprintf("invalid suspend duration: %s", err.Error())
-		return stepStatus, operations, nil
+		return true
 	}
-	if d != 0 {
-		e := options.Engine
-		firstExecuteTime := time.Now()
-		if ss := e.GetCommonStepStatus(tr.step.Name); !ss.FirstExecuteTime.IsZero() {
-			firstExecuteTime = ss.FirstExecuteTime.Time
-		}
-		if time.Now().After(firstExecuteTime.Add(d)) {
-			stepStatus.Phase = common.WorkflowStepPhaseSucceeded
-			operations.Suspend = false
-		}
-	}
-	return stepStatus, operations, nil
+	return false
 }
 
 // Pending check task should be executed or not.
@@ -331,6 +374,246 @@ func getStepGroupStatus(status common.StepStatus, stepStatus common.WorkflowStep
 	return status, operation
 }
 
+// ForeachProperties is the properties of the foreach step
+type ForeachProperties struct {
+	// Items is the list to iterate, each item generates one sub step from the template
+	Items []interface{} `json:"items,omitempty"`
+	// ItemKey is the key in the template properties to put the item, if it is empty,
+	// the item must be an object and will be merged into the template properties
+	ItemKey string `json:"itemKey,omitempty"`
+	// Parallelism is the max number of the items that are executing at the same time, 0 means no limit
+	Parallelism int `json:"parallelism,omitempty"`
+}
+
+type foreachTaskRunner struct {
+	id           string
+	name         string
+	step         v1beta1.WorkflowStep
+	subGenerator func(subStep v1beta1.WorkflowStep) (types.TaskRunner, error)
+	pd           *packages.PackageDiscover
+	pCtx         process.Context
+}
+
+// Name return foreach step name.
+func (tr *foreachTaskRunner) Name() string {
+	return tr.name
+}
+
+// Pending check task should be executed or not.
+func (tr *foreachTaskRunner) Pending(ctx wfContext.Context, stepStatus map[string]common.StepStatus) (bool, common.StepStatus) {
+	return custom.CheckPending(ctx, tr.step, tr.id, stepStatus)
+}
+
+// Run expands the items into sub steps and runs them.
+func (tr *foreachTaskRunner) Run(ctx wfContext.Context, options *types.TaskRunOptions) (status common.StepStatus, operations *types.Operation, rErr error) {
+	status = common.StepStatus{
+		ID:   tr.id,
+		Name: tr.name,
+		Type: types.WorkflowStepTypeForeach,
+	}
+	operations = &types.Operation{}
+
+	pStatus := &status
+	defer handleOutput(ctx, pStatus, operations, tr.step, options.PostStopHooks, tr.pd, tr.id, tr.pCtx)
+	for _, hook := range options.PreCheckHooks {
+		result, err := hook(tr.step, &types.PreCheckOptions{
+			PackageDiscover: tr.pd,
+			ProcessContext:  options.PCtx,
+		})
+		if err != nil {
+			status.Phase = common.WorkflowStepPhaseSkipped
+			status.Reason = types.StatusReasonSkip
+			status.Message = fmt.Sprintf("pre check error: %s", err.Error())
+			continue
+		}
+		if result.Skip {
+			status.Phase = common.WorkflowStepPhaseSkipped
+			status.Reason = types.StatusReasonSkip
+			options.StepStatus[tr.step.Name] = status
+			break
+		}
+		if result.Timeout {
+			status.Phase = common.WorkflowStepPhaseFailed
+			status.Reason = types.StatusReasonTimeout
+			options.StepStatus[tr.step.Name] = status
+		}
+	}
+
+	e := options.Engine
+	props, err := tr.getProperties(ctx)
+	if err != nil {
+		status.Phase = common.WorkflowStepPhaseFailed
+		status.Reason = types.StatusReasonParameter
+		status.Message = err.Error()
+		operations.Terminated = true
+		return status, operations, nil
+	}
+	subTaskRunners, err := tr.generateSubTaskRunners(props, e)
+	if err != nil {
+		status.Phase = common.WorkflowStepPhaseFailed
+		status.Reason = types.StatusReasonParameter
+		status.Message = err.Error()
+		operations.Terminated = true
+		return status, operations, nil
+	}
+	if status.Phase != common.WorkflowStepPhaseSkipped && status.Phase != common.WorkflowStepPhaseFailed && len(subTaskRunners) > 0 {
+		e.SetParentRunner(tr.name)
+		if err := e.Run(limitParallelism(subTaskRunners, options.StepStatus, props.Parallelism), true); err != nil {
+			return common.StepStatus{
+				ID:    tr.id,
+				Name:  tr.name,
+				Type:  types.WorkflowStepTypeForeach,
+				Phase: common.WorkflowStepPhaseRunning,
+			}, e.GetOperation(), err
+		}
+		e.SetParentRunner("")
+	}
+
+	status, operations = getStepGroupStatus(status, e.GetStepStatus(tr.name), e.GetOperation(), len(subTaskRunners))
+	if status.Phase == common.WorkflowStepPhaseSucceeded {
+		if err := tr.collectOutputs(ctx, len(subTaskRunners)); err != nil {
+			status.Phase = common.WorkflowStepPhaseFailed
+			status.Reason = types.StatusReasonOutput
+			status.Message = fmt.Sprintf("collect outputs error: %s", err.Error())
+			operations.Terminated = true
+		}
+	}
+	return status, operations, nil
+}
+
+func (tr *foreachTaskRunner) getProperties(ctx wfContext.Context) (*ForeachProperties, error) {
+	props := &ForeachProperties{}
+	if tr.step.Properties.Size() > 0 {
+		if err := json.Unmarshal(tr.step.Properties.Raw, props); err != nil {
+			return nil, errors.WithMessage(err, "invalid foreach properties")
+		}
+	}
+	for _, input := range tr.step.Inputs {
+		if input.ParameterKey != "items" {
+			continue
+		}
+		inputValue, err := ctx.GetVar(strings.Split(input.From, ".")...)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "get input from [%s]", input.From)
+		}
+		var items []interface{}
+		if err := inputValue.UnmarshalTo(&items); err != nil {
+			return nil, errors.WithMessagef(err, "input value from [%s] is not a valid list", input.From)
+		}
+		props.Items = items
+	}
+	if props.Parallelism < 0 {
+		return nil, errors.Errorf("invalid foreach parallelism %d", props.Parallelism)
+	}
+	return props, nil
+}
+
+func (tr *foreachTaskRunner) generateSubTaskRunners(props *ForeachProperties, e types.Engine) ([]types.TaskRunner, error) {
+	if tr.subGenerator == nil {
+		return nil, errors.New("no sub task generator for foreach step")
+	}
+	var runners []types.TaskRunner
+	for i, item := range props.Items {
+		subStep, err := generateForeachSubStep(tr.name, tr.step.SubSteps[0], i, item, props.ItemKey)
+		if err != nil {
+			return nil, err
+		}
+		runner, err := tr.subGenerator(subStep)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "generate sub step %s", subStep.Name)
+		}
+		e.SetStepRetry(subStep.Name, subStep.Retry)
+		runners = append(runners, runner)
+	}
+	return runners, nil
+}
+
+// limitParallelism returns the sub task runners to be executed in this round, the finished runners
+// are always kept while the unfinished ones are limited by the parallelism.
+func limitParallelism(runners []types.TaskRunner, stepStatus map[string]common.StepStatus, parallelism int) []types.TaskRunner {
+	if parallelism == 0 {
+		return runners
+	}
+	var selected []types.TaskRunner
+	executing := 0
+	for _, runner := range runners {
+		if ss, ok := stepStatus[runner.Name()]; ok && types.IsStepFinish(ss.Phase, ss.Reason) {
+			selected = append(selected, runner)
+			continue
+		}
+		if executing < parallelism {
+			selected = append(selected, runner)
+			executing++
+		}
+	}
+	return selected
+}
+
+func (tr *foreachTaskRunner) collectOutputs(ctx wfContext.Context, count int) error {
+	for _, output := range tr.step.SubSteps[0].Outputs {
+		var items []string
+		for i := 0; i < count; i++ {
+			v, err := ctx.GetVar(types.GetForeachGeneratedName(tr.name, output.Name, i))
+			if err != nil {
+				return errors.WithMessagef(err, "get output %s of item %d", output.Name, i)
+			}
+			s, err := v.String()
+			if err != nil {
+				return err
+			}
+			items = append(items, s)
+		}
+		v, err := value.NewValue(fmt.Sprintf("outputs: [%s]", strings.Join(items, ",")), nil, "")
+		if err != nil {
+			return err
+		}
+		list, err := v.LookupValue("outputs")
+		if err != nil {
+			return err
+		}
+		if err := ctx.SetVar(list, output.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func generateForeachSubStep(foreachName string, template common.WorkflowSubStep, index int, item interface{}, itemKey string) (v1beta1.WorkflowStep, error) {
+	props := map[string]interface{}{}
+	if template.Properties.Size() > 0 {
+		if err := json.Unmarshal(template.Properties.Raw, &props); err != nil {
+			return v1beta1.WorkflowStep{}, err
+		}
+	}
+	if itemKey != "" {
+		props[itemKey] = item
+	} else {
+		patch, ok := item.(map[string]interface{})
+		if !ok {
+			return v1beta1.WorkflowStep{}, errors.Errorf("item %d must be an object if itemKey is not specified", index)
+		}
+		if err := mergo.Merge(&props, patch, mergo.WithOverride); err != nil {
+			return v1beta1.WorkflowStep{}, errors.Wrapf(err, "failed to merge item %d into template properties", index)
+		}
+	}
+	subStep := v1beta1.WorkflowStep{
+		Name:       types.GetForeachGeneratedName(foreachName, template.Name, index),
+		Type:       template.Type,
+		Meta:       template.Meta,
+		Properties: oamutil.Object2RawExtension(props),
+		If:         template.If,
+		Timeout:    template.Timeout,
+		Retry:      template.Retry,
+		Cache:      template.Cache,
+		Inputs:     template.Inputs,
+	}
+	for _, output := range template.Outputs {
+		output.Name = types.GetForeachGeneratedName(foreachName, output.Name, index)
+		subStep.Outputs = append(subStep.Outputs, output)
+	}
+	return subStep, nil
+}
+
 // NewViewTaskDiscover will create a client for load task generator.
 func NewViewTaskDiscover(pd *packages.PackageDiscover, cli client.Client, cfg *rest.Config, apply kube.Dispatcher, delete kube.Deleter, viewNs string, logLevel int, pCtx process.Context, loader template.Loader) types.TaskDiscover {
 	handlerProviders := providers.NewProviders()
@@ -348,30 +631,6 @@ func NewViewTaskDiscover(pd *packages.PackageDiscover, cli client.Client, cfg *r
 	}
 }
 
-// GetSuspendStepDurationWaiting get suspend step wait duration
-func GetSuspendStepDurationWaiting(step v1beta1.WorkflowStep) (time.Duration, error) {
-	if step.Properties.Size() > 0 {
-		o := struct {
-			Duration string `json:"duration"`
-		}{}
-		js, err := common.RawExtensionPointer{RawExtension: step.Properties}.MarshalJSON()
-		if err != nil {
-			return 0, err
-		}
-
-		if err := json.Unmarshal(js, &o); err != nil {
-			return 0, err
-		}
-
-		if o.Duration != "" {
-			waitDuration, err := time.ParseDuration(o.Duration)
-			return waitDuration, err
-		}
-	}
-
-	return 0, nil
-}
-
 func handleOutput(ctx wfContext.Context, stepStatus *common.StepStatus, operations *types.Operation, step v1beta1.WorkflowStep, postStopHooks []types.TaskPostStopHook, pd *packages.PackageDiscover, id string, pCtx process.Context) {
 	status := *stepStatus
 	if len(step.Outputs) > 0 {
@@ -22,6 +22,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
@@ -141,6 +142,7 @@ func TestSuspendStep(t *testing.T) {
 type testEngine struct {
 	stepStatus common.WorkflowStepStatus
 	operation  *types.Operation
+	stepRetry  map[string]*common.WorkflowStepRetry
 }
 
 func (e *testEngine) Run(taskRunners []types.TaskRunner, dag bool) error {
@@ -152,12 +154,19 @@ func (e *testEngine) GetStepStatus(stepName string) common.WorkflowStepStatus {
 }
 
 func (e *testEngine) GetCommonStepStatus(stepName string) common.StepStatus {
-	return common.StepStatus{}
+	return e.stepStatus.StepStatus
 }
 
 func (e *testEngine) SetParentRunner(name string) {
 }
 
+func (e *testEngine) SetStepRetry(stepName string, retry *common.WorkflowStepRetry) {
+	if e.stepRetry == nil {
+		e.stepRetry = map[string]*common.WorkflowStepRetry{}
+	}
+	e.stepRetry[stepName] = retry
+}
+
 func (e *testEngine) GetOperation() *types.Operation {
 	return e.operation
 }
@@ -352,3 +361,102 @@ func TestStepGroupStep(t *testing.T) {
 		})
 	}
 }
+
+func TestForeachStep(t *testing.T) {
+	r := require.New(t)
+	_, err := Foreach(v1beta1.WorkflowStep{Name: "test"}, &types.GeneratorOptions{ID: "124"})
+	r.Error(err)
+
+	var generated []v1beta1.WorkflowStep
+	runner, err := Foreach(v1beta1.WorkflowStep{
+		Name:       "test",
+		Properties: &runtime.RawExtension{Raw: []byte(`{"items":[{"cluster":"hangzhou"},{"cluster":"beijing"}],"parallelism":1}`)},
+		SubSteps: []common.WorkflowSubStep{{
+			Name:       "deploy",
+			Type:       "deploy",
+			Properties: &runtime.RawExtension{Raw: []byte(`{"policies":["topology"],"cluster":"local"}`)},
+			Retry:      &common.WorkflowStepRetry{Attempts: 3},
+		}},
+	}, &types.GeneratorOptions{
+		ID: "124",
+		SubTaskGenerator: func(subStep v1beta1.WorkflowStep) (types.TaskRunner, error) {
+			generated = append(generated, subStep)
+			return StepGroup(subStep, &types.GeneratorOptions{ID: subStep.Name})
+		},
+	})
+	r.NoError(err)
+	r.Equal(runner.Name(), "test")
+
+	engine := &testEngine{
+		stepStatus: common.WorkflowStepStatus{},
+		operation:  &types.Operation{},
+	}
+	status, _, err := runner.Run(nil, &types.TaskRunOptions{
+		StepStatus: map[string]common.StepStatus{},
+		Engine:     engine,
+	})
+	r.NoError(err)
+	r.Equal(status.Type, types.WorkflowStepTypeForeach)
+	r.Equal(status.Phase, common.WorkflowStepPhaseRunning)
+	r.Equal(len(generated), 2)
+	r.Equal(generated[0].Name, "test-deploy-0")
+	r.Equal(string(generated[0].Properties.Raw), `{"cluster":"hangzhou","policies":["topology"]}`)
+	r.Equal(generated[1].Name, "test-deploy-1")
+	r.Equal(3, engine.stepRetry["test-deploy-1"].Attempts)
+	r.Equal(string(generated[1].Properties.Raw), `{"cluster":"beijing","policies":["topology"]}`)
+
+	status, _, err = runner.Run(nil, &types.TaskRunOptions{
+		StepStatus: map[string]common.StepStatus{},
+		Engine: &testEngine{
+			stepStatus: common.WorkflowStepStatus{
+				SubStepsStatus: []common.WorkflowSubStepStatus{
+					{StepStatus: common.StepStatus{Name: "test-deploy-0", Phase: common.WorkflowStepPhaseSucceeded}},
+					{StepStatus: common.StepStatus{Name: "test-deploy-1", Phase: common.WorkflowStepPhaseSucceeded}},
+				},
+			},
+			operation: &types.Operation{},
+		},
+	})
+	r.NoError(err)
+	r.Equal(status.Phase, common.WorkflowStepPhaseSucceeded)
+
+	runner, err = Foreach(v1beta1.WorkflowStep{
+		Name:       "invalid",
+		Properties: &runtime.RawExtension{Raw: []byte(`{"items":["hangzhou"]}`)},
+		SubSteps:   []common.WorkflowSubStep{{Name: "deploy", Type: "deploy"}},
+	}, &types.GeneratorOptions{
+		ID: "125",
+		SubTaskGenerator: func(subStep v1beta1.WorkflowStep) (types.TaskRunner, error) {
+			return StepGroup(subStep, &types.GeneratorOptions{ID: subStep.Name})
+		},
+	})
+	r.NoError(err)
+	status, operations, err := runner.Run(nil, &types.TaskRunOptions{
+		StepStatus: map[string]common.StepStatus{},
+		Engine:     &testEngine{operation: &types.Operation{}},
+	})
+	r.NoError(err)
+	r.Equal(status.Phase, common.WorkflowStepPhaseFailed)
+	r.Equal(status.Reason, types.StatusReasonParameter)
+	r.Equal(operations.Terminated, true)
+}
+
+func TestLimitParallelism(t *testing.T) {
+	r := require.New(t)
+	var runners []types.TaskRunner
+	for _, name := range []string{"s1", "s2", "s3", "s4"} {
+		runner, err := StepGroup(v1beta1.WorkflowStep{Name: name}, &types.GeneratorOptions{ID: name})
+		r.NoError(err)
+		runners = append(runners, runner)
+	}
+	stepStatus := map[string]common.StepStatus{
+		"s1": {Phase: common.WorkflowStepPhaseSucceeded},
+		"s2": {Phase: common.WorkflowStepPhaseRunning},
+	}
+	r.Equal(len(limitParallelism(runners, stepStatus, 0)), 4)
+	selected := limitParallelism(runners, stepStatus, 2)
+	r.Equal(len(selected), 3)
+	r.Equal(selected[0].Name(), "s1")
+	r.Equal(selected[1].Name(), "s2")
+	r.Equal(selected[2].Name(), "s3")
+}
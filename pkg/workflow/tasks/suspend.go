@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+)
+
+const windowTimeLayout = "15:04"
+
+// SuspendWindow is the business calendar window that the suspend step waits for, such as
+// weekdays 02:00-04:00 Europe/Berlin.
+type SuspendWindow struct {
+	// Days are the days of week when the window opens, such as Mon or Monday, weekdays and weekends are
+	// also supported. Empty means every day.
+	Days []string `json:"days,omitempty"`
+	// Start is the opening time of the window in the format of 15:04.
+	Start string `json:"start"`
+	// End is the closing time of the window in the format of 15:04, the window ends on the next day
+	// if the end is not after the start.
+	End string `json:"end"`
+	// TimeZone is the time zone of the window, the default is UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// SuspendProperties are the properties of the suspend step to resume the step automatically.
+type SuspendProperties struct {
+	// Duration is the duration to wait after the step starts, such as 10m.
+	Duration string `json:"duration,omitempty"`
+	// Until is the absolute time in RFC3339 format to resume the step.
+	Until string `json:"until,omitempty"`
+	// Window is the business calendar window to resume the step, the step is resumed at the next opening
+	// of the window after the duration and the until time.
+	Window *SuspendWindow `json:"window,omitempty"`
+}
+
+// GetSuspendStepResumeTime returns the time to resume the suspend step automatically, it returns the zero
+// time if the suspend step can only be resumed manually.
+func GetSuspendStepResumeTime(step v1beta1.WorkflowStep, firstExecuteTime time.Time) (time.Time, error) {
+	if step.Properties.Size() == 0 {
+		return time.Time{}, nil
+	}
+	props := SuspendProperties{}
+	js, err := common.RawExtensionPointer{RawExtension: step.Properties}.MarshalJSON()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := json.Unmarshal(js, &props); err != nil {
+		return time.Time{}, err
+	}
+
+	var resumeTime time.Time
+	if props.Duration != "" {
+		d, err := time.ParseDuration(props.Duration)
+		if err != nil {
+			return time.Time{}, err
+		}
+		resumeTime = firstExecuteTime.Add(d)
+	}
+	if props.Until != "" {
+		until, err := time.Parse(time.RFC3339, props.Until)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "invalid until time %s", props.Until)
+		}
+		if until.After(resumeTime) {
+			resumeTime = until
+		}
+	}
+	if props.Window != nil {
+		from := resumeTime
+		if from.IsZero() {
+			from = firstExecuteTime
+		}
+		return props.Window.NextOpening(from)
+	}
+	return resumeTime, nil
+}
+
+// NextOpening returns the earliest time that is in the window and not before the given time.
+func (w *SuspendWindow) NextOpening(from time.Time) (time.Time, error) {
+	loc := time.UTC
+	if w.TimeZone != "" {
+		var err error
+		if loc, err = time.LoadLocation(w.TimeZone); err != nil {
+			return time.Time{}, errors.Wrapf(err, "invalid time zone %s", w.TimeZone)
+		}
+	}
+	start, err := time.Parse(windowTimeLayout, w.Start)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid window start %s", w.Start)
+	}
+	end, err := time.Parse(windowTimeLayout, w.End)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid window end %s", w.End)
+	}
+	days, err := parseWindowDays(w.Days)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t := from.In(loc)
+	// start from the previous day in case the window of the previous day crosses midnight
+	for i := -1; i <= 7; i++ {
+		day := time.Date(t.Year(), t.Month(), t.Day()+i, 0, 0, 0, 0, loc)
+		if !days[day.Weekday()] {
+			continue
+		}
+		open := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, loc)
+		closed := time.Date(day.Year(), day.Month(), day.Day(), end.Hour(), end.Minute(), 0, 0, loc)
+		if !closed.After(open) {
+			closed = closed.AddDate(0, 0, 1)
+		}
+		if !t.Before(open) && t.Before(closed) {
+			return from, nil
+		}
+		if open.After(t) {
+			return open, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no opening of the window is found")
+}
+
+func parseWindowDays(days []string) (map[time.Weekday]bool, error) {
+	result := map[time.Weekday]bool{}
+	if len(days) == 0 {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			result[d] = true
+		}
+		return result, nil
+	}
+	for _, day := range days {
+		switch key := strings.ToLower(day); key {
+		case "weekdays":
+			for d := time.Monday; d <= time.Friday; d++ {
+				result[d] = true
+			}
+		case "weekends":
+			result[time.Saturday] = true
+			result[time.Sunday] = true
+		default:
+			found := false
+			for d := time.Sunday; d <= time.Saturday; d++ {
+				if name := strings.ToLower(d.String()); key == name || key == name[:3] {
+					result[d] = true
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("invalid window day %s", day)
+			}
+		}
+	}
+	return result, nil
+}
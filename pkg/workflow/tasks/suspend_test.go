@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+)
+
+func TestGetSuspendStepResumeTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// 2022-07-01 is a Friday
+	start := time.Date(2022, 7, 1, 10, 0, 0, 0, time.UTC)
+	testCases := map[string]struct {
+		properties string
+		expected   time.Time
+		hasErr     bool
+	}{
+		"no properties": {
+			expected: time.Time{},
+		},
+		"duration": {
+			properties: `{"duration":"30m"}`,
+			expected:   start.Add(30 * time.Minute),
+		},
+		"until": {
+			properties: `{"until":"2022-07-02T08:00:00Z"}`,
+			expected:   time.Date(2022, 7, 2, 8, 0, 0, 0, time.UTC),
+		},
+		"the later one of duration and until": {
+			properties: `{"duration":"48h","until":"2022-07-02T08:00:00Z"}`,
+			expected:   start.Add(48 * time.Hour),
+		},
+		"next opening of the window": {
+			properties: `{"window":{"days":["weekdays"],"start":"02:00","end":"04:00","timeZone":"Europe/Berlin"}}`,
+			expected:   time.Date(2022, 7, 4, 2, 0, 0, 0, berlin),
+		},
+		"inside the window": {
+			properties: `{"window":{"days":["Fri"],"start":"11:00","end":"13:00","timeZone":"Europe/Berlin"}}`,
+			expected:   start,
+		},
+		"inside the window crossing midnight": {
+			properties: `{"until":"2022-07-02T00:30:00Z","window":{"days":["friday"],"start":"22:00","end":"02:00"}}`,
+			expected:   time.Date(2022, 7, 2, 0, 30, 0, 0, time.UTC),
+		},
+		"window after until": {
+			properties: `{"until":"2022-07-02T08:00:00Z","window":{"start":"02:00","end":"04:00"}}`,
+			expected:   time.Date(2022, 7, 3, 2, 0, 0, 0, time.UTC),
+		},
+		"invalid until": {
+			properties: `{"until":"tomorrow"}`,
+			hasErr:     true,
+		},
+		"invalid window day": {
+			properties: `{"window":{"days":["someday"],"start":"02:00","end":"04:00"}}`,
+			hasErr:     true,
+		},
+		"invalid window time zone": {
+			properties: `{"window":{"start":"02:00","end":"04:00","timeZone":"invalid"}}`,
+			hasErr:     true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			step := v1beta1.WorkflowStep{Name: "suspend", Type: "suspend"}
+			if tc.properties != "" {
+				step.Properties = &runtime.RawExtension{Raw: []byte(tc.properties)}
+			}
+			resumeTime, err := GetSuspendStepResumeTime(step, start)
+			if tc.hasErr {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+			r.True(tc.expected.Equal(resumeTime), "expected %s, got %s", tc.expected, resumeTime)
+		})
+	}
+}
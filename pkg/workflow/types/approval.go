@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"encoding/json"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+)
+
+// ApproverWorkflowCallback is the approver recorded for the approvals sent by the signed callback links. The links
+// can be opened by anyone who receives them, so the approval steps accept it only if it's listed in the approvers.
+const ApproverWorkflowCallback = "vela:workflow-callback"
+
+// ApprovalRecorders are the comma separated users trusted to record the approvals on behalf of others, such as the
+// service account of the apiserver. The other users can only append their own approvals.
+var ApprovalRecorders = ""
+
+// ApprovalProperties are the properties of the approval step.
+type ApprovalProperties struct {
+	// Approvers are the users who can approve the step, the service accounts are represented by
+	// their user names, such as system:serviceaccount:default:deployer.
+	Approvers []string `json:"approvers,omitempty"`
+	// Groups are the groups whose members can approve the step.
+	Groups []string `json:"groups,omitempty"`
+	// Quorum is the number of distinct approvers required to approve the step, the default is 1.
+	Quorum int `json:"quorum,omitempty"`
+	// Expiration is the duration since the step starts or the absolute time in RFC3339 format, the step
+	// fails if it's not approved before the expiration.
+	Expiration string `json:"expiration,omitempty"`
+}
+
+// GetApprovalProperties parses the properties of the approval step.
+func GetApprovalProperties(step v1beta1.WorkflowStep) (*ApprovalProperties, error) {
+	props := &ApprovalProperties{}
+	if step.Properties.Size() == 0 {
+		return props, nil
+	}
+	js, err := common.RawExtensionPointer{RawExtension: step.Properties}.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(js, props); err != nil {
+		return nil, err
+	}
+	return props, nil
+}
+
+// GetQuorum returns the number of distinct approvers required to approve the step.
+func (p *ApprovalProperties) GetQuorum() int {
+	if p.Quorum <= 0 {
+		return 1
+	}
+	return p.Quorum
+}
+
+// IsEligible checks whether the approver with the groups can approve or reject the step. Anyone is
+// eligible if neither approvers nor groups are specified, except ApproverWorkflowCallback, which must be
+// listed in the approvers explicitly.
+func (p *ApprovalProperties) IsEligible(approver string, groups []string) bool {
+	for _, a := range p.Approvers {
+		if a == approver {
+			return true
+		}
+	}
+	if approver == ApproverWorkflowCallback {
+		return false
+	}
+	if len(p.Approvers) == 0 && len(p.Groups) == 0 {
+		return true
+	}
+	for _, g := range p.Groups {
+		for _, group := range groups {
+			if g == group {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CountApprovals returns the number of distinct eligible approvers who approved the step, and the first
+// eligible rejection if the step is rejected.
+func (p *ApprovalProperties) CountApprovals(approvals []common.WorkflowStepApproval) (int, *common.WorkflowStepApproval) {
+	approvers := map[string]bool{}
+	for i, approval := range approvals {
+		if !p.IsEligible(approval.Approver, approval.Groups) {
+			continue
+		}
+		switch approval.Action {
+		case common.WorkflowStepApprovalReject:
+			return len(approvers), &approvals[i]
+		case common.WorkflowStepApprovalApprove:
+			approvers[approval.Approver] = true
+		default:
+		}
+	}
+	return len(approvers), nil
+}
@@ -38,6 +38,13 @@ type TaskRunner interface {
 	Run(ctx wfContext.Context, options *TaskRunOptions) (common.StepStatus, *Operation, error)
 }
 
+// ExitHandlers are the task runners executed after the steps of workflow reach the terminal state.
+type ExitHandlers struct {
+	OnSuccess []TaskRunner
+	OnFailure []TaskRunner
+	Finally   []TaskRunner
+}
+
 // TaskDiscover is the interface to obtain the TaskGenerator。
 type TaskDiscover interface {
 	GetTaskGenerator(ctx context.Context, name string) (TaskGenerator, error)
@@ -49,6 +56,7 @@ type Engine interface {
 	GetStepStatus(stepName string) common.WorkflowStepStatus
 	GetCommonStepStatus(stepName string) common.StepStatus
 	SetParentRunner(name string)
+	SetStepRetry(stepName string, retry *common.WorkflowStepRetry)
 	GetOperation() *Operation
 }
 
@@ -108,6 +116,10 @@ type GeneratorOptions struct {
 	PackageDiscover *packages.PackageDiscover
 	ProcessContext  process.Context
 	ExecuteMode     common.WorkflowMode
+
+	// SubTaskGenerator generates sub task runners at runtime, it is used by the steps whose sub steps
+	// can only be decided during execution, such as foreach.
+	SubTaskGenerator func(subStep v1beta1.WorkflowStep) (TaskRunner, error)
 }
 
 // Action is that workflow provider can do.
@@ -127,6 +139,14 @@ const (
 	ContextPrefixBackoffTimes = "backoff_times"
 	// ContextPrefixBackoffReason is the prefix that refer to the current backoff reason in workflow context config map
 	ContextPrefixBackoffReason = "backoff_reason"
+	// ContextPrefixStepCache is the prefix that refer to the input hash and outputs of the cached step in workflow context config map.
+	ContextPrefixStepCache = "step_cache"
+	// ContextPrefixWaitFor is the prefix that refer to the start time of the kube wait-for operation in workflow context config map.
+	ContextPrefixWaitFor = "wait_for"
+	// ContextPrefixJob is the prefix that refer to the spec hash and outputs of the finished job in workflow context config map.
+	ContextPrefixJob = "job"
+	// ContextPrefixMetricsAnalysis is the prefix that refer to the start time of the metrics analysis in workflow context config map.
+	ContextPrefixMetricsAnalysis = "metrics_analysis"
 	// ContextKeyLastExecuteTime is the key that refer to the last execute time in workflow context config map.
 	ContextKeyLastExecuteTime = "last_execute_time"
 	// ContextKeyNextExecuteTime is the key that refer to the next execute time in workflow context config map.
@@ -142,6 +162,12 @@ const (
 	WorkflowStepTypeBuiltinApplyComponent = "builtin-apply-component"
 	// WorkflowStepTypeStepGroup type step-group
 	WorkflowStepTypeStepGroup = "step-group"
+	// WorkflowStepTypeForeach type foreach
+	WorkflowStepTypeForeach = "foreach"
+	// WorkflowStepTypeApproval type approval
+	WorkflowStepTypeApproval = "approval"
+	// WorkflowStepTypeSubWorkflow type sub-workflow
+	WorkflowStepTypeSubWorkflow = "sub-workflow"
 )
 
 var (
@@ -176,6 +202,12 @@ const (
 	StatusReasonTimeout = "Timeout"
 	// StatusReasonAction is the reason of the workflow progress condition which is Action.
 	StatusReasonAction = "Action"
+	// StatusReasonRejected is the reason of the workflow progress condition which is Rejected.
+	StatusReasonRejected = "Rejected"
+	// StatusReasonExpired is the reason of the workflow progress condition which is Expired.
+	StatusReasonExpired = "Expired"
+	// StatusReasonCached is the reason of the workflow progress condition which is Cached.
+	StatusReasonCached = "Cached"
 )
 
 // IsStepFinish will decide whether step is finish.
@@ -16,6 +16,15 @@ limitations under the License.
 
 package types
 
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+)
+
 // IsBuiltinWorkflowStepType checks if workflow step type is builtin type
 func IsBuiltinWorkflowStepType(wfType string) bool {
 	for _, _type := range []string{
@@ -23,6 +32,8 @@ func IsBuiltinWorkflowStepType(wfType string) bool {
 		WorkflowStepTypeApplyComponent,
 		WorkflowStepTypeBuiltinApplyComponent,
 		WorkflowStepTypeStepGroup,
+		WorkflowStepTypeForeach,
+		WorkflowStepTypeApproval,
 	} {
 		if _type == wfType {
 			return true
@@ -30,3 +41,83 @@ func IsBuiltinWorkflowStepType(wfType string) bool {
 	}
 	return false
 }
+
+// GetMaxRetryTimes returns the max retry times of the step, the global max retry times is used if the step
+// doesn't specify the attempts in its retry policy.
+func GetMaxRetryTimes(retry *common.WorkflowStepRetry) int {
+	if retry == nil || retry.Attempts <= 0 {
+		return MaxWorkflowStepErrorRetryTimes
+	}
+	return retry.Attempts
+}
+
+// IsRetryableReason checks if the step failed with the reason can be retried.
+func IsRetryableReason(retry *common.WorkflowStepRetry, reason string) bool {
+	if retry == nil || len(retry.RetryableReasons) == 0 {
+		return true
+	}
+	for _, r := range retry.RetryableReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRetryBackoffWaitTime returns the seconds to wait before the next retry of the step by its retry policy,
+// the backoffTimes is the times that the step has been retried.
+func GetRetryBackoffWaitTime(retry *common.WorkflowStepRetry, backoffTimes int) int {
+	baseDelay := time.Second
+	if d, err := time.ParseDuration(retry.BaseDelay); err == nil && d > 0 {
+		baseDelay = d
+	}
+	maxDelay := time.Duration(MaxWorkflowFailedBackoffTime) * time.Second
+	if d, err := time.ParseDuration(retry.MaxDelay); err == nil && d > 0 {
+		maxDelay = d
+	}
+	delay := baseDelay
+	if retry.Backoff != common.WorkflowStepBackoffFixed && backoffTimes > 1 {
+		delay = time.Duration(float64(baseDelay) * math.Pow(2, float64(backoffTimes-1)))
+	}
+	if delay > maxDelay || delay <= 0 {
+		delay = maxDelay
+	}
+	seconds := int(math.Ceil(delay.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+// GetStepDeadline returns the deadline of the step by its timeout. The timeout can be a duration relative to
+// the first execute time of the step, such as 10m, or an absolute time in RFC3339 format, such as 2022-07-01T02:00:00+02:00.
+func GetStepDeadline(timeout string, firstExecuteTime time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(timeout); err == nil {
+		return firstExecuteTime.Add(d), nil
+	}
+	deadline, err := time.Parse(time.RFC3339, timeout)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timeout %s, it must be a duration or a time in RFC3339 format", timeout)
+	}
+	return deadline, nil
+}
+
+// GetForeachGeneratedName returns the name of the sub step or the output generated by the foreach step from the name in
+// the template sub step, it's prefixed by the name of the foreach step to avoid collision.
+func GetForeachGeneratedName(foreachName, name string, index int) string {
+	return fmt.Sprintf("%s-%s-%d", foreachName, name, index)
+}
+
+// IsForeachGeneratedName checks if the name may be generated by the foreach step from the name in the template sub step
+func IsForeachGeneratedName(foreachName, name, generated string) bool {
+	prefix := foreachName + "-" + name + "-"
+	if !strings.HasPrefix(generated, prefix) || len(generated) == len(prefix) {
+		return false
+	}
+	for _, c := range generated[len(prefix):] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
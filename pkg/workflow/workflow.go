@@ -70,12 +70,13 @@ const (
 )
 
 type workflow struct {
-	app     *oamcore.Application
-	cli     client.Client
-	wfCtx   wfContext.Context
-	rk      resourcekeeper.ResourceKeeper
-	dagMode bool
-	debug   bool
+	app          *oamcore.Application
+	cli          client.Client
+	wfCtx        wfContext.Context
+	rk           resourcekeeper.ResourceKeeper
+	dagMode      bool
+	debug        bool
+	exitHandlers *wfTypes.ExitHandlers
 }
 
 // NewWorkflow returns a Workflow implementation.
@@ -93,6 +94,11 @@ func NewWorkflow(app *oamcore.Application, cli client.Client, mode common.Workfl
 	}
 }
 
+// SetExitHandlers sets the exit handlers executed after the steps reach the terminal state.
+func (w *workflow) SetExitHandlers(handlers *wfTypes.ExitHandlers) {
+	w.exitHandlers = handlers
+}
+
 // ExecuteSteps process workflow step in order.
 func (w *workflow) ExecuteSteps(ctx monitorContext.Context, appRev *oamcore.ApplicationRevision, taskRunners []wfTypes.TaskRunner) (common.WorkflowState, error) {
 	revAndSpecHash, err := ComputeWorkflowRevisionHash(appRev.Name, w.app)
@@ -117,13 +123,13 @@ func (w *workflow) ExecuteSteps(ctx monitorContext.Context, appRev *oamcore.Appl
 		return common.WorkflowStateFinished, nil
 	}
 	if checkWorkflowTerminated(wfStatus, allTasksDone) {
-		return common.WorkflowStateTerminated, nil
+		return w.runExitHandlers(ctx, common.WorkflowStateTerminated)
 	}
 	if checkWorkflowSuspended(wfStatus) {
 		return common.WorkflowStateSuspended, nil
 	}
 	if allTasksSucceeded {
-		return common.WorkflowStateSucceeded, nil
+		return w.runExitHandlers(ctx, common.WorkflowStateSucceeded)
 	}
 
 	if cacheValue, ok := StepStatusCache.Load(cacheKey); ok {
@@ -158,7 +164,7 @@ func (w *workflow) ExecuteSteps(ctx monitorContext.Context, appRev *oamcore.Appl
 		e.cleanBackoffTimesForTerminated()
 		if checkWorkflowTerminated(wfStatus, allTasksDone) {
 			wfContext.CleanupMemoryStore(e.app.Name, e.app.Namespace)
-			return common.WorkflowStateTerminated, nil
+			return w.runExitHandlers(ctx, common.WorkflowStateTerminated)
 		}
 	}
 	if wfStatus.Suspend {
@@ -167,12 +173,77 @@ func (w *workflow) ExecuteSteps(ctx monitorContext.Context, appRev *oamcore.Appl
 	}
 	if allTasksSucceeded {
 		wfStatus.Message = string(common.WorkflowStateSucceeded)
-		return common.WorkflowStateSucceeded, nil
+		return w.runExitHandlers(ctx, common.WorkflowStateSucceeded)
 	}
 	wfStatus.Message = string(common.WorkflowStateExecuting)
 	return common.WorkflowStateExecuting, nil
 }
 
+// runExitHandlers executes the exit handlers after the steps reach the terminal state, the onSuccess or onFailure
+// handlers are executed with the finally handlers in DAG mode. The terminal state is returned once all the exit
+// handlers are finished, otherwise the workflow keeps executing.
+func (w *workflow) runExitHandlers(ctx monitorContext.Context, state common.WorkflowState) (common.WorkflowState, error) {
+	if w.exitHandlers == nil {
+		return state, nil
+	}
+	var taskRunners []wfTypes.TaskRunner
+	if state == common.WorkflowStateSucceeded {
+		taskRunners = append(taskRunners, w.exitHandlers.OnSuccess...)
+	} else {
+		taskRunners = append(taskRunners, w.exitHandlers.OnFailure...)
+	}
+	taskRunners = append(taskRunners, w.exitHandlers.Finally...)
+
+	wfStatus := w.app.Status.Workflow
+	if exitHandlersDone(taskRunners, wfStatus.ExitHandlers) {
+		return state, nil
+	}
+
+	if w.wfCtx == nil {
+		wfCtx, err := w.makeContext(w.app.Name)
+		if err != nil {
+			ctx.Error(err, "make context")
+			return common.WorkflowStateExecuting, err
+		}
+		w.wfCtx = wfCtx
+	}
+
+	// the exit handlers are recorded separately, so they run with their own status
+	exitStatus := &common.WorkflowStatus{
+		Mode:  common.WorkflowModeDAG,
+		Steps: wfStatus.ExitHandlers,
+	}
+	e := newEngine(ctx, w.wfCtx, w, exitStatus)
+	// let the exit handlers be able to check the status of the steps
+	setStepStatus(e.stepStatus, wfStatus.Steps)
+	err := e.Run(taskRunners, true)
+	wfStatus.ExitHandlers = exitStatus.Steps
+	if err != nil {
+		ctx.Error(err, "run exit handlers")
+		return common.WorkflowStateExecuting, err
+	}
+	if exitHandlersDone(taskRunners, wfStatus.ExitHandlers) {
+		return state, nil
+	}
+	return common.WorkflowStateExecuting, nil
+}
+
+func exitHandlersDone(taskRunners []wfTypes.TaskRunner, status []common.WorkflowStepStatus) bool {
+	for _, t := range taskRunners {
+		done := false
+		for _, ss := range status {
+			if ss.Name == t.Name() {
+				done = wfTypes.IsStepFinish(ss.Phase, ss.Reason)
+				break
+			}
+		}
+		if !done {
+			return false
+		}
+	}
+	return true
+}
+
 func checkWorkflowTerminated(wfStatus *common.WorkflowStatus, allTasksDone bool) bool {
 	// if all tasks are done, and the terminated is true, then the workflow is terminated
 	return wfStatus.Terminated && allTasksDone
@@ -182,11 +253,11 @@ func checkWorkflowSuspended(wfStatus *common.WorkflowStatus) bool {
 	// if workflow is suspended and the suspended step is still running, return false to run the suspended step
 	if wfStatus.Suspend {
 		for _, step := range wfStatus.Steps {
-			if step.Type == wfTypes.WorkflowStepTypeSuspend && step.Phase == common.WorkflowStepPhaseRunning {
+			if isWaitSuspendStep(step.StepStatus) {
 				return false
 			}
 			for _, sub := range step.SubStepsStatus {
-				if sub.Type == wfTypes.WorkflowStepTypeSuspend && sub.Phase == common.WorkflowStepPhaseRunning {
+				if isWaitSuspendStep(sub.StepStatus) {
 					return false
 				}
 			}
@@ -239,13 +310,21 @@ func newEngine(ctx monitorContext.Context, wfCtx wfContext.Context, w *workflow,
 	stepStatus := make(map[string]common.StepStatus)
 	setStepStatus(stepStatus, wfStatus.Steps)
 	stepDependsOn := make(map[string][]string)
+	stepRetry := make(map[string]*common.WorkflowStepRetry)
 	if w.app.Spec.Workflow != nil {
-		for _, step := range w.app.Spec.Workflow.Steps {
+		for _, step := range getWorkflowSteps(w.app.Spec.Workflow) {
 			hooks.SetAdditionalNameInStatus(stepStatus, step.Name, step.Properties, stepStatus[step.Name])
 			stepDependsOn[step.Name] = append(stepDependsOn[step.Name], step.DependsOn...)
+			if step.Retry != nil {
+				stepRetry[step.Name] = step.Retry
+			}
 			for _, sub := range step.SubSteps {
 				hooks.SetAdditionalNameInStatus(stepStatus, sub.Name, sub.Properties, stepStatus[step.Name])
 				stepDependsOn[sub.Name] = append(stepDependsOn[sub.Name], sub.DependsOn...)
+				// the retry policy of the foreach template is registered for the generated sub steps at runtime
+				if sub.Retry != nil && step.Type != wfTypes.WorkflowStepTypeForeach {
+					stepRetry[sub.Name] = sub.Retry
+				}
 			}
 		}
 	} else {
@@ -263,6 +342,7 @@ func newEngine(ctx monitorContext.Context, wfCtx wfContext.Context, w *workflow,
 		rk:            w.rk,
 		stepStatus:    stepStatus,
 		stepDependsOn: stepDependsOn,
+		stepRetry:     stepRetry,
 		stepTimeout:   make(map[string]time.Time),
 	}
 }
@@ -297,11 +377,11 @@ func (w *workflow) GetSuspendBackoffWaitTime() time.Duration {
 	max := time.Duration(1<<63 - 1)
 	min := max
 	for _, step := range w.app.Spec.Workflow.Steps {
-		if step.Type == wfTypes.WorkflowStepTypeSuspend || step.Type == wfTypes.WorkflowStepTypeStepGroup {
+		if isSuspendStepType(step.Type) || step.Type == wfTypes.WorkflowStepTypeStepGroup {
 			min = handleSuspendBackoffTime(step, stepStatus[step.Name], min)
 		}
 		for _, sub := range step.SubSteps {
-			if sub.Type == wfTypes.WorkflowStepTypeSuspend {
+			if isSuspendStepType(sub.Type) {
 				min = handleSuspendBackoffTime(oamcore.WorkflowStep{
 					Name:       sub.Name,
 					Type:       sub.Type,
@@ -320,27 +400,37 @@ func (w *workflow) GetSuspendBackoffWaitTime() time.Duration {
 func handleSuspendBackoffTime(step oamcore.WorkflowStep, status common.StepStatus, min time.Duration) time.Duration {
 	if status.Phase == common.WorkflowStepPhaseRunning {
 		if step.Timeout != "" {
-			duration, err := time.ParseDuration(step.Timeout)
+			timeout, err := wfTypes.GetStepDeadline(step.Timeout, status.FirstExecuteTime.Time)
 			if err != nil {
 				return min
 			}
-			timeout := status.FirstExecuteTime.Add(duration)
 			if time.Now().Before(timeout) {
-				d := time.Until(timeout)
-				if duration < min {
+				if d := time.Until(timeout); d < min {
 					min = d
 				}
 			}
 		}
 
-		d, err := wfTasks.GetSuspendStepDurationWaiting(step)
-		if err != nil {
+		var resumeTime time.Time
+		if step.Type == wfTypes.WorkflowStepTypeSuspend {
+			t, err := wfTasks.GetSuspendStepResumeTime(step, status.FirstExecuteTime.Time)
+			if err != nil {
+				return min
+			}
+			resumeTime = t
+		} else if status.NextExecuteTime != nil {
+			resumeTime = status.NextExecuteTime.Time
+		}
+		if resumeTime.IsZero() {
 			return min
 		}
-		if d != 0 && d < min {
+		d := time.Until(resumeTime)
+		if d < time.Second*minWorkflowBackoffWaitTime {
+			d = time.Second * minWorkflowBackoffWaitTime
+		}
+		if d < min {
 			min = d
 		}
-
 	}
 	return min
 }
@@ -423,6 +513,15 @@ func (w *workflow) setMetadataToContext(wfCtx wfContext.Context) error {
 	return wfCtx.SetVar(metadata, wfTypes.ContextKeyMetadata)
 }
 
+// getWorkflowSteps returns the steps of the workflow including the exit handlers
+func getWorkflowSteps(wf *oamcore.Workflow) []oamcore.WorkflowStep {
+	steps := make([]oamcore.WorkflowStep, 0, len(wf.Steps)+len(wf.OnSuccess)+len(wf.OnFailure)+len(wf.Finally))
+	steps = append(steps, wf.Steps...)
+	steps = append(steps, wf.OnSuccess...)
+	steps = append(steps, wf.OnFailure...)
+	return append(steps, wf.Finally...)
+}
+
 func (e *engine) getBackoffTimes(stepID string) int {
 	if v, ok := e.wfCtx.GetValueInMemory(wfTypes.ContextPrefixBackoffTimes, stepID); ok {
 		times, ok := v.(int)
@@ -437,36 +536,48 @@ func (e *engine) getBackoffWaitTime() int {
 	// the default value of min times reaches the max workflow backoff wait time
 	minTimes := 15
 	found := false
-	for _, step := range e.status.Steps {
-		if backoffTimes := e.getBackoffTimes(step.ID); backoffTimes > 0 {
-			found = true
-			if backoffTimes < minTimes {
-				minTimes = backoffTimes
-			}
+	// retryInterval is the min wait time of the steps that have their own retry policy
+	retryInterval := -1
+	checkStep := func(step common.StepStatus) {
+		backoffTimes := e.getBackoffTimes(step.ID)
+		if backoffTimes <= 0 {
+			return
 		}
-		if step.SubStepsStatus != nil {
-			for _, subStep := range step.SubStepsStatus {
-				if backoffTimes := e.getBackoffTimes(subStep.ID); backoffTimes > 0 {
-					found = true
-					if backoffTimes < minTimes {
-						minTimes = backoffTimes
-					}
-				}
+		if retry, ok := e.stepRetry[step.Name]; ok {
+			if interval := wfTypes.GetRetryBackoffWaitTime(retry, backoffTimes); retryInterval < 0 || interval < retryInterval {
+				retryInterval = interval
 			}
+			return
+		}
+		found = true
+		if backoffTimes < minTimes {
+			minTimes = backoffTimes
+		}
+	}
+	for _, step := range e.status.Steps {
+		checkStep(step.StepStatus)
+		for _, subStep := range step.SubStepsStatus {
+			checkStep(subStep.StepStatus)
 		}
 	}
 
 	if !found {
+		if retryInterval > 0 {
+			return retryInterval
+		}
 		return minWorkflowBackoffWaitTime
 	}
 
 	interval := int(math.Pow(2, float64(minTimes)) * backoffTimeCoefficient)
 	if interval < minWorkflowBackoffWaitTime {
-		return minWorkflowBackoffWaitTime
+		interval = minWorkflowBackoffWaitTime
 	}
 	maxWorkflowBackoffWaitTime := e.getMaxBackoffWaitTime()
 	if interval > maxWorkflowBackoffWaitTime {
-		return maxWorkflowBackoffWaitTime
+		interval = maxWorkflowBackoffWaitTime
+	}
+	if retryInterval > 0 && retryInterval < interval {
+		return retryInterval
 	}
 	return interval
 }
@@ -492,6 +603,19 @@ func (e *engine) getNextTimeout() int64 {
 					min = duration
 				}
 			}
+			// the step will be resumed at the next execute time, such as a suspend step waiting for a deadline
+			if step.NextExecuteTime != nil {
+				if duration := step.NextExecuteTime.Sub(now); duration < min {
+					min = duration
+				}
+			}
+			for _, sub := range step.SubStepsStatus {
+				if sub.Phase == common.WorkflowStepPhaseRunning && sub.NextExecuteTime != nil {
+					if duration := sub.NextExecuteTime.Sub(now); duration < min {
+						min = duration
+					}
+				}
+			}
 		}
 	}
 	if min == max {
@@ -632,7 +756,7 @@ func (e *engine) steps(taskRunners []wfTypes.TaskRunner, dag bool) error {
 		e.waiting = e.waiting || operation.Waiting
 		// for the suspend step with duration, there's no need to increase the backoff time in reconcile when it's still running
 		if !wfTypes.IsStepFinish(status.Phase, status.Reason) && !isWaitSuspendStep(status) {
-			if err := handleBackoffTimes(wfCtx, status, false); err != nil {
+			if err := handleBackoffTimes(wfCtx, status, e.stepRetry[status.Name], false); err != nil {
 				return err
 			}
 			if dag {
@@ -641,7 +765,7 @@ func (e *engine) steps(taskRunners []wfTypes.TaskRunner, dag bool) error {
 			return nil
 		}
 		// clear the backoff time when the step is finished
-		if err := handleBackoffTimes(wfCtx, status, true); err != nil {
+		if err := handleBackoffTimes(wfCtx, status, e.stepRetry[status.Name], true); err != nil {
 			return err
 		}
 
@@ -696,12 +820,11 @@ func (e *engine) generateRunOptions(dependsOnPhase common.WorkflowStepPhase) *wf
 					}
 				}
 				if !status.FirstExecuteTime.Time.IsZero() && step.Timeout != "" {
-					duration, err := time.ParseDuration(step.Timeout)
+					timeout, err := wfTypes.GetStepDeadline(step.Timeout, status.FirstExecuteTime.Time)
 					if err != nil {
-						// if the timeout is a invalid duration, return {timeout: false}
+						// if the timeout is invalid, return {timeout: false}
 						return &wfTypes.PreCheckResult{Timeout: false}, err
 					}
-					timeout := status.FirstExecuteTime.Add(duration)
 					e.stepTimeout[step.Name] = timeout
 					if time.Now().After(timeout) {
 						return &wfTypes.PreCheckResult{Timeout: true}, nil
@@ -739,6 +862,7 @@ type engine struct {
 	stepStatus         map[string]common.StepStatus
 	stepTimeout        map[string]time.Time
 	stepDependsOn      map[string][]string
+	stepRetry          map[string]*common.WorkflowStepRetry
 }
 
 func (e *engine) finishStep(operation *wfTypes.Operation) {
@@ -873,15 +997,22 @@ func isUnsuccessfulStep(phase common.WorkflowStepPhase) bool {
 }
 
 func isWaitSuspendStep(step common.StepStatus) bool {
-	return step.Type == wfTypes.WorkflowStepTypeSuspend && step.Phase == common.WorkflowStepPhaseRunning
+	return isSuspendStepType(step.Type) && step.Phase == common.WorkflowStepPhaseRunning
+}
+
+// isSuspendStepType checks if the step suspends the workflow while it's running, such as suspend and approval
+func isSuspendStepType(stepType string) bool {
+	return stepType == wfTypes.WorkflowStepTypeSuspend || stepType == wfTypes.WorkflowStepTypeApproval
 }
 
-func handleBackoffTimes(wfCtx wfContext.Context, status common.StepStatus, clear bool) error {
+func handleBackoffTimes(wfCtx wfContext.Context, status common.StepStatus, retry *common.WorkflowStepRetry, clear bool) error {
 	if clear {
 		wfCtx.DeleteValueInMemory(wfTypes.ContextPrefixBackoffTimes, status.ID)
 		wfCtx.DeleteValueInMemory(wfTypes.ContextPrefixBackoffReason, status.ID)
 	} else {
-		if val, exists := wfCtx.GetValueInMemory(wfTypes.ContextPrefixBackoffReason, status.ID); !exists || val != status.Message {
+		// the backoff times of the step with retry policy keep growing even if the failure message changes,
+		// so that the backoff of the flaky step won't be reset by the different errors.
+		if val, exists := wfCtx.GetValueInMemory(wfTypes.ContextPrefixBackoffReason, status.ID); !exists || (val != status.Message && retry == nil) {
 			wfCtx.SetValueInMemory(status.Message, wfTypes.ContextPrefixBackoffReason, status.ID)
 			wfCtx.DeleteValueInMemory(wfTypes.ContextPrefixBackoffTimes, status.ID)
 		}
@@ -929,6 +1060,13 @@ func (e *engine) SetParentRunner(name string) {
 	e.parentRunner = name
 }
 
+// SetStepRetry registers the retry policy of the step generated at runtime, such as the sub steps of foreach
+func (e *engine) SetStepRetry(stepName string, retry *common.WorkflowStepRetry) {
+	if retry != nil {
+		e.stepRetry[stepName] = retry
+	}
+}
+
 func (e *engine) GetOperation() *wfTypes.Operation {
 	return &wfTypes.Operation{
 		Suspend:            e.status.Suspend,
@@ -2121,6 +2121,70 @@ var _ = Describe("Test Workflow", func() {
 		Expect(err).ToNot(HaveOccurred())
 		Expect(saved).Should(BeEquivalentTo(true))
 	})
+
+	It("test for exit handlers", func() {
+		app, runners := makeTestCase([]oamcore.WorkflowStep{
+			{
+				Name: "s1",
+				Type: "success",
+			},
+			{
+				Name: "s2",
+				Type: "terminate",
+			},
+		})
+		app.Spec.Workflow.OnSuccess = []oamcore.WorkflowStep{{Name: "on-success", Type: "success"}}
+		app.Spec.Workflow.OnFailure = []oamcore.WorkflowStep{{Name: "on-failure", Type: "running"}}
+		app.Spec.Workflow.Finally = []oamcore.WorkflowStep{{Name: "finally", Type: "success"}}
+		handlers := &wfTypes.ExitHandlers{
+			OnSuccess: []wfTypes.TaskRunner{makeRunner(app.Spec.Workflow.OnSuccess[0], nil)},
+			OnFailure: []wfTypes.TaskRunner{makeRunner(app.Spec.Workflow.OnFailure[0], nil)},
+			Finally:   []wfTypes.TaskRunner{makeRunner(app.Spec.Workflow.Finally[0], nil)},
+		}
+		ctx := monitorContext.NewTraceContext(context.Background(), "test-app")
+		wf := NewWorkflow(app, k8sClient, common.WorkflowModeStep, false, nil)
+		wf.SetExitHandlers(handlers)
+		state, err := wf.ExecuteSteps(ctx, revision, runners)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state).Should(BeEquivalentTo(common.WorkflowStateInitializing))
+		state, err = wf.ExecuteSteps(ctx, revision, runners)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state).Should(BeEquivalentTo(common.WorkflowStateExecuting))
+		Expect(app.Status.Workflow.Terminated).Should(BeTrue())
+		Expect(len(app.Status.Workflow.Steps)).Should(BeEquivalentTo(2))
+		exitHandlers := app.Status.Workflow.ExitHandlers
+		Expect(len(exitHandlers)).Should(BeEquivalentTo(2))
+		Expect(exitHandlers[0].Name).Should(BeEquivalentTo("on-failure"))
+		Expect(exitHandlers[0].Phase).Should(BeEquivalentTo(common.WorkflowStepPhaseRunning))
+		Expect(exitHandlers[1].Name).Should(BeEquivalentTo("finally"))
+		Expect(exitHandlers[1].Phase).Should(BeEquivalentTo(common.WorkflowStepPhaseSucceeded))
+
+		handlers.OnFailure = []wfTypes.TaskRunner{makeRunner(oamcore.WorkflowStep{Name: "on-failure", Type: "success"}, nil)}
+		state, err = wf.ExecuteSteps(ctx, revision, runners)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state).Should(BeEquivalentTo(common.WorkflowStateTerminated))
+		Expect(app.Status.Workflow.ExitHandlers[0].Phase).Should(BeEquivalentTo(common.WorkflowStepPhaseSucceeded))
+
+		app, runners = makeTestCase([]oamcore.WorkflowStep{
+			{
+				Name: "s1",
+				Type: "success",
+			},
+		})
+		app.Spec.Workflow.OnSuccess = []oamcore.WorkflowStep{{Name: "on-success", Type: "success"}}
+		wf = NewWorkflow(app, k8sClient, common.WorkflowModeStep, false, nil)
+		wf.SetExitHandlers(handlers)
+		state, err = wf.ExecuteSteps(ctx, revision, runners)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state).Should(BeEquivalentTo(common.WorkflowStateInitializing))
+		state, err = wf.ExecuteSteps(ctx, revision, runners)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state).Should(BeEquivalentTo(common.WorkflowStateSucceeded))
+		exitHandlers = app.Status.Workflow.ExitHandlers
+		Expect(len(exitHandlers)).Should(BeEquivalentTo(2))
+		Expect(exitHandlers[0].Name).Should(BeEquivalentTo("on-success"))
+		Expect(exitHandlers[1].Name).Should(BeEquivalentTo("finally"))
+	})
 })
 
 func makeTestCase(steps []oamcore.WorkflowStep) (*oamcore.Application, []wfTypes.TaskRunner) {
@@ -96,6 +96,7 @@ func NewCommandWithIOStreams(ioStream util.IOStreams) *cobra.Command {
 		NewLiveDiffCommand(commandArgs, "2", ioStream),
 		NewDryRunCommand(commandArgs, ioStream),
 		RevisionCommandGroup(commandArgs),
+		GCCommandGroup(f, ioStream),
 
 		// Workflows
 		NewWorkflowCommand(commandArgs, ioStream),
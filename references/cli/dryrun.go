@@ -46,6 +46,7 @@ type DryRunCmdOptions struct {
 	ApplicationFile string
 	DefinitionFile  string
 	OfflineMode     bool
+	Workflow        bool
 }
 
 // NewDryRunCommand creates `dry-run` command
@@ -60,6 +61,9 @@ func NewDryRunCommand(c common.Args, ioStreams cmdutil.IOStreams) *cobra.Command
 
 You can also specify a remote url for app:
 	vela dry-run -d <definition file or directory> -f https://<remote-host>/app.yaml
+
+You can also simulate the workflow against a fake cluster, the provider calls and the final step phases will be printed:
+	vela dry-run -f /path/to/app.yaml --workflow
 `,
 		Example: "vela dry-run",
 		Annotations: map[string]string{
@@ -89,6 +93,7 @@ You can also specify a remote url for app:
 	cmd.Flags().StringVarP(&o.ApplicationFile, "file", "f", "./app.yaml", "application file name")
 	cmd.Flags().StringVarP(&o.DefinitionFile, "definition", "d", "", "specify a definition file or directory, it will only be used in dry-run rather than applied to K8s cluster")
 	cmd.Flags().BoolVar(&o.OfflineMode, "offline", false, "Run `dry-run` in offline / local mode, all validation steps will be skipped")
+	cmd.Flags().BoolVar(&o.Workflow, "workflow", false, "Simulate the workflow against a fake cluster and print the step graph, the provider calls and the final step phases")
 	addNamespaceAndEnvArg(cmd)
 	cmd.SetOut(ioStreams.Out)
 	return cmd
@@ -157,6 +162,15 @@ func DryRunApplication(cmdOption *DryRunCmdOptions, c common.Args, namespace str
 	if err = dryRunOpt.PrintDryRun(&buff, app.Name, comps, policies); err != nil {
 		return buff, err
 	}
+	if cmdOption.Workflow {
+		sim, err := dryRunOpt.SimulateWorkflow(ctx, app)
+		if err != nil {
+			return buff, errors.WithMessage(err, "simulate workflow")
+		}
+		if err = dryRunOpt.PrintWorkflowSimulation(&buff, app.Name, sim); err != nil {
+			return buff, err
+		}
+	}
 	return buff, nil
 }
 
@@ -0,0 +1,376 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	velacmd "github.com/oam-dev/kubevela/pkg/cmd"
+	cmdutil "github.com/oam-dev/kubevela/pkg/cmd/util"
+	"github.com/oam-dev/kubevela/pkg/resourcekeeper"
+	"github.com/oam-dev/kubevela/pkg/utils/util"
+)
+
+// gcKeepSelectorFields are the fields of the resource policy rule selector supported by the --keep flag
+var gcKeepSelectorFields = map[string]bool{
+	"componentNames": true,
+	"componentTypes": true,
+	"oamTypes":       true,
+	"traitTypes":     true,
+	"resourceTypes":  true,
+	"resourceNames":  true,
+	"namespaces":     true,
+	"clusters":       true,
+	"apiGroups":      true,
+	"apiVersions":    true,
+}
+
+// GCCommandGroup commands for the garbage collection of application resources
+func GCCommandGroup(f velacmd.Factory, streams util.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: i18n.T("Preview or run the garbage collection of application resources."),
+		Annotations: map[string]string{
+			types.TagCommandType: types.TypeApp,
+		},
+	}
+	cmd.AddCommand(NewGCPreviewCommand(f, streams))
+	cmd.AddCommand(NewGCRunCommand(f, streams))
+	return cmd
+}
+
+// GCOptions options for the garbage collection of application resources
+type GCOptions struct {
+	AppName   string
+	Namespace string
+	Output    string
+	Keep      []string
+	DryRun    bool
+	Force     bool
+
+	keepSelectors []v1alpha1.ResourcePolicyRuleSelector
+
+	util.IOStreams
+}
+
+// Complete .
+func (opt *GCOptions) Complete(f velacmd.Factory, cmd *cobra.Command, args []string) {
+	opt.AppName = args[0]
+	opt.Namespace = velacmd.GetNamespace(f, cmd)
+	if opt.Namespace == "" {
+		opt.Namespace = types.DefaultAppNamespace
+	}
+}
+
+// Validate parses the keep selectors and checks the output format
+func (opt *GCOptions) Validate() error {
+	switch opt.Output {
+	case "", "json", "yaml":
+	default:
+		return fmt.Errorf("unsupported output format %s, supported formats are json and yaml", opt.Output)
+	}
+	opt.keepSelectors = nil
+	for _, keep := range opt.Keep {
+		selector, err := parseGCKeepSelector(keep)
+		if err != nil {
+			return err
+		}
+		opt.keepSelectors = append(opt.keepSelectors, *selector)
+	}
+	return nil
+}
+
+// parseGCKeepSelector parses the selector like `componentNames=web,db;resourceTypes=Service`, the conditions
+// separated by `;` must be all matched
+func parseGCKeepSelector(keep string) (*v1alpha1.ResourcePolicyRuleSelector, error) {
+	conditions := map[string][]string{}
+	for _, condition := range strings.Split(keep, ";") {
+		if strings.TrimSpace(condition) == "" {
+			continue
+		}
+		parts := strings.SplitN(condition, "=", 2)
+		field := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || !gcKeepSelectorFields[field] {
+			return nil, fmt.Errorf("invalid keep selector %q, expect conditions like componentNames=web,db separated by ;", keep)
+		}
+		for _, value := range strings.Split(parts[1], ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				conditions[field] = append(conditions[field], value)
+			}
+		}
+	}
+	if len(conditions) == 0 {
+		return nil, fmt.Errorf("invalid keep selector %q, no condition is specified", keep)
+	}
+	bs, err := json.Marshal(conditions)
+	if err != nil {
+		return nil, err
+	}
+	selector := &v1alpha1.ResourcePolicyRuleSelector{}
+	if err = json.Unmarshal(bs, selector); err != nil {
+		return nil, err
+	}
+	selector.Operator = v1alpha1.ResourcePolicyRuleSelectorOperatorAnd
+	return selector, nil
+}
+
+func (opt *GCOptions) getApplication(ctx context.Context, cli client.Client) (*v1beta1.Application, error) {
+	app := &v1beta1.Application{}
+	if err := cli.Get(ctx, apitypes.NamespacedName{Namespace: opt.Namespace, Name: opt.AppName}, app); err != nil {
+		return nil, fmt.Errorf("failed to get application %s/%s: %w", opt.Namespace, opt.AppName, err)
+	}
+	return app, nil
+}
+
+func (opt *GCOptions) getResourceKeeper(ctx context.Context, cli client.Client) (resourcekeeper.ResourceKeeper, error) {
+	app, err := opt.getApplication(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+	return resourcekeeper.NewResourceKeeper(ctx, cli, app)
+}
+
+// checkApplicationSettled refuses the manual garbage collection unless the application is running and its workflow
+// is finished. The resources of the outdated versions may be still in use while the latest version is being deployed
+// or is failed, recycling them in that case can break the application.
+func checkApplicationSettled(app *v1beta1.Application) error {
+	if app.Status.Phase != common.ApplicationRunning {
+		return fmt.Errorf("application %s/%s is %s, the garbage collection can only run when it's %s, use --force to run it anyway",
+			app.Namespace, app.Name, app.Status.Phase, common.ApplicationRunning)
+	}
+	if app.Status.Workflow != nil && !app.Status.Workflow.Finished {
+		return fmt.Errorf("the workflow of application %s/%s is not finished, use --force to run the garbage collection anyway",
+			app.Namespace, app.Name)
+	}
+	return nil
+}
+
+// printPlan prints the garbage collection plan in the output format
+func (opt *GCOptions) printPlan(plan *resourcekeeper.GarbageCollectPlan) error {
+	switch opt.Output {
+	case "json":
+		bs, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+		opt.Infonln(string(bs))
+		return nil
+	case "yaml":
+		bs, err := yaml.Marshal(plan)
+		if err != nil {
+			return err
+		}
+		opt.Info(string(bs))
+		return nil
+	default:
+	}
+	if len(plan.ResourceTrackers) == 0 {
+		opt.Infof("No resourcetracker of application %s/%s will be recycled.\n", opt.Namespace, opt.AppName)
+		return nil
+	}
+	opt.Infof("ResourceTrackers to recycle: %s\n", strings.Join(plan.ResourceTrackers, ", "))
+	if len(plan.Resources) == 0 {
+		opt.Infof("No resource will be recycled.\n")
+		return nil
+	}
+	table := newUITable().AddRow("ORDER", "ACTION", "CLUSTER", "NAMESPACE", "RESOURCE", "COMPONENT", "RESOURCETRACKER", "REASON", "BLOCKED-BY")
+	for _, resource := range plan.Resources {
+		cluster := resource.Cluster
+		if cluster == "" {
+			cluster = types.ClusterLocalName
+		}
+		table.AddRow(resource.Order, resource.Action, cluster, resource.Namespace, resource.Kind+"/"+resource.Name,
+			resource.Component, resource.ResourceTracker, resource.Reason, strings.Join(resource.BlockedBy, ","))
+	}
+	opt.Infonln(table.String())
+	return nil
+}
+
+// Preview prints the plan of the next garbage collection of the application
+func (opt *GCOptions) Preview(ctx context.Context, cli client.Client) error {
+	rk, err := opt.getResourceKeeper(ctx, cli)
+	if err != nil {
+		return err
+	}
+	plan, err := rk.PreviewGarbageCollect(ctx)
+	if err != nil {
+		return err
+	}
+	return opt.printPlan(plan)
+}
+
+// Run recycles the outdated versioned resourcetrackers of the application manually
+func (opt *GCOptions) Run(ctx context.Context, cli client.Client) error {
+	app, err := opt.getApplication(ctx, cli)
+	if err != nil {
+		return err
+	}
+	if !opt.DryRun && !opt.Force {
+		if err = checkApplicationSettled(app); err != nil {
+			return err
+		}
+	}
+	rk, err := resourcekeeper.NewResourceKeeper(ctx, cli, app)
+	if err != nil {
+		return err
+	}
+	options := []resourcekeeper.GCOption{resourcekeeper.ManualGCOption{Keep: opt.keepSelectors}}
+	plan, err := rk.PreviewGarbageCollect(ctx, options...)
+	if err != nil {
+		return err
+	}
+	if err = opt.printPlan(plan); err != nil || opt.DryRun || len(plan.ResourceTrackers) == 0 {
+		return err
+	}
+	if !assumeYes {
+		if !NewUserInput().AskBool(fmt.Sprintf("Do you want to recycle the resources of application %s/%s", opt.Namespace, opt.AppName), &UserInputOptions{assumeYes}) {
+			return fmt.Errorf("stopping garbage collection")
+		}
+	}
+	options = append(options, resourcekeeper.DisableGCComponentRevisionOption{}, resourcekeeper.DisableLegacyGCOption{})
+	finished, waiting, err := rk.GarbageCollect(ctx, options...)
+	if err != nil {
+		return err
+	}
+	if !finished && len(waiting) > 0 {
+		opt.Infof("The garbage collection is in progress, waiting for %d resources to be deleted, including %s.\n", len(waiting), waiting[0].DisplayName())
+		return nil
+	}
+	opt.Infof("The garbage collection of application %s/%s is finished.\n", opt.Namespace, opt.AppName)
+	return nil
+}
+
+var (
+	gcPreviewLong = templates.LongDesc(i18n.T(`
+		Preview the garbage collection of application resources
+
+		Preview the resources to be recycled by the next garbage collection of the application,
+		without changing anything in the clusters. The plan shows the resourcetrackers to be
+		recycled, and for each resource, the action, the reason and the order of deletion. When
+		the garbage-collect policy uses the dependency order, the resources are deleted after the
+		resources of their dependent components (BLOCKED-BY) are gone.`))
+
+	gcPreviewExample = templates.Examples(i18n.T(`
+		# Preview the garbage collection of the application
+		vela gc preview my-app -n demo
+
+		# Preview the garbage collection plan in JSON
+		vela gc preview my-app -o json`))
+
+	gcRunLong = templates.LongDesc(i18n.T(`
+		Recycle the outdated resourcetrackers of the application manually
+
+		When the garbage-collect policy sets keepLegacyResource, the outdated versioned
+		resourcetrackers are kept until all their resources are taken over by later versions.
+		This command recycles them once and for all. Use --keep to select the resources to
+		leave in the clusters, the kept resources are no longer tracked by the application.
+
+		The command only runs when the application is running and its workflow is finished,
+		since the resources of the outdated versions may be still in use while the latest
+		version is being deployed or is failed. Use --force to run it anyway.
+
+		The --keep selector is made of the conditions separated by ";" and all the conditions
+		must match. Each condition is like componentNames=web,db, the supported fields are
+		componentNames, componentTypes, oamTypes, traitTypes, resourceTypes, resourceNames,
+		namespaces, clusters, apiGroups and apiVersions. The values support wildcards like web-*
+		and exclusion like !web-canary. Multiple --keep flags keep the resources matching any of them.`))
+
+	gcRunExample = templates.Examples(i18n.T(`
+		# Recycle the outdated resourcetrackers of the application
+		vela gc run my-app -n demo
+
+		# Recycle the outdated resourcetrackers but keep the PersistentVolumeClaims of the database component
+		vela gc run my-app --keep "componentNames=database;resourceTypes=PersistentVolumeClaim"
+
+		# Show what will be recycled without changing anything
+		vela gc run my-app --keep "resourceTypes=Secret,ConfigMap" --dry-run`))
+)
+
+// NewGCPreviewCommand command for previewing the garbage collection of application resources
+func NewGCPreviewCommand(f velacmd.Factory, streams util.IOStreams) *cobra.Command {
+	o := &GCOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "preview APP",
+		Short:   i18n.T("Preview the garbage collection of application resources."),
+		Long:    gcPreviewLong,
+		Example: gcPreviewExample,
+		Annotations: map[string]string{
+			types.TagCommandType: types.TypeApp,
+		},
+		Args: cobra.ExactValidArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return velacmd.GetApplicationsForCompletion(cmd.Context(), f, velacmd.GetNamespace(f, cmd), toComplete)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Complete(f, cmd, args)
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Preview(cmd.Context(), f.Client()))
+		},
+	}
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "The output format of the plan. One of: (json, yaml). If empty, the plan is printed as a table.")
+	return velacmd.NewCommandBuilder(f, cmd).
+		WithNamespaceFlag().
+		WithStreams(streams).
+		WithResponsiveWriter().
+		Build()
+}
+
+// NewGCRunCommand command for recycling the outdated resourcetrackers of application manually
+func NewGCRunCommand(f velacmd.Factory, streams util.IOStreams) *cobra.Command {
+	o := &GCOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "run APP",
+		Short:   i18n.T("Recycle the outdated resourcetrackers of application manually."),
+		Long:    gcRunLong,
+		Example: gcRunExample,
+		Annotations: map[string]string{
+			types.TagCommandType: types.TypeApp,
+		},
+		Args: cobra.ExactValidArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return velacmd.GetApplicationsForCompletion(cmd.Context(), f, velacmd.GetNamespace(f, cmd), toComplete)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Complete(f, cmd, args)
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run(cmd.Context(), f.Client()))
+		},
+	}
+	cmd.Flags().StringArrayVarP(&o.Keep, "keep", "", o.Keep, "The selector of the resources to leave in the clusters, like componentNames=web;resourceTypes=Service.")
+	cmd.Flags().BoolVarP(&o.DryRun, FlagDryRun, "", o.DryRun, "Setting this flag will not recycle anything. It will print out the resources to be recycled.")
+	cmd.Flags().BoolVarP(&o.Force, "force", "f", o.Force, "Run the garbage collection even if the application is not running or its workflow is not finished.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "The output format of the plan. One of: (json, yaml). If empty, the plan is printed as a table.")
+	return velacmd.NewCommandBuilder(f, cmd).
+		WithNamespaceFlag().
+		WithStreams(streams).
+		WithResponsiveWriter().
+		Build()
+}
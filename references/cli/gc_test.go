@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+)
+
+func TestParseGCKeepSelector(t *testing.T) {
+	r := require.New(t)
+	selector, err := parseGCKeepSelector("componentNames=web, db;resourceTypes=PersistentVolumeClaim;")
+	r.NoError(err)
+	r.Equal(&v1alpha1.ResourcePolicyRuleSelector{
+		CompNames:     []string{"web", "db"},
+		ResourceTypes: []string{"PersistentVolumeClaim"},
+		Operator:      v1alpha1.ResourcePolicyRuleSelectorOperatorAnd,
+	}, selector)
+
+	_, err = parseGCKeepSelector("components=web")
+	r.Error(err)
+	_, err = parseGCKeepSelector("componentNames")
+	r.Error(err)
+	_, err = parseGCKeepSelector(";")
+	r.Error(err)
+
+	opt := &GCOptions{Keep: []string{"clusters=local", "namespaces=!default"}}
+	r.NoError(opt.Validate())
+	r.Len(opt.keepSelectors, 2)
+	opt.Output = "table"
+	r.Error(opt.Validate())
+}
+
+func TestCheckApplicationSettled(t *testing.T) {
+	r := require.New(t)
+	app := &v1beta1.Application{}
+	app.Status.Phase = common.ApplicationRunning
+	app.Status.Workflow = &common.WorkflowStatus{Finished: true}
+	r.NoError(checkApplicationSettled(app))
+
+	app.Status.Workflow.Finished = false
+	r.Error(checkApplicationSettled(app))
+
+	app.Status.Phase = common.ApplicationWorkflowTerminated
+	app.Status.Workflow.Finished = true
+	r.Error(checkApplicationSettled(app))
+}
@@ -283,6 +283,25 @@ func printWorkflowStatus(c client.Client, ioStreams cmdutil.IOStreams, appName s
 			ioStreams.Infof("    type: %s\n", step.Type)
 			ioStreams.Infof("    phase: %s \n", getWfStepColor(step.Phase).Sprint(step.Phase))
 			ioStreams.Infof("    message: %s\n", step.Message)
+			if step.NextExecuteTime != nil {
+				ioStreams.Infof("    nextExecuteTime: %s\n", step.NextExecuteTime.Format(time.RFC3339))
+			}
+			if len(step.Approvals) > 0 {
+				ioStreams.Info("    approvals:")
+				for _, approval := range step.Approvals {
+					ioStreams.Infof("    - %s by %s at %s %s\n", approval.Action, approval.Approver, approval.Time.Format(time.RFC3339), approval.Message)
+				}
+			}
+		}
+		if len(workflowStatus.ExitHandlers) > 0 {
+			ioStreams.Info("  Exit Handlers")
+			for _, step := range workflowStatus.ExitHandlers {
+				ioStreams.Infof("  - id: %s\n", step.ID)
+				ioStreams.Infof("    name: %s\n", step.Name)
+				ioStreams.Infof("    type: %s\n", step.Type)
+				ioStreams.Infof("    phase: %s \n", getWfStepColor(step.Phase).Sprint(step.Phase))
+				ioStreams.Infof("    message: %s\n", step.Message)
+			}
 		}
 		ioStreams.Infof("\n")
 	}
@@ -22,14 +22,18 @@ import (
 
 	"github.com/spf13/cobra"
 	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 
+	apicommon "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/auth"
 	"github.com/oam-dev/kubevela/pkg/multicluster"
 	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/utils/common"
 	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
 	"github.com/oam-dev/kubevela/pkg/workflow/operation"
+	wfTypes "github.com/oam-dev/kubevela/pkg/workflow/types"
 	"github.com/oam-dev/kubevela/references/appfile"
 )
 
@@ -46,6 +50,7 @@ func NewWorkflowCommand(c common.Args, ioStreams cmdutil.IOStreams) *cobra.Comma
 	cmd.AddCommand(
 		NewWorkflowSuspendCommand(c, ioStreams),
 		NewWorkflowResumeCommand(c, ioStreams),
+		NewWorkflowRejectCommand(c, ioStreams),
 		NewWorkflowTerminateCommand(c, ioStreams),
 		NewWorkflowRestartCommand(c, ioStreams),
 		NewWorkflowRollbackCommand(c, ioStreams),
@@ -121,14 +126,94 @@ func NewWorkflowResumeCommand(c common.Args, ioStream cmdutil.IOStreams) *cobra.
 				return err
 			}
 
+			approver, err := getWorkflowApprover(context.Background(), c, app)
+			if err != nil {
+				return err
+			}
+			wo := operation.NewWorkflowOperator(cli, cmd.OutOrStdout())
+			return wo.Resume(context.Background(), app, approver)
+		},
+	}
+	addNamespaceAndEnvArg(cmd)
+	return cmd
+}
+
+// NewWorkflowRejectCommand create workflow reject command
+func NewWorkflowRejectCommand(c common.Args, ioStream cmdutil.IOStreams) *cobra.Command {
+	var message string
+	cmd := &cobra.Command{
+		Use:     "reject",
+		Short:   "Reject the approval steps of an application workflow.",
+		Long:    "Reject the running approval steps of an application workflow in cluster, the workflow will be terminated.",
+		Example: "vela workflow reject <application-name> --message <reason>",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return fmt.Errorf("must specify application name")
+			}
+			namespace, err := GetFlagNamespaceOrEnv(cmd, c)
+			if err != nil {
+				return err
+			}
+			app, err := appfile.LoadApplication(namespace, args[0], c)
+			if err != nil {
+				return err
+			}
+			cli, err := c.GetClient()
+			if err != nil {
+				return err
+			}
+
+			approver, err := getWorkflowApprover(context.Background(), c, app)
+			if err != nil {
+				return err
+			}
 			wo := operation.NewWorkflowOperator(cli, cmd.OutOrStdout())
-			return wo.Resume(context.Background(), app)
+			return wo.Reject(context.Background(), app, approver, message)
 		},
 	}
 	addNamespaceAndEnvArg(cmd)
+	cmd.Flags().StringVarP(&message, "message", "m", "", "The reason of the rejection")
 	return cmd
 }
 
+// getWorkflowApprover asks the apiserver for the identity of the current user if the workflow is waiting for the
+// approvals. It's only used to check whether the user is eligible for the approval steps, the approvals are recorded
+// by the webhook with the identity of the authenticated request.
+func getWorkflowApprover(ctx context.Context, c common.Args, app *v1beta1.Application) (*auth.Identity, error) {
+	if !hasRunningApprovalStep(app) {
+		return nil, nil
+	}
+	config, err := c.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	identity, err := auth.ReviewIdentity(ctx, rest.CopyConfig(config))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the identity of the approver: %w", err)
+	}
+	return identity, nil
+}
+
+func hasRunningApprovalStep(app *v1beta1.Application) bool {
+	if app.Status.Workflow == nil {
+		return false
+	}
+	isRunningApproval := func(status apicommon.StepStatus) bool {
+		return status.Type == wfTypes.WorkflowStepTypeApproval && status.Phase == apicommon.WorkflowStepPhaseRunning
+	}
+	for _, step := range app.Status.Workflow.Steps {
+		if isRunningApproval(step.StepStatus) {
+			return true
+		}
+		for _, sub := range step.SubStepsStatus {
+			if isRunningApproval(sub.StepStatus) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // NewWorkflowTerminateCommand create workflow terminate command
 func NewWorkflowTerminateCommand(c common.Args, ioStream cmdutil.IOStreams) *cobra.Command {
 	cmd := &cobra.Command{
@@ -19,6 +19,8 @@ package cli
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
@@ -27,9 +29,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	common2 "github.com/oam-dev/kubevela/pkg/utils/common"
 	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
 	wfTypes "github.com/oam-dev/kubevela/pkg/workflow/types"
 )
@@ -614,3 +618,32 @@ func TestWorkflowRollback(t *testing.T) {
 		})
 	}
 }
+
+func TestGetWorkflowApprover(t *testing.T) {
+	r := require.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/apis/authentication.k8s.io/v1/selfsubjectreviews" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"apiVersion":"authentication.k8s.io/v1","kind":"SelfSubjectReview","status":{"userInfo":{"username":"alice","groups":["sre"]}}}`))
+	}))
+	defer server.Close()
+	c := common2.Args{}
+	r.NoError(c.SetConfig(&rest.Config{Host: server.URL}))
+
+	app := &v1beta1.Application{Status: common.AppStatus{Workflow: &common.WorkflowStatus{
+		Steps: []common.WorkflowStepStatus{{StepStatus: common.StepStatus{Type: "suspend", Phase: common.WorkflowStepPhaseRunning}}},
+	}}}
+	approver, err := getWorkflowApprover(context.Background(), c, app)
+	r.NoError(err)
+	r.Nil(approver)
+
+	app.Status.Workflow.Steps[0].SubStepsStatus = []common.WorkflowSubStepStatus{{
+		StepStatus: common.StepStatus{Type: wfTypes.WorkflowStepTypeApproval, Phase: common.WorkflowStepPhaseRunning},
+	}}
+	approver, err = getWorkflowApprover(context.Background(), c, app)
+	r.NoError(err)
+	r.Equal("alice", approver.User)
+	r.Equal([]string{"sre"}, approver.Groups)
+}
@@ -0,0 +1,67 @@
+"drift-detection": {
+	annotations: {}
+	description: "Detect the drift of the managed resources from the desired state, and choose to correct, report or alert the drift."
+	labels: {}
+	attributes: {}
+	type: "policy"
+}
+
+template: {
+	#DriftDetectionPolicyRule: {
+		// +usage=Specify how to select the targets of the rule
+		selector: #ResourcePolicyRuleSelector
+		// +usage=Specify how to handle the drift of the selected resources
+		mode: "correct" | "report" | "alert"
+	}
+
+	#ResourcePolicyRuleSelector: {
+		#ResourcePolicyRuleSelectorTerm
+		// +usage=Select resources matching all the selectors
+		allOf?: [...#ResourcePolicyRuleSelectorTerm]
+		// +usage=Select resources matching any of the selectors
+		anyOf?: [...#ResourcePolicyRuleSelectorTerm]
+		// +usage=Exclude resources matching the selector
+		not?: #ResourcePolicyRuleSelectorTerm
+	}
+
+	#ResourcePolicyRuleSelectorTerm: {
+		// +usage=Select resources by component names, the values support wildcards like web-* and exclusion like !web-canary
+		componentNames?: [...string]
+		// +usage=Select resources by component types
+		componentTypes?: [...string]
+		// +usage=Select resources by oamTypes (COMPONENT or TRAIT)
+		oamTypes?: [...string]
+		// +usage=Select resources by trait types
+		traitTypes?: [...string]
+		// +usage=Select resources by resource types (like Deployment)
+		resourceTypes?: [...string]
+		// +usage=Select resources by their names
+		resourceNames?: [...string]
+		// +usage=Select resources by their namespaces
+		namespaces?: [...string]
+		// +usage=Select resources by the clusters they are dispatched to, the control plane is local
+		clusters?: [...string]
+		// +usage=Select resources by their API groups, the core group is core
+		apiGroups?: [...string]
+		// +usage=Select resources by their API versions (like apps/v1)
+		apiVersions?: [...string]
+		// +usage=Select resources by the labels of the rendered resources
+		labelSelector?: {
+			matchLabels?: [string]: string
+			matchExpressions?: [...{
+				key:      string
+				operator: "In" | "NotIn" | "Exists" | "DoesNotExist"
+				values?: [...string]
+			}]
+		}
+		// +usage=Specify how to combine the conditions, or matches any of the conditions while and matches all of them
+		operator?: "or" | "and"
+	}
+
+	parameter: {
+		// +usage=Specify how to handle the drift, correct re-applies the desired state, report only records the drift while alert records the drift with warning events
+		mode: *"correct" | "report" | "alert"
+		// +usage=Specify the modes at resource level, the first matched rule is used
+		rules?: [...#DriftDetectionPolicyRule]
+	}
+}
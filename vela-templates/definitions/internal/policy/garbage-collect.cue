@@ -9,13 +9,23 @@
 template: {
 	#GarbageCollectPolicyRule: {
 		// +usage=Specify how to select the targets of the rule
-		selector: [...#ResourcePolicyRuleSelector]
+		selector: #ResourcePolicyRuleSelector
 		// +usage=Specify the strategy for target resource to recycle
 		strategy: *"onAppUpdate" | "onAppDelete" | "never"
 	}
 
 	#ResourcePolicyRuleSelector: {
-		// +usage=Select resources by component names
+		#ResourcePolicyRuleSelectorTerm
+		// +usage=Select resources matching all the selectors
+		allOf?: [...#ResourcePolicyRuleSelectorTerm]
+		// +usage=Select resources matching any of the selectors
+		anyOf?: [...#ResourcePolicyRuleSelectorTerm]
+		// +usage=Exclude resources matching the selector
+		not?: #ResourcePolicyRuleSelectorTerm
+	}
+
+	#ResourcePolicyRuleSelectorTerm: {
+		// +usage=Select resources by component names, the values support wildcards like web-* and exclusion like !web-canary
 		componentNames?: [...string]
 		// +usage=Select resources by component types
 		componentTypes?: [...string]
@@ -27,6 +37,25 @@ template: {
 		resourceTypes?: [...string]
 		// +usage=Select resources by their names
 		resourceNames?: [...string]
+		// +usage=Select resources by their namespaces
+		namespaces?: [...string]
+		// +usage=Select resources by the clusters they are dispatched to, the control plane is local
+		clusters?: [...string]
+		// +usage=Select resources by their API groups, the core group is core
+		apiGroups?: [...string]
+		// +usage=Select resources by their API versions (like apps/v1)
+		apiVersions?: [...string]
+		// +usage=Select resources by the labels of the rendered resources
+		labelSelector?: {
+			matchLabels?: [string]: string
+			matchExpressions?: [...{
+				key:      string
+				operator: "In" | "NotIn" | "Exists" | "DoesNotExist"
+				values?: [...string]
+			}]
+		}
+		// +usage=Specify how to combine the conditions, or matches any of the conditions while and matches all of them
+		operator?: "or" | "and"
 	}
 
 	parameter: {
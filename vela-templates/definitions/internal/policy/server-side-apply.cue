@@ -0,0 +1,69 @@
+"server-side-apply": {
+	annotations: {}
+	description: "Apply resources by the server-side apply with the field manager instead of the client-side three way merge."
+	labels: {}
+	attributes: {}
+	type: "policy"
+}
+
+template: {
+	#ServerSideApplyPolicyRule: {
+		// +usage=Specify how to select the targets of the rule
+		selector: #ResourcePolicyRuleSelector
+	}
+
+	#ResourcePolicyRuleSelector: {
+		#ResourcePolicyRuleSelectorTerm
+		// +usage=Select resources matching all the selectors
+		allOf?: [...#ResourcePolicyRuleSelectorTerm]
+		// +usage=Select resources matching any of the selectors
+		anyOf?: [...#ResourcePolicyRuleSelectorTerm]
+		// +usage=Exclude resources matching the selector
+		not?: #ResourcePolicyRuleSelectorTerm
+	}
+
+	#ResourcePolicyRuleSelectorTerm: {
+		// +usage=Select resources by component names, the values support wildcards like web-* and exclusion like !web-canary
+		componentNames?: [...string]
+		// +usage=Select resources by component types
+		componentTypes?: [...string]
+		// +usage=Select resources by oamTypes (COMPONENT or TRAIT)
+		oamTypes?: [...string]
+		// +usage=Select resources by trait types
+		traitTypes?: [...string]
+		// +usage=Select resources by resource types (like Deployment)
+		resourceTypes?: [...string]
+		// +usage=Select resources by their names
+		resourceNames?: [...string]
+		// +usage=Select resources by their namespaces
+		namespaces?: [...string]
+		// +usage=Select resources by the clusters they are dispatched to, the control plane is local
+		clusters?: [...string]
+		// +usage=Select resources by their API groups, the core group is core
+		apiGroups?: [...string]
+		// +usage=Select resources by their API versions (like apps/v1)
+		apiVersions?: [...string]
+		// +usage=Select resources by the labels of the rendered resources
+		labelSelector?: {
+			matchLabels?: [string]: string
+			matchExpressions?: [...{
+				key:      string
+				operator: "In" | "NotIn" | "Exists" | "DoesNotExist"
+				values?: [...string]
+			}]
+		}
+		// +usage=Specify how to combine the conditions, or matches any of the conditions while and matches all of them
+		operator?: "or" | "and"
+	}
+
+	parameter: {
+		// +usage=Whether to enable server-side apply for the application
+		enable: *false | bool
+		// +usage=Specify the field manager of the server-side apply
+		fieldManager?: string
+		// +usage=Specify how to handle the conflicts with other field managers, force takes the ownership of the conflicting fields while fail aborts the apply
+		conflictPolicy: *"force" | "fail"
+		// +usage=Specify the rules to select the resources to apply by the server-side apply, all resources are selected if not set
+		rules?: [...#ServerSideApplyPolicyRule]
+	}
+}
@@ -0,0 +1,18 @@
+"approval": {
+	type: "workflow-step"
+	annotations: {}
+	labels: {}
+	description: "Suspend the current workflow until it's approved by the approvers, it can be approved by 'vela workflow resume' and rejected by 'vela workflow reject' command."
+}
+template: {
+	parameter: {
+		// +usage=Specify the users who can approve the step, the service accounts are represented as "system:serviceaccount:<namespace>:<name>"
+		approvers?: [...string]
+		// +usage=Specify the groups whose members can approve the step
+		groups?: [...string]
+		// +usage=Specify the number of distinct approvers required to approve the step
+		quorum: *1 | int
+		// +usage=Specify the duration such as "24h" or the absolute time in RFC3339 format, the step fails if it's not approved before the expiration
+		expiration?: string
+	}
+}
@@ -0,0 +1,91 @@
+import (
+	"vela/op"
+)
+
+"git-commit": {
+	type: "workflow-step"
+	annotations: {}
+	labels: {}
+	description: "Render the components of the application and commit the manifests to the git repository."
+}
+template: {
+	load: op.#Load @step(1)
+
+	_selected: {
+		if parameter.components != _|_ {
+			for c in parameter.components {"\(c)": true}
+		}
+		if parameter.components == _|_ {
+			for name, c in load.value {"\(name)": true}
+		}
+	}
+
+	render: op.#Steps & {
+		for name, c in load.value {
+			if _selected[name] != _|_ {
+				"\(name)": op.#RenderComponent & {
+					value: c
+					output: {...}
+					outputs: {...}
+				}
+			}
+		}
+	} @step(2)
+
+	clone: op.#GitClone & {
+		url:    parameter.url
+		branch: parameter.branch
+		if parameter.secretRef != _|_ {
+			secretRef: parameter.secretRef
+		}
+	} @step(3)
+
+	write: op.#GitWriteFiles & {
+		path:  clone.path
+		dir:   parameter.path
+		clean: true
+		files: [ for compName, r in render if _selected[compName] != _|_ {
+			name:   "\(compName).yaml"
+			values: [r.output] + [ for o in r.outputs {o}]
+		}]
+	} @step(4)
+
+	commit: op.#GitCommit & {
+		path:    clone.path
+		message: parameter.message
+		author:  parameter.author
+	} @step(5)
+
+	push: op.#GitPush & {
+		path:   clone.path
+		branch: parameter.branch
+		if parameter.secretRef != _|_ {
+			secretRef: parameter.secretRef
+		}
+	} @step(6)
+
+	parameter: {
+		// +usage=Specify the url of the git repository
+		url: string
+		// +usage=Specify the branch to commit to
+		branch: *"main" | string
+		// +usage=Specify the directory in the repository to write the manifests, the existing files in the directory are replaced. Use "." to replace all the files in the repository
+		path: string
+		// +usage=Specify the names of the components to commit, all the components are committed if not set
+		components?: [...string]
+		// +usage=Specify the secret that contains the credentials of the repository
+		secretRef?: {
+			// +usage=Specify the name of the secret
+			name: string
+			// +usage=Specify the namespace of the secret, the namespace of the application is used if not set. Other namespaces require the application to run with an identity
+			namespace?: string
+		}
+		// +usage=Specify the template of the commit message, the fields are AppName, Namespace and Files
+		message: *"Update manifests of application {{.Namespace}}/{{.AppName}}" | string
+		// +usage=Specify the author of the commit
+		author: {
+			name:  *"KubeVela" | string
+			email: *"kubevela@kubevela.io" | string
+		}
+	}
+}
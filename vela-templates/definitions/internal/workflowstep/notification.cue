@@ -1,17 +1,79 @@
 import (
 	"vela/op"
 	"encoding/base64"
+	"encoding/json"
+	"strings"
 )
 
 "notification": {
 	type: "workflow-step"
 	annotations: {}
 	labels: {}
-	description: "Send notifications to Email, DingTalk, Slack, Lark or webhook in your workflow."
+	description: "Send notifications to Email, DingTalk, Slack, Lark, Microsoft Teams or webhook in your workflow."
 }
 template: {
 
 	parameter: {
+		// +usage=Generate the links to approve or reject the suspended workflow, refer to the links with ${approve} and ${reject} in the messages
+		approval?: {
+			// +usage=The external address of the apiserver that receives the callbacks, such as https://velaux.example.com
+			callbackURL: string
+			// +usage=The duration the links are valid for, the links never expire if empty
+			expiration: *"24h" | string
+		}
+		// +usage=Please fulfill its url and message if you want to send Microsoft Teams messages
+		teams?: {
+			// +usage=Specify the the teams incoming webhook url, you can either sepcify it in value or use secretRef
+			url: {
+				// +usage=the url address content in string
+				value: string
+			} | {
+				secretRef: {
+					// +usage=name is the name of the secret
+					name: string
+					// +usage=key is the key in the secret
+					key: string
+				}
+			}
+			// +usage=Specify the message that you want to sent, the approve and reject buttons are added if approval is set
+			message: {
+				// +usage=Specify the title of the message
+				title?: string
+				// +usage=Specify the message text in markdown
+				text: string
+				// +usage=Specify the theme color in hex, such as 0076D7
+				themeColor?: string
+			}
+		}
+		// +usage=Please fulfill its url, signing key and message if you want to send signed webhook messages
+		webhook?: {
+			// +usage=Specify the the webhook url, you can either sepcify it in value or use secretRef
+			url: {
+				// +usage=the url address content in string
+				value: string
+			} | {
+				secretRef: {
+					// +usage=name is the name of the secret
+					name: string
+					// +usage=key is the key in the secret
+					key: string
+				}
+			}
+			// +usage=Specify the key to sign the payload, the signature is in the X-Vela-Signature header. You can either sepcify it in value or use secretRef
+			signingKey: {
+				// +usage=the key content in string
+				value: string
+			} | {
+				secretRef: {
+					// +usage=name is the name of the secret
+					name: string
+					// +usage=key is the key in the secret
+					key: string
+				}
+			}
+			// +usage=Specify the message that you want to sent, it's sent with the application, the step and the approval links in the payload
+			message: {...}
+		}
 		// +usage=Please fulfill its url and message if you want to send Lark messages
 		lark?: {
 			// +usage=Specify the the lark url, you can either sepcify it in value or use secretRef
@@ -207,12 +269,141 @@ template: {
 		url?:         string
 	}
 
+	approvalLinks: op.#Steps & {
+		if parameter.approval != _|_ {
+			generate: op.#ApprovalLinks & {
+				callbackURL: parameter.approval.callbackURL
+				stepID:      context.stepSessionID
+				expiration: parameter.approval.expiration
+			}
+		}
+	}
+
+	// replace the placeholders of the approval links in the messages
+	#render: {
+		input: string
+		if parameter.approval == _|_ {
+			output: input
+		}
+		if parameter.approval != _|_ {
+			output: strings.Replace(strings.Replace(input, "${approve}", approvalLinks.generate.links.approve, -1), "${reject}", approvalLinks.generate.links.reject, -1)
+		}
+	}
+
+	teams: op.#Steps & {
+		if parameter.teams != _|_ {
+			teamsMessage: {
+				text: (#render & {input: parameter.teams.message.text}).output
+				if parameter.teams.message.title != _|_ {
+					title:   parameter.teams.message.title
+					summary: parameter.teams.message.title
+				}
+				if parameter.teams.message.title == _|_ {
+					summary: text
+				}
+				if parameter.teams.message.themeColor != _|_ {
+					themeColor: parameter.teams.message.themeColor
+				}
+				if parameter.approval != _|_ {
+					potentialAction: [{
+						name: "Approve"
+						targets: [{uri: approvalLinks.generate.links.approve}]
+					}, {
+						name: "Reject"
+						targets: [{uri: approvalLinks.generate.links.reject}]
+					}]
+				}
+			}
+			if parameter.teams.url.value != _|_ {
+				teams1: op.#Teams & {
+					message:  teamsMessage
+					teamsUrl: parameter.teams.url.value
+				}
+			}
+			if parameter.teams.url.secretRef != _|_ && parameter.teams.url.value == _|_ {
+				read: op.#Read & {
+					value: {
+						apiVersion: "v1"
+						kind:       "Secret"
+						metadata: {
+							name:      parameter.teams.url.secretRef.name
+							namespace: context.namespace
+						}
+					}
+				}
+
+				decoded:     base64.Decode(null, read.value.data[parameter.teams.url.secretRef.key])
+				stringValue: op.#ConvertString & {bt: decoded}
+				teams2:      op.#Teams & {
+					message:  teamsMessage
+					teamsUrl: stringValue.str
+				}
+			}
+		}
+	}
+
+	webhook: op.#Steps & {
+		if parameter.webhook != _|_ {
+			targetUrl:     string
+			signingSecret: string
+			if parameter.webhook.url.value != _|_ {
+				targetUrl: parameter.webhook.url.value
+			}
+			if parameter.webhook.url.secretRef != _|_ && parameter.webhook.url.value == _|_ {
+				readURL: op.#Read & {
+					value: {
+						apiVersion: "v1"
+						kind:       "Secret"
+						metadata: {
+							name:      parameter.webhook.url.secretRef.name
+							namespace: context.namespace
+						}
+					}
+				}
+				urlValue:  op.#ConvertString & {bt: base64.Decode(null, readURL.value.data[parameter.webhook.url.secretRef.key])}
+				targetUrl: urlValue.str
+			}
+			if parameter.webhook.signingKey.value != _|_ {
+				signingSecret: parameter.webhook.signingKey.value
+			}
+			if parameter.webhook.signingKey.secretRef != _|_ && parameter.webhook.signingKey.value == _|_ {
+				readKey: op.#Read & {
+					value: {
+						apiVersion: "v1"
+						kind:       "Secret"
+						metadata: {
+							name:      parameter.webhook.signingKey.secretRef.name
+							namespace: context.namespace
+						}
+					}
+				}
+				keyValue:      op.#ConvertString & {bt: base64.Decode(null, readKey.value.data[parameter.webhook.signingKey.secretRef.key])}
+				signingSecret: keyValue.str
+			}
+			send: op.#SignedWebhook & {
+				message: {
+					application: {
+						name:      context.name
+						namespace: context.namespace
+					}
+					stepID:    context.stepSessionID
+					"message": parameter.webhook.message
+					if parameter.approval != _|_ {
+						approval: approvalLinks.generate.links
+					}
+				}
+				webhookUrl: targetUrl
+				signingKey: signingSecret
+			}
+		}
+	}
+
 	// send webhook notification
 	ding: op.#Steps & {
 		if parameter.dingding != _|_ {
 			if parameter.dingding.url.value != _|_ {
 				ding1: op.#DingTalk & {
-					message: parameter.dingding.message
+					message: json.Unmarshal((#render & {input: json.Marshal(parameter.dingding.message)}).output)
 					dingUrl: parameter.dingding.url.value
 				}
 			}
@@ -231,7 +422,7 @@ template: {
 				decoded:     base64.Decode(null, read.value.data[parameter.dingding.url.secretRef.key])
 				stringValue: op.#ConvertString & {bt: decoded}
 				ding2:       op.#DingTalk & {
-					message: parameter.dingding.message
+					message: json.Unmarshal((#render & {input: json.Marshal(parameter.dingding.message)}).output)
 					dingUrl: stringValue.str
 				}
 			}
@@ -242,7 +433,7 @@ template: {
 		if parameter.lark != _|_ {
 			if parameter.lark.url.value != _|_ {
 				lark1: op.#Lark & {
-					message: parameter.lark.message
+					message: json.Unmarshal((#render & {input: json.Marshal(parameter.lark.message)}).output)
 					larkUrl: parameter.lark.url.value
 				}
 			}
@@ -261,7 +452,7 @@ template: {
 				decoded:     base64.Decode(null, read.value.data[parameter.lark.url.secretRef.key])
 				stringValue: op.#ConvertString & {bt: decoded}
 				lark2:       op.#Lark & {
-					message: parameter.lark.message
+					message: json.Unmarshal((#render & {input: json.Marshal(parameter.lark.message)}).output)
 					larkUrl: stringValue.str
 				}
 			}
@@ -272,7 +463,7 @@ template: {
 		if parameter.slack != _|_ {
 			if parameter.slack.url.value != _|_ {
 				slack1: op.#Slack & {
-					message:  parameter.slack.message
+					message:  json.Unmarshal((#render & {input: json.Marshal(parameter.slack.message)}).output)
 					slackUrl: parameter.slack.url.value
 				}
 			}
@@ -291,7 +482,7 @@ template: {
 				decoded:     base64.Decode(null, read.value.data[parameter.slack.url.secretRef.key])
 				stringValue: op.#ConvertString & {bt: decoded}
 				slack2:      op.#Slack & {
-					message:  parameter.slack.message
+					message:  json.Unmarshal((#render & {input: json.Marshal(parameter.slack.message)}).output)
 					slackUrl: stringValue.str
 				}
 			}
@@ -312,7 +503,10 @@ template: {
 						port:     parameter.email.from.port
 					}
 					to:      parameter.email.to
-					content: parameter.email.content
+					content: {
+						subject: parameter.email.content.subject
+						body:    (#render & {input: parameter.email.content.body}).output
+					}
 				}
 			}
 
@@ -341,7 +535,10 @@ template: {
 						port:     parameter.email.from.port
 					}
 					to:      parameter.email.to
-					content: parameter.email.content
+					content: {
+						subject: parameter.email.content.subject
+						body:    (#render & {input: parameter.email.content.body}).output
+					}
 				}
 			}
 		}
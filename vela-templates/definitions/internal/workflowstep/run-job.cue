@@ -0,0 +1,64 @@
+import (
+	"vela/op"
+)
+
+"run-job": {
+	type: "workflow-step"
+	annotations: {}
+	labels: {}
+	description: "Run a container task as a Job in the cluster and wait for it to complete."
+}
+template: {
+	job: op.#RunJob & {
+		name:    parameter.name
+		cluster: parameter.cluster
+		image:   parameter.image
+		if parameter.command != _|_ {
+			command: parameter.command
+		}
+		if parameter.args != _|_ {
+			args: parameter.args
+		}
+		if parameter.env != _|_ {
+			env: parameter.env
+		}
+		if parameter.secrets != _|_ {
+			secrets: parameter.secrets
+		}
+		backoffLimit:            parameter.backoffLimit
+		ttlSecondsAfterFinished: parameter.ttlSecondsAfterFinished
+		if parameter.timeout != _|_ {
+			activeDeadlineSeconds: parameter.timeout
+		}
+	}
+
+	parameter: {
+		// +usage=Specify the name of the job, the job is rerun only if its spec is changed
+		name: string
+		// +usage=Specify the cluster to run the job, the local cluster by default
+		cluster: *"" | string
+		// +usage=Specify the image of the job
+		image: string
+		// +usage=Specify the command of the container
+		command?: [...string]
+		// +usage=Specify the arguments of the command
+		args?: [...string]
+		// +usage=Specify the environment variables
+		env?: [...{
+			name:   string
+			value?: string
+			valueFrom?: secretKeyRef?: {
+				name: string
+				key:  string
+			}
+		}]
+		// +usage=Specify the secrets to load as the environment variables
+		secrets?: [...string]
+		// +usage=Specify the number of retries before the job is failed
+		backoffLimit: *0 | int
+		// +usage=Specify the timeout in seconds of the job
+		timeout?: int
+		// +usage=Specify the seconds to keep the job after it has finished
+		ttlSecondsAfterFinished: *600 | int
+	}
+}
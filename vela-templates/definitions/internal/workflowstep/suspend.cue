@@ -8,5 +8,18 @@ template: {
 	parameter: {
 		// +usage=Specify the wait duration time to resume workflow such as "30s", "1min" or "2m15s"
 		duration?: string
+		// +usage=Specify the absolute time in RFC3339 format to resume workflow such as "2022-07-01T02:00:00+02:00"
+		until?: string
+		// +usage=Specify the business calendar window to resume workflow, the workflow is resumed at the next opening of the window
+		window?: {
+			// +usage=Specify the days of week when the window opens such as "Mon" or "Monday", "weekdays" and "weekends" are also supported
+			days?: [...string]
+			// +usage=Specify the opening time of the window such as "02:00"
+			start: string
+			// +usage=Specify the closing time of the window such as "04:00"
+			end: string
+			// +usage=Specify the time zone of the window such as "Europe/Berlin", the default is UTC
+			timeZone?: string
+		}
 	}
 }